@@ -17,6 +17,10 @@ func main() {
 		log.Println("No .env file found")
 	}
 
+	if os.Getenv("FETCH_SETTINGS_KEY") == "" {
+		log.Println("WARNING: FETCH_SETTINGS_KEY is not set, projects cannot store fetch credentials and stored ones are unavailable")
+	}
+
 	// Connect to database
 	database.Connect()
 
@@ -0,0 +1,216 @@
+// Package a11ytree builds the accessibility tree assistive technologies are
+// exposed to from a parsed HTML document.
+package a11ytree
+
+import (
+	"strconv"
+	"strings"
+
+	"tokubetsu/internal/accname"
+	"tokubetsu/internal/aria"
+	"tokubetsu/internal/dom"
+
+	"golang.org/x/net/html"
+)
+
+// TextRole is the role given to runs of text
+const TextRole = "text"
+
+// Node is a node of the accessibility tree
+type Node struct {
+	Role        string            `json:"role"`
+	Name        string            `json:"name,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Tag         string            `json:"tag,omitempty"`
+	Selector    string            `json:"selector,omitempty"`
+	States      map[string]string `json:"states,omitempty"`
+	Focusable   bool              `json:"focusable,omitempty"`
+	// Hidden nodes are excluded from the tree; they are kept as leaves so
+	// that hidden content can be told apart from missing content
+	Hidden   bool    `json:"hidden,omitempty"`
+	Children []*Node `json:"children,omitempty"`
+}
+
+// Options control how the tree is built
+type Options struct {
+	// Hidden decides whether an element is excluded from the tree. When nil,
+	// only aria-hidden, the hidden attribute and inline styles are considered.
+	Hidden func(*html.Node) bool
+	// Names computes accessible names. When nil, one is created for the document.
+	Names *accname.Computer
+	// Selector returns a CSS selector for an element, if set
+	Selector func(*html.Node) string
+}
+
+// nonRendered elements never contribute to the tree, not even as hidden nodes
+var nonRendered = map[string]bool{
+	"head": true, "script": true, "style": true, "template": true,
+	"noscript": true, "meta": true, "link": true, "title": true, "base": true,
+}
+
+// ariaExcluded are aria-* attributes that are exposed as relations or names
+// rather than as states
+var ariaExcluded = map[string]bool{
+	"aria-label":       true,
+	"aria-labelledby":  true,
+	"aria-describedby": true,
+	"aria-description": true,
+	"aria-hidden":      true,
+}
+
+type builder struct {
+	opts Options
+}
+
+// Build returns the accessibility tree of a document. The root node has the
+// document role and the page title as its name.
+func Build(doc *html.Node, opts Options) *Node {
+	if opts.Hidden == nil {
+		opts.Hidden = aria.Hidden
+	}
+	if opts.Names == nil {
+		opts.Names = accname.New(doc, opts.Hidden)
+	}
+	b := &builder{opts: opts}
+
+	root := &Node{Role: "document"}
+	if title := dom.Find(doc, "title"); title != nil {
+		root.Name = dom.Text(title)
+	}
+	if body := dom.Find(doc, "body"); body != nil {
+		root.Children = b.children(body)
+	} else {
+		root.Children = b.children(doc)
+	}
+	return root
+}
+
+// children builds the nodes for the children of n, lifting the content of
+// ignored elements into their parent
+func (b *builder) children(n *html.Node) []*Node {
+	var nodes []*Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		nodes = append(nodes, b.build(c)...)
+	}
+	return nodes
+}
+
+func (b *builder) build(n *html.Node) []*Node {
+	switch n.Type {
+	case html.TextNode:
+		text := strings.Join(strings.Fields(n.Data), " ")
+		if text == "" {
+			return nil
+		}
+		return []*Node{{Role: TextRole, Name: text}}
+	case html.ElementNode:
+	default:
+		return nil
+	}
+
+	if nonRendered[n.Data] {
+		return nil
+	}
+
+	role := aria.Role(n)
+	if b.opts.Hidden(n) {
+		return []*Node{b.node(n, role, true)}
+	}
+
+	// Elements without semantics are ignored unless they can take focus or
+	// have been given a name
+	if role == "" || role == "generic" || aria.IsPresentational(n) {
+		if !aria.Focusable(n) && !dom.HasAttr(n, "aria-label") && !dom.HasAttr(n, "aria-labelledby") {
+			return b.children(n)
+		}
+		if role == "" || aria.IsPresentational(n) {
+			role = "generic"
+		}
+	}
+
+	node := b.node(n, role, false)
+	// Images and other atomic roles expose their name, not their content
+	if !aria.ChildrenPresentational(role) {
+		node.Children = b.children(n)
+	}
+	return []*Node{node}
+}
+
+func (b *builder) node(n *html.Node, role string, hidden bool) *Node {
+	node := &Node{
+		Role:   role,
+		Tag:    n.Data,
+		Hidden: hidden,
+	}
+	if b.opts.Selector != nil {
+		node.Selector = b.opts.Selector(n)
+	}
+	if hidden {
+		return node
+	}
+
+	node.Name = b.opts.Names.Name(n)
+	node.Description = b.opts.Names.Description(n)
+	node.Focusable = aria.Focusable(n)
+	if states := states(n, role); len(states) > 0 {
+		node.States = states
+	}
+	return node
+}
+
+// states collects the ARIA states and properties of an element, combining
+// aria-* attributes with the equivalent native HTML semantics
+func states(n *html.Node, role string) map[string]string {
+	states := make(map[string]string)
+	for _, attr := range n.Attr {
+		if strings.HasPrefix(attr.Key, "aria-") && !ariaExcluded[attr.Key] {
+			states[strings.TrimPrefix(attr.Key, "aria-")] = strings.TrimSpace(attr.Val)
+		}
+	}
+
+	if aria.Disabled(n) {
+		states["disabled"] = "true"
+	}
+	if dom.IsElement(n, "input", "select", "textarea") && dom.HasAttr(n, "required") {
+		states["required"] = "true"
+	}
+	if dom.IsElement(n, "input", "textarea") && dom.HasAttr(n, "readonly") {
+		states["readonly"] = "true"
+	}
+
+	switch role {
+	case "checkbox", "radio", "switch", "menuitemcheckbox", "menuitemradio":
+		if _, ok := states["checked"]; !ok || dom.IsElement(n, "input") {
+			states["checked"] = strconv.FormatBool(dom.IsElement(n, "input") && dom.HasAttr(n, "checked"))
+		}
+	case "option":
+		if n.Data == "option" {
+			states["selected"] = strconv.FormatBool(dom.HasAttr(n, "selected"))
+		}
+	case "heading":
+		if _, ok := states["level"]; !ok && len(n.Data) == 2 && n.Data[0] == 'h' {
+			states["level"] = n.Data[1:]
+		}
+	case "listbox":
+		if n.Data == "select" && dom.HasAttr(n, "multiple") {
+			states["multiselectable"] = "true"
+		}
+	case "slider", "spinbutton", "progressbar", "meter":
+		if n.Data == "input" || n.Data == "progress" || n.Data == "meter" {
+			if value, ok := dom.LookupAttr(n, "value"); ok {
+				states["valuenow"] = value
+			}
+			if min, ok := dom.LookupAttr(n, "min"); ok {
+				states["valuemin"] = min
+			}
+			if max, ok := dom.LookupAttr(n, "max"); ok {
+				states["valuemax"] = max
+			}
+		}
+	}
+
+	if dom.IsElement(n, "details") {
+		states["expanded"] = strconv.FormatBool(dom.HasAttr(n, "open"))
+	}
+	return states
+}
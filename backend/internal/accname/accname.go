@@ -0,0 +1,402 @@
+// Package accname computes accessible names and descriptions following the
+// W3C Accessible Name and Description Computation 1.2 and the HTML-AAM
+// host language rules.
+package accname
+
+import (
+	"strings"
+
+	"tokubetsu/internal/aria"
+	"tokubetsu/internal/dom"
+
+	"golang.org/x/net/html"
+)
+
+// Computer computes names and descriptions for the elements of one document.
+// It is not safe for concurrent use.
+type Computer struct {
+	ids    map[string]*html.Node
+	labels map[*html.Node][]*html.Node
+	hidden func(*html.Node) bool
+}
+
+// New indexes a document for name computation. hidden decides whether an
+// element is excluded from the accessibility tree; when nil, only
+// aria-hidden, the hidden attribute and inline styles are considered.
+func New(doc *html.Node, hidden func(*html.Node) bool) *Computer {
+	if hidden == nil {
+		hidden = aria.Hidden
+	}
+	c := &Computer{
+		ids:    make(map[string]*html.Node),
+		labels: make(map[*html.Node][]*html.Node),
+		hidden: hidden,
+	}
+
+	var labels []*html.Node
+	dom.Walk(doc, func(n *html.Node) bool {
+		if n.Type == html.ElementNode {
+			if id := dom.Attr(n, "id"); id != "" {
+				if _, exists := c.ids[id]; !exists {
+					c.ids[id] = n
+				}
+			}
+			if n.Data == "label" {
+				labels = append(labels, n)
+			}
+		}
+		return true
+	})
+
+	for _, label := range labels {
+		var control *html.Node
+		if id, ok := dom.LookupAttr(label, "for"); ok {
+			control = c.ids[id]
+		} else {
+			for _, e := range dom.Elements(label) {
+				if isLabelable(e) {
+					control = e
+					break
+				}
+			}
+		}
+		if control != nil && isLabelable(control) {
+			c.labels[control] = append(c.labels[control], label)
+		}
+	}
+	return c
+}
+
+// state is the traversal state of one name computation
+type state struct {
+	visited map[*html.Node]bool
+	// root is the element whose name is being computed
+	root *html.Node
+	// labelledBy is set while following aria-labelledby or aria-describedby
+	labelledBy bool
+	// recursive is set while computing text from descendants or labels
+	recursive bool
+	// includeHidden is set below a hidden node referenced directly by
+	// aria-labelledby, whose hidden content then contributes to the name
+	includeHidden bool
+}
+
+// Name returns the accessible name of an element, with whitespace collapsed
+func (c *Computer) Name(n *html.Node) string {
+	st := &state{visited: make(map[*html.Node]bool), root: n}
+	return collapse(c.compute(n, st))
+}
+
+// Description returns the accessible description of an element
+func (c *Computer) Description(n *html.Node) string {
+	if refs := c.references(n, "aria-describedby"); len(refs) > 0 {
+		var parts []string
+		for _, ref := range refs {
+			st := &state{visited: map[*html.Node]bool{n: true}, root: n, labelledBy: true, recursive: true}
+			parts = append(parts, c.referenced(ref, st))
+		}
+		if desc := collapse(strings.Join(parts, " ")); desc != "" {
+			return desc
+		}
+	}
+	if desc := collapse(dom.Attr(n, "aria-description")); desc != "" {
+		return desc
+	}
+	// The title is only a description when it did not already supply the name
+	if title := collapse(dom.Attr(n, "title")); title != "" && title != c.Name(n) {
+		return title
+	}
+	return ""
+}
+
+// Labels returns the label elements associated with a form control
+func (c *Computer) Labels(n *html.Node) []*html.Node {
+	return c.labels[n]
+}
+
+// Lookup returns the element with the given id, if any
+func (c *Computer) Lookup(id string) *html.Node {
+	return c.ids[id]
+}
+
+// references resolves an ID reference list attribute
+func (c *Computer) references(n *html.Node, attr string) []*html.Node {
+	var refs []*html.Node
+	for _, id := range strings.Fields(dom.Attr(n, attr)) {
+		if ref := c.ids[id]; ref != nil {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// referenced computes the text of a node referenced by aria-labelledby or
+// aria-describedby. Hidden referenced nodes still contribute their content.
+func (c *Computer) referenced(n *html.Node, st *state) string {
+	sub := *st
+	sub.includeHidden = c.hidden(n)
+	return c.compute(n, &sub)
+}
+
+func (c *Computer) compute(n *html.Node, st *state) string {
+	switch n.Type {
+	case html.TextNode:
+		return n.Data
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	if st.visited[n] {
+		return ""
+	}
+	st.visited[n] = true
+
+	// Step 2A: hidden nodes are skipped unless reached through aria-labelledby
+	if !st.includeHidden && c.hidden(n) {
+		return ""
+	}
+
+	// Step 2B: aria-labelledby, unless already following a reference
+	if !st.labelledBy {
+		if refs := c.references(n, "aria-labelledby"); len(refs) > 0 {
+			sub := *st
+			sub.labelledBy = true
+			sub.recursive = true
+			var parts []string
+			for _, ref := range refs {
+				parts = append(parts, c.referenced(ref, &sub))
+			}
+			if name := strings.TrimSpace(strings.Join(parts, " ")); name != "" {
+				return name
+			}
+		}
+	}
+
+	role := aria.Role(n)
+
+	// Step 2C: controls embedded in another element's name contribute their value
+	if st.recursive && n != st.root {
+		if value, ok := c.embeddedValue(n, role); ok {
+			return value
+		}
+	}
+
+	// Step 2D: aria-label
+	if label := strings.TrimSpace(dom.Attr(n, "aria-label")); label != "" {
+		return label
+	}
+
+	// Step 2E: host language label
+	if !aria.IsPresentational(n) {
+		if name := c.native(n, st); strings.TrimSpace(name) != "" {
+			return name
+		}
+	}
+
+	// Step 2F: name from content
+	if aria.NameFromContent(role) || st.recursive || dom.IsElement(n, "label", "legend", "caption", "figcaption") {
+		if text := c.content(n, st); strings.TrimSpace(text) != "" {
+			return text
+		}
+	}
+
+	// Step 2I: tooltip attribute, then placeholder for text fields
+	if title := strings.TrimSpace(dom.Attr(n, "title")); title != "" {
+		return title
+	}
+	if n == st.root && (role == "textbox" || role == "searchbox" || role == "combobox") {
+		if placeholder := strings.TrimSpace(dom.Attr(n, "placeholder")); placeholder != "" {
+			return placeholder
+		}
+		return strings.TrimSpace(dom.Attr(n, "aria-placeholder"))
+	}
+	return ""
+}
+
+// native applies the HTML-AAM name rules for an element
+func (c *Computer) native(n *html.Node, st *state) string {
+	if n.Namespace == "svg" {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if dom.IsElement(child, "title") {
+				return dom.Text(child)
+			}
+		}
+		return ""
+	}
+
+	switch n.Data {
+	case "input":
+		switch strings.ToLower(dom.Attr(n, "type")) {
+		case "button":
+			return dom.Attr(n, "value")
+		case "submit":
+			if value, ok := dom.LookupAttr(n, "value"); ok {
+				return value
+			}
+			return "Submit"
+		case "reset":
+			if value, ok := dom.LookupAttr(n, "value"); ok {
+				return value
+			}
+			return "Reset"
+		case "image":
+			// The "Submit Query" default is not applied so that image buttons
+			// without a text alternative are still reported
+			if alt := dom.Attr(n, "alt"); strings.TrimSpace(alt) != "" {
+				return alt
+			}
+			if name := c.labelText(n, st); strings.TrimSpace(name) != "" {
+				return name
+			}
+			return dom.Attr(n, "value")
+		}
+		return c.labelText(n, st)
+	case "button", "select", "textarea", "meter", "output", "progress":
+		return c.labelText(n, st)
+	case "img", "area":
+		return dom.Attr(n, "alt")
+	case "fieldset":
+		return c.firstChildText(n, "legend", st)
+	case "figure":
+		return c.firstChildText(n, "figcaption", st)
+	case "table":
+		return c.firstChildText(n, "caption", st)
+	case "optgroup":
+		return dom.Attr(n, "label")
+	}
+	return ""
+}
+
+// labelText joins the text of the label elements associated with a control
+func (c *Computer) labelText(n *html.Node, st *state) string {
+	var parts []string
+	for _, label := range c.labels[n] {
+		sub := *st
+		sub.recursive = true
+		parts = append(parts, c.compute(label, &sub))
+	}
+	return strings.Join(parts, " ")
+}
+
+func (c *Computer) firstChildText(n *html.Node, tag string, st *state) string {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if dom.IsElement(child, tag) {
+			sub := *st
+			sub.recursive = true
+			return c.compute(child, &sub)
+		}
+	}
+	return ""
+}
+
+// content concatenates the text computed for each child of an element
+func (c *Computer) content(n *html.Node, st *state) string {
+	sub := *st
+	sub.recursive = true
+
+	var b strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if dom.IsElement(child, "br") {
+			b.WriteString(" ")
+			continue
+		}
+		text := c.compute(child, &sub)
+		if child.Type == html.ElementNode && isBlock(child) {
+			b.WriteString(" ")
+			b.WriteString(text)
+			b.WriteString(" ")
+		} else {
+			b.WriteString(text)
+		}
+	}
+	return b.String()
+}
+
+// embeddedValue returns the value a form control contributes when it is
+// embedded in the label or content of another element
+func (c *Computer) embeddedValue(n *html.Node, role string) (string, bool) {
+	switch role {
+	case "textbox", "searchbox":
+		if n.Data == "textarea" {
+			return dom.Text(n), true
+		}
+		if n.Data == "input" {
+			return dom.Attr(n, "value"), true
+		}
+		return dom.Text(n), true
+	case "combobox", "listbox":
+		if n.Data == "select" {
+			return selectedOptions(n), true
+		}
+		if n.Data == "input" {
+			return dom.Attr(n, "value"), true
+		}
+		return "", false
+	case "slider", "spinbutton", "progressbar", "meter", "scrollbar":
+		if text := dom.Attr(n, "aria-valuetext"); text != "" {
+			return text, true
+		}
+		if now := dom.Attr(n, "aria-valuenow"); now != "" {
+			return now, true
+		}
+		return dom.Attr(n, "value"), true
+	}
+	return "", false
+}
+
+// selectedOptions returns the text of a select's selected options, or of
+// its first option when none is marked selected
+func selectedOptions(n *html.Node) string {
+	var options []*html.Node
+	for _, e := range dom.Elements(n) {
+		if e.Data == "option" {
+			options = append(options, e)
+		}
+	}
+	var selected []string
+	for _, option := range options {
+		if dom.HasAttr(option, "selected") {
+			selected = append(selected, dom.Text(option))
+		}
+	}
+	if len(selected) == 0 && len(options) > 0 && !dom.HasAttr(n, "multiple") {
+		selected = append(selected, dom.Text(options[0]))
+	}
+	return strings.Join(selected, " ")
+}
+
+// isLabelable reports whether an element can be associated with a label
+func isLabelable(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	switch n.Data {
+	case "button", "meter", "output", "progress", "select", "textarea":
+		return true
+	case "input":
+		return !strings.EqualFold(dom.Attr(n, "type"), "hidden")
+	}
+	return false
+}
+
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true,
+	"dd": true, "details": true, "dialog": true, "div": true, "dl": true,
+	"dt": true, "fieldset": true, "figcaption": true, "figure": true,
+	"footer": true, "form": true, "h1": true, "h2": true, "h3": true,
+	"h4": true, "h5": true, "h6": true, "header": true, "hr": true,
+	"li": true, "main": true, "nav": true, "ol": true, "p": true,
+	"pre": true, "section": true, "summary": true, "table": true,
+	"td": true, "th": true, "tr": true, "ul": true,
+}
+
+// isBlock reports whether an element renders as a block by default, so its
+// text is separated from its siblings
+func isBlock(n *html.Node) bool {
+	return blockElements[n.Data]
+}
+
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
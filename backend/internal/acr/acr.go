@@ -0,0 +1,303 @@
+// Package acr builds Accessibility Conformance Reports in the layout of the
+// VPAT 2.x international edition from a compliance report and the standards
+// profile it was measured against
+package acr
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"tokubetsu/internal/models"
+	"tokubetsu/internal/standards"
+	"tokubetsu/internal/wcag"
+)
+
+// VPAT 2.x conformance levels
+const (
+	Supports          = "Supports"
+	PartiallySupports = "Partially Supports"
+	DoesNotSupport    = "Does Not Support"
+	NotApplicable     = "Not Applicable"
+	NotEvaluated      = "Not Evaluated"
+)
+
+// Report is an Accessibility Conformance Report
+type Report struct {
+	Product           string    `json:"product"`
+	Description       string    `json:"description"`
+	URL               string    `json:"url"`
+	Date              time.Time `json:"date"`
+	EvaluationMethods string    `json:"evaluation_methods"`
+	Profile           string    `json:"profile"`           // Standards profile the report was measured against
+	WCAGVersion       string    `json:"wcag_version"`      // WCAG version the profile incorporates
+	Standards         []string  `json:"standards"`         // Applicable standards and guidelines
+	ConformanceLevel  string    `json:"conformance_level"` // Highest WCAG level the report conforms to, or none
+	Tables            []Table   `json:"tables"`
+}
+
+// Table is one conformance table of the report
+type Table struct {
+	Standard string `json:"standard"` // wcag, or the profile of the standard's own tables
+	Title    string `json:"title"`
+	Notes    string `json:"notes,omitempty"`
+	Rows     []Row  `json:"rows"`
+}
+
+// Row is the conformance of a single criterion. ID is the key its remarks
+// are stored under.
+type Row struct {
+	ID            string   `json:"id"`
+	Criterion     string   `json:"criterion"`
+	AlsoAppliesTo []string `json:"also_applies_to,omitempty"`
+	Conformance   string   `json:"conformance"`
+	Remarks       string   `json:"remarks"`
+	Edited        bool     `json:"edited"` // Remarks were written by a reviewer rather than generated
+}
+
+// Build creates the ACR of a report with its criterion results and manual
+// checklist loaded. Its tables list the requirements of the report's
+// standards profile. Notes of audited checks become the default remarks.
+// remarks maps row IDs to remarks entered by a reviewer.
+func Build(project models.Project, report models.ComplianceReport, remarks map[string]string) *Report {
+	profile := standards.Resolve(report.Profile)
+	document := documentOf(profile)
+	acr := &Report{
+		Product:          project.Name,
+		Description:      project.Description,
+		URL:              report.URL,
+		Date:             report.GeneratedAt,
+		Profile:          profile.ID,
+		WCAGVersion:      profile.WCAG,
+		Standards:        document.standards,
+		ConformanceLevel: report.ConformanceLevel,
+		EvaluationMethods: "Automated testing of " + report.URL + " against the requirements of " + profile.Name + ". " +
+			"Criteria marked Not Evaluated could not be decided by automated testing and require manual review.",
+	}
+	if acr.Product == "" {
+		acr.Product = project.Title
+	}
+
+	results := make(map[string]models.CriterionResult, len(report.Results))
+	for _, result := range report.Results {
+		results[result.Criterion] = result
+	}
+	notes := make(map[string]string)
+	for _, check := range report.Checklist {
+		if check.Result != models.ManualPending {
+			notes[check.Criterion] = strings.TrimSpace(check.Notes)
+		}
+	}
+	if len(notes) > 0 {
+		acr.EvaluationMethods = "Automated testing of " + report.URL + " against the requirements of " + profile.Name + ", " +
+			"and a manual audit of the criteria automated testing could not decide. " +
+			"Criteria marked Not Evaluated have not been audited yet."
+	}
+
+	wcagRows := make(map[string]Row)
+	tables := make(map[string]*Table)
+	additions := Table{Standard: profile.ID, Title: document.additions, Notes: "Requirements of the standard beyond the WCAG success criteria."}
+	for _, requirement := range profile.Requirements {
+		key := requirement.Key()
+		result, ok := results[key]
+		if !ok {
+			result = models.CriterionResult{Criterion: key, Status: models.CriterionNotTested}
+		}
+		row := Row{
+			ID:          key,
+			Criterion:   requirement.ID + " " + requirement.Title,
+			Conformance: conformance(result),
+			Remarks:     generatedRemarks(result),
+		}
+		if result.Source == models.SourceManual {
+			row.Remarks = "Evaluated in a manual audit."
+			if notes[key] != "" {
+				row.Remarks = notes[key]
+			}
+		}
+
+		if requirement.Criterion == "" {
+			applyRemarks(&row, remarks)
+			additions.Rows = append(additions.Rows, row)
+			continue
+		}
+		criterion, _ := wcag.Lookup(requirement.Criterion)
+		row.Criterion = fmt.Sprintf("%s %s (Level %s)", criterion.Number, criterion.Title, criterion.Level)
+		if requirement.ID != criterion.Number {
+			row.AlsoAppliesTo = []string{profile.Name + " " + requirement.ID}
+		}
+		applyRemarks(&row, remarks)
+		wcagRows[key] = row
+
+		if tables[criterion.Level] == nil {
+			tables[criterion.Level] = &Table{Standard: "wcag"}
+		}
+		tables[criterion.Level].Rows = append(tables[criterion.Level].Rows, row)
+	}
+
+	for _, level := range []string{wcag.LevelA, wcag.LevelAA, wcag.LevelAAA} {
+		table := tables[level]
+		if table == nil {
+			continue
+		}
+		table.Title = fmt.Sprintf("Table %d: Success Criteria, Level %s", len(acr.Tables)+1, level)
+		table.Notes = fmt.Sprintf("WCAG %s Level %s.", profile.WCAG, level)
+		if len(table.Rows) > 0 && len(table.Rows[0].AlsoAppliesTo) > 0 {
+			table.Notes += " Each criterion applies as the clause of " + profile.Name + " noted under it."
+		}
+		acr.Tables = append(acr.Tables, *table)
+	}
+
+	for _, standard := range functionalStandards {
+		if standard.id != profile.ID {
+			continue
+		}
+		table := Table{Standard: standard.id, Title: standard.title, Notes: standard.notes}
+		for _, fpc := range standard.criteria {
+			numbers := supporting(fpc.wcag, wcagRows)
+			row := Row{
+				ID:          fpc.id,
+				Criterion:   fpc.id + " " + fpc.title,
+				Conformance: aggregate(numbers, wcagRows),
+				Remarks:     aggregateRemarks(numbers, wcagRows),
+			}
+			applyRemarks(&row, remarks)
+			table.Rows = append(table.Rows, row)
+		}
+		acr.Tables = append(acr.Tables, table)
+	}
+	if len(additions.Rows) > 0 {
+		acr.Tables = append(acr.Tables, additions)
+	}
+	return acr
+}
+
+// ValidRow reports whether id is the ID of a row the ACR of a profile has,
+// so remarks can be entered for it
+func ValidRow(profile *standards.Profile, id string) bool {
+	if _, ok := profile.Requirement(id); ok {
+		return true
+	}
+	for _, standard := range functionalStandards {
+		if standard.id != profile.ID {
+			continue
+		}
+		for _, fpc := range standard.criteria {
+			if fpc.id == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func applyRemarks(row *Row, remarks map[string]string) {
+	if text := strings.TrimSpace(remarks[row.ID]); text != "" {
+		row.Remarks = text
+		row.Edited = true
+	}
+}
+
+// conformance maps a criterion result to a VPAT conformance level
+func conformance(result models.CriterionResult) string {
+	switch result.Status {
+	case models.CriterionPassed:
+		return Supports
+	case models.CriterionFailed:
+		if result.Passes > 0 {
+			return PartiallySupports
+		}
+		return DoesNotSupport
+	case models.CriterionNotApplicable:
+		return NotApplicable
+	}
+	return NotEvaluated
+}
+
+// generatedRemarks explains a criterion result when no reviewer remarks exist
+func generatedRemarks(result models.CriterionResult) string {
+	rules := strings.Join(result.Rules, ", ")
+	switch result.Status {
+	case models.CriterionPassed:
+		return fmt.Sprintf("Automated checks passed on %d element(s) (%s).", result.Passes, rules)
+	case models.CriterionFailed:
+		return fmt.Sprintf("Automated checks found %d issue(s) and %d passing element(s) (%s).", result.Violations, result.Passes, rules)
+	case models.CriterionNotApplicable:
+		return "No content the automated checks apply to was found."
+	case models.CriterionNotTested:
+		return "The automated checks for this criterion were not run."
+	case models.CriterionNeedsReview:
+		return fmt.Sprintf("Automated checks passed on %d element(s) (%s); a manual review must confirm the criterion.", result.Passes, rules)
+	}
+	return "Not covered by automated testing; requires manual evaluation."
+}
+
+// supporting returns the success criteria of a functional performance
+// criterion that the profile incorporates
+func supporting(numbers []string, rows map[string]Row) []string {
+	var incorporated []string
+	for _, number := range numbers {
+		if _, ok := rows[number]; ok {
+			incorporated = append(incorporated, number)
+		}
+	}
+	return incorporated
+}
+
+// aggregate derives the conformance of a functional performance criterion
+// from the Level A and AA success criteria that support it
+func aggregate(numbers []string, rows map[string]Row) string {
+	var supports, fails, notEvaluated, applicable int
+	for _, number := range numbers {
+		criterion, _ := wcag.Lookup(number)
+		if criterion.Level == wcag.LevelAAA {
+			continue
+		}
+		switch rows[number].Conformance {
+		case Supports:
+			supports++
+			applicable++
+		case PartiallySupports:
+			fails++
+			supports++
+			applicable++
+		case DoesNotSupport:
+			fails++
+			applicable++
+		case NotEvaluated:
+			notEvaluated++
+			applicable++
+		}
+	}
+
+	switch {
+	case fails > 0 && supports > 0:
+		return PartiallySupports
+	case fails > 0:
+		return DoesNotSupport
+	case notEvaluated > 0 || len(numbers) == 0:
+		return NotEvaluated
+	case applicable == 0:
+		return NotApplicable
+	}
+	return Supports
+}
+
+// aggregateRemarks points at the success criteria a functional performance
+// criterion was derived from
+func aggregateRemarks(numbers []string, rows map[string]Row) string {
+	if len(numbers) == 0 {
+		return "Not covered by WCAG success criteria; requires manual evaluation."
+	}
+	var failing []string
+	for _, number := range numbers {
+		if c := rows[number].Conformance; c == DoesNotSupport || c == PartiallySupports {
+			failing = append(failing, number)
+		}
+	}
+	remarks := "Based on WCAG success criteria " + strings.Join(numbers, ", ") + "."
+	if len(failing) > 0 {
+		remarks += " Issues were found under " + strings.Join(failing, ", ") + "."
+	}
+	return remarks
+}
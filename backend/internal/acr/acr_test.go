@@ -0,0 +1,210 @@
+package acr
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"tokubetsu/internal/models"
+	"tokubetsu/internal/standards"
+)
+
+func TestConformance(t *testing.T) {
+	for _, tt := range []struct {
+		result models.CriterionResult
+		want   string
+	}{
+		{models.CriterionResult{Status: models.CriterionPassed, Passes: 3}, Supports},
+		{models.CriterionResult{Status: models.CriterionFailed, Passes: 2, Violations: 1}, PartiallySupports},
+		{models.CriterionResult{Status: models.CriterionFailed, Violations: 1}, DoesNotSupport},
+		{models.CriterionResult{Status: models.CriterionNotApplicable}, NotApplicable},
+		{models.CriterionResult{Status: models.CriterionNeedsReview, Passes: 1}, NotEvaluated},
+		{models.CriterionResult{Status: models.CriterionNeedsManual}, NotEvaluated},
+		{models.CriterionResult{Status: models.CriterionNotTested}, NotEvaluated},
+	} {
+		if got := conformance(tt.result); got != tt.want {
+			t.Errorf("conformance(%s, %d passes) = %s, want %s", tt.result.Status, tt.result.Passes, got, tt.want)
+		}
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	rows := map[string]Row{
+		"1.1.1": {Conformance: Supports},
+		"1.3.1": {Conformance: Supports},
+		"1.4.1": {Conformance: DoesNotSupport},
+		"1.4.3": {Conformance: PartiallySupports},
+		"1.2.1": {Conformance: NotApplicable},
+		"2.1.1": {Conformance: NotEvaluated},
+		"1.4.6": {Conformance: DoesNotSupport}, // Level AAA does not count
+	}
+	for _, tt := range []struct {
+		numbers []string
+		want    string
+	}{
+		{[]string{"1.1.1", "1.3.1"}, Supports},
+		{[]string{"1.1.1", "1.4.1"}, PartiallySupports},
+		{[]string{"1.4.3"}, PartiallySupports},
+		{[]string{"1.4.1", "1.2.1"}, DoesNotSupport},
+		{[]string{"1.1.1", "2.1.1"}, NotEvaluated},
+		{[]string{"1.2.1"}, NotApplicable},
+		{[]string{"1.1.1", "1.4.6"}, Supports},
+		{nil, NotEvaluated},
+	} {
+		if got := aggregate(tt.numbers, rows); got != tt.want {
+			t.Errorf("aggregate(%v) = %s, want %s", tt.numbers, got, tt.want)
+		}
+	}
+}
+
+// rowsOf returns the rows of an ACR by ID
+func rowsOf(report *Report) map[string]Row {
+	rows := make(map[string]Row)
+	for _, table := range report.Tables {
+		for _, row := range table.Rows {
+			rows[row.ID] = row
+		}
+	}
+	return rows
+}
+
+func TestBuildFromProfile(t *testing.T) {
+	project := models.Project{Name: "Portal"}
+	for _, tt := range []struct {
+		profile       string
+		wcag          string
+		has, hasNot   []string
+		tables        int
+		conformanceOf map[string]string
+	}{
+		{
+			profile: "wcag22-aa", wcag: "2.2",
+			has:    []string{"1.1.1", "2.5.8"},
+			hasNot: []string{"4.1.1", "1.4.6", "302.1", "4.2.1"},
+			tables: 2,
+		},
+		{
+			profile: "section508", wcag: "2.0",
+			has:    []string{"1.1.1", "4.1.1", "302.1", "503.4.1", "503.4.2", "602.3"},
+			hasNot: []string{"1.4.10", "2.5.8", "4.2.1"},
+			tables: 4,
+		},
+		{
+			profile: "en301549", wcag: "2.1",
+			has:    []string{"1.4.10", "4.1.1", "4.2.1", "7.1.1", "12.1.1"},
+			hasNot: []string{"2.5.8", "302.1"},
+			tables: 4,
+			conformanceOf: map[string]string{
+				"7.1.1": DoesNotSupport, // Tested by video-caption
+				"4.2.4": DoesNotSupport, // Derived from 1.2.2 among others
+			},
+		},
+	} {
+		t.Run(tt.profile, func(t *testing.T) {
+			report := models.ComplianceReport{
+				Profile: tt.profile,
+				URL:     "https://example.com/",
+				Results: []models.CriterionResult{
+					{Criterion: "1.2.2", Status: models.CriterionFailed, Violations: 1, Rules: []string{"video-caption"}},
+					{Criterion: "7.1.1", Status: models.CriterionFailed, Violations: 1, Rules: []string{"video-caption"}},
+				},
+			}
+			acr := Build(project, report, nil)
+			if acr.WCAGVersion != tt.wcag {
+				t.Errorf("WCAG version = %q, want %q", acr.WCAGVersion, tt.wcag)
+			}
+			if len(acr.Tables) != tt.tables {
+				t.Errorf("ACR has %d tables, want %d", len(acr.Tables), tt.tables)
+			}
+			rows := rowsOf(acr)
+			for _, id := range tt.has {
+				if _, ok := rows[id]; !ok {
+					t.Errorf("ACR has no row %s", id)
+				}
+			}
+			for _, id := range tt.hasNot {
+				if _, ok := rows[id]; ok {
+					t.Errorf("ACR has row %s, which %s does not require", id, tt.profile)
+				}
+			}
+			for id, want := range tt.conformanceOf {
+				if got := rows[id].Conformance; got != want {
+					t.Errorf("row %s conformance = %q, want %q", id, got, want)
+				}
+			}
+			for id := range rows {
+				if !ValidRow(standards.Resolve(tt.profile), id) {
+					t.Errorf("row %s does not accept remarks", id)
+				}
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	report := models.ComplianceReport{Profile: "section508", URL: "https://example.com/", GeneratedAt: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)}
+	acr := Build(models.Project{Name: "Portal & Co"}, report, map[string]string{"503.4.1": "No media player."})
+	want := []string{
+		"WCAG 2.0 Conformance Level",
+		"Revised Section 508 standards published January 18, 2017",
+		"503.4.1 Caption Controls",
+		"No media player.",
+	}
+
+	var html bytes.Buffer
+	if err := RenderHTML(&html, acr); err != nil {
+		t.Fatal(err)
+	}
+	for _, text := range want {
+		if !strings.Contains(html.String(), text) {
+			t.Errorf("HTML does not contain %q", text)
+		}
+	}
+	if strings.Contains(html.String(), "WCAG 2.2") {
+		t.Error("HTML of a Section 508 report mentions WCAG 2.2")
+	}
+
+	var docx bytes.Buffer
+	if err := RenderDOCX(&docx, acr); err != nil {
+		t.Fatal(err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(docx.Bytes()), int64(docx.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var document []byte
+	for _, f := range archive.File {
+		if f.Name != "word/document.xml" {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		document, err = io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if document == nil {
+		t.Fatal("DOCX has no word/document.xml")
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(document))
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("word/document.xml is not well-formed: %v", err)
+		}
+	}
+	for _, text := range append(want, "Portal &amp; Co") {
+		if !bytes.Contains(document, []byte(text)) {
+			t.Errorf("DOCX does not contain %q", text)
+		}
+	}
+}
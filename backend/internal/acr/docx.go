@@ -0,0 +1,149 @@
+package acr
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const docxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>
+</Types>`
+
+const docxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>
+</Relationships>`
+
+// Column widths of conformance tables in twentieths of a point
+var docxColumns = []int{3600, 1800, 4200}
+
+// RenderDOCX writes the report as a Word document
+func RenderDOCX(w io.Writer, report *Report) error {
+	archive := zip.NewWriter(w)
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", docxContentTypes},
+		{"_rels/.rels", docxRels},
+		{"word/document.xml", docxDocument(report)},
+	}
+	for _, part := range parts {
+		f, err := archive.Create(part.name)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %v", part.name, err)
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return fmt.Errorf("failed to write %s: %v", part.name, err)
+		}
+	}
+	return archive.Close()
+}
+
+// docxDocument builds the WordprocessingML body of the report
+func docxDocument(report *Report) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	b.WriteString(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>`)
+
+	docxParagraph(&b, report.Product+" Accessibility Conformance Report", 36, true)
+	docxParagraph(&b, "International Edition, based on VPAT® Version 2.5", 22, false)
+	docxField(&b, "Name of Product/Version", report.Product)
+	if report.Description != "" {
+		docxField(&b, "Product Description", report.Description)
+	}
+	docxField(&b, "URL Evaluated", report.URL)
+	docxField(&b, "Report Date", report.Date.Format("January 2, 2006"))
+	docxField(&b, "Evaluation Methods Used", report.EvaluationMethods)
+	docxField(&b, "WCAG "+report.WCAGVersion+" Conformance Level", report.ConformanceLevel)
+
+	docxParagraph(&b, "Applicable Standards/Guidelines", 28, true)
+	for _, standard := range report.Standards {
+		docxParagraph(&b, standard, 22, false)
+	}
+
+	for _, table := range report.Tables {
+		docxParagraph(&b, table.Title, 28, true)
+		if table.Notes != "" {
+			docxParagraph(&b, "Notes: "+table.Notes, 20, false)
+		}
+
+		b.WriteString(`<w:tbl><w:tblPr><w:tblW w:w="0" w:type="auto"/><w:tblBorders>`)
+		for _, side := range []string{"top", "left", "bottom", "right", "insideH", "insideV"} {
+			fmt.Fprintf(&b, `<w:%s w:val="single" w:sz="4" w:space="0" w:color="595959"/>`, side)
+		}
+		b.WriteString(`</w:tblBorders></w:tblPr><w:tblGrid>`)
+		for _, width := range docxColumns {
+			fmt.Fprintf(&b, `<w:gridCol w:w="%d"/>`, width)
+		}
+		b.WriteString(`</w:tblGrid>`)
+
+		// Repeat the header row on every page
+		b.WriteString(`<w:tr><w:trPr><w:tblHeader/></w:trPr>`)
+		for i, heading := range []string{"Criteria", "Conformance Level", "Remarks and Explanations"} {
+			docxCell(&b, docxColumns[i], true, heading)
+		}
+		b.WriteString(`</w:tr>`)
+
+		for _, row := range table.Rows {
+			b.WriteString(`<w:tr>`)
+			criterion := []string{row.Criterion}
+			if len(row.AlsoAppliesTo) > 0 {
+				criterion = append(criterion, "Also applies to: "+strings.Join(row.AlsoAppliesTo, "; "))
+			}
+			docxCell(&b, docxColumns[0], false, criterion...)
+			docxCell(&b, docxColumns[1], false, row.Conformance)
+			docxCell(&b, docxColumns[2], false, row.Remarks)
+			b.WriteString(`</w:tr>`)
+		}
+		b.WriteString(`</w:tbl>`)
+	}
+
+	b.WriteString(`<w:sectPr><w:pgSz w:w="12240" w:h="15840"/><w:pgMar w:top="1080" w:right="1080" w:bottom="1080" w:left="1080" w:header="720" w:footer="720" w:gutter="0"/></w:sectPr>`)
+	b.WriteString(`</w:body></w:document>`)
+	return b.String()
+}
+
+// docxParagraph writes a paragraph of text at the given size in half-points
+func docxParagraph(b *strings.Builder, text string, size int, bold bool) {
+	b.WriteString(`<w:p>`)
+	docxRun(b, text, size, bold)
+	b.WriteString(`</w:p>`)
+}
+
+// docxField writes a "Label: value" paragraph
+func docxField(b *strings.Builder, label, value string) {
+	b.WriteString(`<w:p>`)
+	docxRun(b, label+": ", 22, true)
+	docxRun(b, value, 22, false)
+	b.WriteString(`</w:p>`)
+}
+
+// docxCell writes a table cell with one paragraph per line
+func docxCell(b *strings.Builder, width int, bold bool, lines ...string) {
+	fmt.Fprintf(b, `<w:tc><w:tcPr><w:tcW w:w="%d" w:type="dxa"/>`, width)
+	if bold {
+		b.WriteString(`<w:shd w:val="clear" w:color="auto" w:fill="E8E8E8"/>`)
+	}
+	b.WriteString(`</w:tcPr>`)
+	for _, line := range lines {
+		docxParagraph(b, line, 20, bold)
+	}
+	b.WriteString(`</w:tc>`)
+}
+
+func docxRun(b *strings.Builder, text string, size int, bold bool) {
+	b.WriteString(`<w:r><w:rPr>`)
+	if bold {
+		b.WriteString(`<w:b/>`)
+	}
+	fmt.Fprintf(b, `<w:sz w:val="%d"/></w:rPr><w:t xml:space="preserve">`, size)
+	var escaped bytes.Buffer
+	xml.EscapeText(&escaped, []byte(text))
+	b.Write(escaped.Bytes())
+	b.WriteString(`</w:t></w:r>`)
+}
@@ -0,0 +1,76 @@
+package acr
+
+import (
+	"html/template"
+	"io"
+)
+
+var htmlTemplate = template.Must(template.New("acr").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Product}} Accessibility Conformance Report</title>
+<style>
+body { font-family: Arial, Helvetica, sans-serif; line-height: 1.4; margin: 2rem; color: #1a1a1a; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2rem; }
+th, td { border: 1px solid #595959; padding: 0.4rem 0.6rem; text-align: left; vertical-align: top; }
+th { background: #e8e8e8; }
+.also { display: block; font-size: 0.85rem; color: #404040; }
+.notes { font-size: 0.9rem; }
+</style>
+</head>
+<body>
+<h1>{{.Product}} Accessibility Conformance Report</h1>
+<p>International Edition, based on VPAT&reg; Version 2.5</p>
+<dl>
+<dt>Name of Product/Version</dt><dd>{{.Product}}</dd>
+{{- if .Description}}
+<dt>Product Description</dt><dd>{{.Description}}</dd>
+{{- end}}
+<dt>URL Evaluated</dt><dd>{{.URL}}</dd>
+<dt>Report Date</dt><dd>{{.Date.Format "January 2, 2006"}}</dd>
+<dt>Evaluation Methods Used</dt><dd>{{.EvaluationMethods}}</dd>
+<dt>WCAG {{.WCAGVersion}} Conformance Level</dt><dd>{{.ConformanceLevel}}</dd>
+</dl>
+<h2>Applicable Standards/Guidelines</h2>
+<ul>
+{{- range .Standards}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+<h2>Terms</h2>
+<ul>
+<li><strong>Supports</strong>: The functionality of the product has at least one method that meets the criterion without known defects or meets with equivalent facilitation.</li>
+<li><strong>Partially Supports</strong>: Some functionality of the product does not meet the criterion.</li>
+<li><strong>Does Not Support</strong>: The majority of product functionality does not meet the criterion.</li>
+<li><strong>Not Applicable</strong>: The criterion is not relevant to the product.</li>
+<li><strong>Not Evaluated</strong>: The product has not been evaluated against the criterion.</li>
+</ul>
+{{range $i, $table := .Tables}}
+<h2 id="table-{{$i}}">{{.Title}}</h2>
+{{- if .Notes}}
+<p class="notes">Notes: {{.Notes}}</p>
+{{- end}}
+<table aria-labelledby="table-{{$i}}">
+<thead>
+<tr><th scope="col">Criteria</th><th scope="col">Conformance Level</th><th scope="col">Remarks and Explanations</th></tr>
+</thead>
+<tbody>
+{{- range .Rows}}
+<tr>
+<th scope="row">{{.Criterion}}{{if .AlsoAppliesTo}}<span class="also">Also applies to: {{range $i, $c := .AlsoAppliesTo}}{{if $i}}; {{end}}{{$c}}{{end}}</span>{{end}}</th>
+<td>{{.Conformance}}</td>
+<td>{{.Remarks}}</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+{{end}}
+</body>
+</html>
+`))
+
+// RenderHTML writes the report as a standalone HTML document
+func RenderHTML(w io.Writer, report *Report) error {
+	return htmlTemplate.Execute(w, report)
+}
@@ -0,0 +1,121 @@
+package acr
+
+import "tokubetsu/internal/standards"
+
+// document is how the ACR of a standards profile names the standards it
+// applies and its table of requirements beyond WCAG
+type document struct {
+	standards []string
+	additions string
+}
+
+var documents = map[string]document{
+	"wcag22-aa": {standards: []string{
+		"Web Content Accessibility Guidelines 2.2, Level A and AA",
+	}},
+	"wcag22-aaa": {standards: []string{
+		"Web Content Accessibility Guidelines 2.2, Level A, AA and AAA",
+	}},
+	"wcag21-aa": {standards: []string{
+		"Web Content Accessibility Guidelines 2.1, Level A and AA",
+	}},
+	"section508": {standards: []string{
+		"Revised Section 508 standards published January 18, 2017 and corrected January 22, 2018",
+		"Web Content Accessibility Guidelines 2.0, Level A and AA",
+	}, additions: "Revised Section 508 Report: Chapter 5 Software and Chapter 6 Support Documentation and Services"},
+	"en301549": {standards: []string{
+		"EN 301 549 Accessibility requirements for ICT products and services, V3.2.1 (2021-03)",
+		"Web Content Accessibility Guidelines 2.1, Level A and AA",
+	}, additions: "EN 301 549 Report: Clause 7 ICT with Video Capabilities and Clause 12 Documentation and Support Services"},
+	"ada-title-ii": {standards: []string{
+		"Americans with Disabilities Act Title II, 28 CFR 35.200",
+		"Web Content Accessibility Guidelines 2.1, Level A and AA",
+	}},
+}
+
+// documentOf returns the document of a profile, named after the profile
+// itself when it has none
+func documentOf(profile *standards.Profile) document {
+	d, ok := documents[profile.ID]
+	if !ok {
+		d.standards = []string{profile.Name}
+	}
+	if d.additions == "" {
+		d.additions = profile.Name + " Report: Additional Requirements"
+	}
+	return d
+}
+
+// functionalCriterion is a functional performance criterion of Section 508
+// or EN 301 549 and the WCAG success criteria that support it
+type functionalCriterion struct {
+	id    string
+	title string
+	wcag  []string
+}
+
+var functionalStandards = []struct {
+	id       string
+	title    string
+	notes    string
+	criteria []functionalCriterion
+}{
+	{
+		id:    "section508",
+		title: "Revised Section 508 Report: Chapter 3 Functional Performance Criteria",
+		notes: "Web content is covered by 501.1 and E205.4 through the WCAG tables above. " +
+			"Chapter 4 (Hardware) and Chapter 5 (Software) do not apply to web content. " +
+			"Conformance is derived from the supporting WCAG success criteria.",
+		criteria: []functionalCriterion{
+			{"302.1", "Without Vision", withoutVision},
+			{"302.2", "With Limited Vision", limitedVision},
+			{"302.3", "Without Perception of Color", withoutColor},
+			{"302.4", "Without Hearing", withoutHearing},
+			{"302.5", "With Limited Hearing", limitedHearing},
+			{"302.6", "Without Speech", nil},
+			{"302.7", "With Limited Manipulation", limitedManipulation},
+			{"302.8", "With Limited Reach and Strength", nil},
+			{"302.9", "With Limited Language, Cognitive, and Learning Abilities", limitedCognition},
+		},
+	},
+	{
+		id:    "en301549",
+		title: "EN 301 549 Report: Clause 4 Functional Performance Statements",
+		notes: "Clause 9 (Web) is covered by the WCAG tables above. " +
+			"Clauses 5 to 8 and 10 to 13 apply to hardware, software, non-web documents and services and are not evaluated. " +
+			"Conformance is derived from the supporting WCAG success criteria.",
+		criteria: []functionalCriterion{
+			{"4.2.1", "Usage without vision", withoutVision},
+			{"4.2.2", "Usage with limited vision", limitedVision},
+			{"4.2.3", "Usage without perception of colour", withoutColor},
+			{"4.2.4", "Usage without hearing", withoutHearing},
+			{"4.2.5", "Usage with limited hearing", limitedHearing},
+			{"4.2.6", "Usage with no or limited vocal capability", nil},
+			{"4.2.7", "Usage with limited manipulation or strength", limitedManipulation},
+			{"4.2.8", "Usage with limited reach", nil},
+			{"4.2.9", "Minimize photosensitive seizure triggers", []string{"2.3.1"}},
+			{"4.2.10", "Usage with limited cognition, language or learning", limitedCognition},
+			{"4.2.11", "Privacy", nil},
+		},
+	},
+}
+
+// Supporting success criteria of the functional performance criteria
+var (
+	withoutVision = []string{
+		"1.1.1", "1.2.1", "1.2.3", "1.2.5", "1.3.1", "1.3.2", "1.3.3", "2.1.1", "2.1.2",
+		"2.4.1", "2.4.2", "2.4.3", "2.4.4", "3.1.1", "3.1.2", "3.3.1", "3.3.2", "4.1.2", "4.1.3",
+	}
+	limitedVision = []string{
+		"1.3.4", "1.4.3", "1.4.4", "1.4.5", "1.4.10", "1.4.11", "1.4.12", "1.4.13",
+		"2.4.7", "2.4.11", "2.5.8",
+	}
+	withoutColor        = []string{"1.4.1", "1.4.3", "1.4.11"}
+	withoutHearing      = []string{"1.2.1", "1.2.2", "1.2.4"}
+	limitedHearing      = []string{"1.2.2", "1.2.4", "1.4.2"}
+	limitedManipulation = []string{"2.1.1", "2.1.2", "2.1.4", "2.5.1", "2.5.2", "2.5.4", "2.5.7", "2.5.8"}
+	limitedCognition    = []string{
+		"2.2.1", "2.2.2", "2.4.2", "2.4.4", "2.4.6", "3.1.1", "3.1.2", "3.2.1", "3.2.2",
+		"3.2.3", "3.2.4", "3.2.6", "3.3.1", "3.3.2", "3.3.3", "3.3.4", "3.3.7", "3.3.8",
+	}
+)
@@ -0,0 +1,112 @@
+package aria
+
+import (
+	"strings"
+
+	"tokubetsu/internal/dom"
+
+	"golang.org/x/net/html"
+)
+
+// Disabled reports whether an element is disabled, natively or through
+// aria-disabled
+func Disabled(n *html.Node) bool {
+	if strings.EqualFold(dom.Attr(n, "aria-disabled"), "true") {
+		return true
+	}
+	if !dom.IsElement(n, "button", "input", "select", "textarea", "optgroup", "option", "fieldset") {
+		return false
+	}
+	if dom.HasAttr(n, "disabled") {
+		return true
+	}
+	// Controls inside a disabled fieldset are disabled, except those in its
+	// first legend
+	for p := n.Parent; p != nil; p = p.Parent {
+		if dom.IsElement(p, "fieldset") && dom.HasAttr(p, "disabled") {
+			legend := dom.Find(p, "legend")
+			return legend == nil || !contains(legend, n)
+		}
+	}
+	return false
+}
+
+// Focusable reports whether an element can receive keyboard focus, either
+// natively or through a tabindex attribute
+func Focusable(n *html.Node) bool {
+	if n == nil || n.Type != html.ElementNode {
+		return false
+	}
+	if dom.IsElement(n, "button", "input", "select", "textarea") {
+		if n.Data == "input" && strings.EqualFold(dom.Attr(n, "type"), "hidden") {
+			return false
+		}
+		return !Disabled(n)
+	}
+	if _, ok := TabIndex(n); ok {
+		return true
+	}
+	switch n.Data {
+	case "a", "area":
+		return dom.HasAttr(n, "href")
+	case "iframe", "summary":
+		return true
+	case "audio", "video":
+		return dom.HasAttr(n, "controls")
+	}
+	editable, ok := dom.LookupAttr(n, "contenteditable")
+	return ok && !strings.EqualFold(editable, "false")
+}
+
+// Tabbable reports whether an element is focusable and in the sequential
+// focus navigation order
+func Tabbable(n *html.Node) bool {
+	if !Focusable(n) {
+		return false
+	}
+	index, ok := TabIndex(n)
+	return !ok || index >= 0
+}
+
+// TabIndex returns the parsed tabindex attribute of an element. It reports
+// false when the attribute is absent or not a valid integer.
+func TabIndex(n *html.Node) (int, bool) {
+	value, ok := dom.LookupAttr(n, "tabindex")
+	if !ok {
+		return 0, false
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	sign := 1
+	if value[0] == '-' || value[0] == '+' {
+		if value[0] == '-' {
+			sign = -1
+		}
+		value = value[1:]
+	}
+	index := 0
+	for i, r := range value {
+		if r < '0' || r > '9' {
+			if i == 0 {
+				return 0, false
+			}
+			break
+		}
+		index = index*10 + int(r-'0')
+	}
+	if value == "" {
+		return 0, false
+	}
+	return sign * index, true
+}
+
+func contains(ancestor, n *html.Node) bool {
+	for p := n; p != nil; p = p.Parent {
+		if p == ancestor {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package aria
+
+import (
+	"strings"
+
+	"tokubetsu/internal/dom"
+
+	"golang.org/x/net/html"
+)
+
+// HiddenByARIA reports whether an element or an ancestor has aria-hidden="true"
+func HiddenByARIA(n *html.Node) bool {
+	for e := n; e != nil; e = e.Parent {
+		if e.Type == html.ElementNode && strings.EqualFold(strings.TrimSpace(dom.Attr(e, "aria-hidden")), "true") {
+			return true
+		}
+	}
+	return false
+}
+
+// Hidden reports whether an element is excluded from the accessibility tree
+// without considering stylesheets: aria-hidden, the hidden attribute,
+// inline display:none or visibility:hidden, or a non-rendered element
+func Hidden(n *html.Node) bool {
+	if HiddenByARIA(n) {
+		return true
+	}
+	for e := n; e != nil; e = e.Parent {
+		if e.Type != html.ElementNode {
+			continue
+		}
+		switch e.Data {
+		case "head", "script", "style", "template", "noscript", "title", "meta", "link":
+			return true
+		}
+		if dom.HasAttr(e, "hidden") {
+			return true
+		}
+		style := strings.ToLower(strings.ReplaceAll(dom.Attr(e, "style"), " ", ""))
+		if strings.Contains(style, "display:none") || (e == n && strings.Contains(style, "visibility:hidden")) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,260 @@
+// Package aria implements WAI-ARIA role and state semantics for
+// golang.org/x/net/html trees, following the HTML-AAM mappings.
+package aria
+
+import (
+	"strconv"
+	"strings"
+
+	"tokubetsu/internal/dom"
+
+	"golang.org/x/net/html"
+)
+
+// Role returns the element's effective role: the first recognised token of
+// its role attribute, or its implicit role
+func Role(n *html.Node) string {
+	if role := ExplicitRole(n); role != "" {
+		return role
+	}
+	return ImplicitRole(n)
+}
+
+// ExplicitRole returns the first recognised token of the role attribute
+func ExplicitRole(n *html.Node) string {
+	for _, token := range strings.Fields(strings.ToLower(dom.Attr(n, "role"))) {
+		if IsValidRole(token) {
+			return token
+		}
+	}
+	return ""
+}
+
+// ImplicitRole returns the role an element has without a role attribute
+func ImplicitRole(n *html.Node) string {
+	if n == nil || n.Type != html.ElementNode {
+		return ""
+	}
+
+	switch n.Data {
+	case "a", "area":
+		if dom.HasAttr(n, "href") {
+			return "link"
+		}
+		if n.Data == "a" {
+			return "generic"
+		}
+	case "article":
+		return "article"
+	case "aside":
+		return "complementary"
+	case "blockquote":
+		return "blockquote"
+	case "button":
+		return "button"
+	case "caption":
+		return "caption"
+	case "code":
+		return "code"
+	case "datalist":
+		return "listbox"
+	case "dd":
+		return "definition"
+	case "del", "s":
+		return "deletion"
+	case "details":
+		return "group"
+	case "dfn", "dt":
+		return "term"
+	case "dialog":
+		return "dialog"
+	case "em":
+		return "emphasis"
+	case "fieldset", "optgroup":
+		return "group"
+	case "figure":
+		return "figure"
+	case "footer":
+		if !withinSectioningContent(n) {
+			return "contentinfo"
+		}
+		return "generic"
+	case "form":
+		return "form"
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		return "heading"
+	case "header":
+		if !withinSectioningContent(n) {
+			return "banner"
+		}
+		return "generic"
+	case "hr":
+		return "separator"
+	case "html":
+		return "document"
+	case "img":
+		if alt, ok := dom.LookupAttr(n, "alt"); ok && alt == "" && !hasGlobalARIA(n) {
+			return "presentation"
+		}
+		return "img"
+	case "input":
+		return inputRole(n)
+	case "ins":
+		return "insertion"
+	case "li":
+		return "listitem"
+	case "main":
+		return "main"
+	case "math":
+		return "math"
+	case "menu", "ol", "ul":
+		return "list"
+	case "meter":
+		return "meter"
+	case "nav":
+		return "navigation"
+	case "option":
+		return "option"
+	case "output":
+		return "status"
+	case "p":
+		return "paragraph"
+	case "progress":
+		return "progressbar"
+	case "search":
+		return "search"
+	case "section":
+		if dom.HasAttr(n, "aria-label") || dom.HasAttr(n, "aria-labelledby") || dom.HasAttr(n, "title") {
+			return "region"
+		}
+		return "generic"
+	case "select":
+		size, _ := strconv.Atoi(dom.Attr(n, "size"))
+		if dom.HasAttr(n, "multiple") || size > 1 {
+			return "listbox"
+		}
+		return "combobox"
+	case "strong":
+		return "strong"
+	case "sub":
+		return "subscript"
+	case "sup":
+		return "superscript"
+	case "svg":
+		return "graphics-document"
+	case "table":
+		return "table"
+	case "tbody", "tfoot", "thead":
+		return "rowgroup"
+	case "td":
+		if table := dom.Closest(n, "table"); table != nil && isGrid(table) {
+			return "gridcell"
+		}
+		return "cell"
+	case "textarea":
+		return "textbox"
+	case "th":
+		return headerCellRole(n)
+	case "time":
+		return "time"
+	case "tr":
+		return "row"
+	case "b", "bdi", "bdo", "body", "data", "div", "i", "pre", "q", "samp", "small", "span", "u":
+		return "generic"
+	}
+	return ""
+}
+
+func inputRole(n *html.Node) string {
+	hasList := dom.HasAttr(n, "list")
+	switch strings.ToLower(dom.Attr(n, "type")) {
+	case "button", "image", "reset", "submit":
+		return "button"
+	case "checkbox":
+		return "checkbox"
+	case "radio":
+		return "radio"
+	case "range":
+		return "slider"
+	case "number":
+		return "spinbutton"
+	case "search":
+		if hasList {
+			return "combobox"
+		}
+		return "searchbox"
+	case "hidden", "file", "color", "date", "datetime-local", "month", "time", "week", "password":
+		return ""
+	case "", "text", "email", "tel", "url":
+		if hasList {
+			return "combobox"
+		}
+		return "textbox"
+	}
+	// Unknown types behave as text inputs
+	if hasList {
+		return "combobox"
+	}
+	return "textbox"
+}
+
+// headerCellRole distinguishes row headers from column headers
+func headerCellRole(n *html.Node) string {
+	switch strings.ToLower(dom.Attr(n, "scope")) {
+	case "row", "rowgroup":
+		return "rowheader"
+	case "col", "colgroup":
+		return "columnheader"
+	}
+	// A header cell followed by data cells in the same row heads that row
+	for s := n.NextSibling; s != nil; s = s.NextSibling {
+		if dom.IsElement(s, "td") {
+			return "rowheader"
+		}
+	}
+	for s := n.PrevSibling; s != nil; s = s.PrevSibling {
+		if dom.IsElement(s, "td") {
+			return "rowheader"
+		}
+	}
+	return "columnheader"
+}
+
+func isGrid(table *html.Node) bool {
+	role := ExplicitRole(table)
+	return role == "grid" || role == "treegrid"
+}
+
+// withinSectioningContent reports whether header/footer is scoped to a
+// sectioning element rather than to the page
+func withinSectioningContent(n *html.Node) bool {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if dom.IsElement(p, "article", "aside", "main", "nav", "section") {
+			return true
+		}
+		switch ExplicitRole(p) {
+		case "article", "complementary", "main", "navigation", "region":
+			return true
+		}
+	}
+	return false
+}
+
+// hasGlobalARIA reports whether an element has a global aria-* attribute or
+// is focusable, either of which overrides a presentational role
+func hasGlobalARIA(n *html.Node) bool {
+	for _, attr := range n.Attr {
+		if strings.HasPrefix(attr.Key, "aria-") {
+			return true
+		}
+	}
+	return Focusable(n)
+}
+
+// IsPresentational reports whether an element's semantics are removed by a
+// presentation or none role. The role is ignored on focusable elements and
+// elements with global ARIA attributes.
+func IsPresentational(n *html.Node) bool {
+	role := Role(n)
+	return (role == "presentation" || role == "none") && !hasGlobalARIA(n)
+}
@@ -0,0 +1,384 @@
+package aria
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ValueType is the type of an ARIA state or property value
+type ValueType int
+
+const (
+	TypeBoolean ValueType = iota
+	TypeTristate
+	TypeTrueFalseUndefined
+	TypeIDRef
+	TypeIDRefList
+	TypeInteger
+	TypeNumber
+	TypeString
+	TypeToken
+	TypeTokenList
+)
+
+// Attribute describes an ARIA state or property
+type Attribute struct {
+	Name string
+	Type ValueType
+	// Values lists the allowed tokens of token and token list attributes
+	Values []string
+	// Global attributes are allowed on every role
+	Global bool
+	// MinValue is the lowest allowed value of integer attributes
+	MinValue *int
+}
+
+// RoleSpec describes the states, properties and structure of a role
+type RoleSpec struct {
+	Name     string
+	Abstract bool
+	// Required states and properties that must be present
+	Required []string
+	// Supported states and properties in addition to the global ones
+	Supported []string
+	// Prohibited states and properties that must not be used
+	Prohibited []string
+	// RequiredOwned roles, one of which the element must own
+	RequiredOwned []string
+	// RequiredContext roles, one of which must own the element
+	RequiredContext []string
+	// NameFromContent roles compute their name from their descendants
+	NameFromContent bool
+	// ChildrenPresentational roles hide their descendants' semantics
+	ChildrenPresentational bool
+}
+
+func intPtr(v int) *int { return &v }
+
+// attributes is the WAI-ARIA 1.2 table of states and properties
+var attributes = map[string]Attribute{}
+
+func init() {
+	for _, attr := range []Attribute{
+		{Name: "aria-activedescendant", Type: TypeIDRef},
+		{Name: "aria-atomic", Type: TypeBoolean, Global: true},
+		{Name: "aria-autocomplete", Type: TypeToken, Values: []string{"inline", "list", "both", "none"}},
+		{Name: "aria-braillelabel", Type: TypeString, Global: true},
+		{Name: "aria-brailleroledescription", Type: TypeString, Global: true},
+		{Name: "aria-busy", Type: TypeBoolean, Global: true},
+		{Name: "aria-checked", Type: TypeTristate},
+		{Name: "aria-colcount", Type: TypeInteger, MinValue: intPtr(-1)},
+		{Name: "aria-colindex", Type: TypeInteger, MinValue: intPtr(1)},
+		{Name: "aria-colindextext", Type: TypeString},
+		{Name: "aria-colspan", Type: TypeInteger, MinValue: intPtr(1)},
+		{Name: "aria-controls", Type: TypeIDRefList, Global: true},
+		{Name: "aria-current", Type: TypeToken, Values: []string{"page", "step", "location", "date", "time", "true", "false"}, Global: true},
+		{Name: "aria-describedby", Type: TypeIDRefList, Global: true},
+		{Name: "aria-description", Type: TypeString, Global: true},
+		{Name: "aria-details", Type: TypeIDRefList, Global: true},
+		{Name: "aria-disabled", Type: TypeBoolean, Global: true},
+		{Name: "aria-dropeffect", Type: TypeTokenList, Values: []string{"copy", "execute", "link", "move", "none", "popup"}, Global: true},
+		{Name: "aria-errormessage", Type: TypeIDRefList, Global: true},
+		{Name: "aria-expanded", Type: TypeTrueFalseUndefined},
+		{Name: "aria-flowto", Type: TypeIDRefList, Global: true},
+		{Name: "aria-grabbed", Type: TypeTrueFalseUndefined, Global: true},
+		{Name: "aria-haspopup", Type: TypeToken, Values: []string{"true", "false", "menu", "listbox", "tree", "grid", "dialog"}, Global: true},
+		{Name: "aria-hidden", Type: TypeTrueFalseUndefined, Global: true},
+		{Name: "aria-invalid", Type: TypeToken, Values: []string{"grammar", "false", "spelling", "true"}, Global: true},
+		{Name: "aria-keyshortcuts", Type: TypeString, Global: true},
+		{Name: "aria-label", Type: TypeString, Global: true},
+		{Name: "aria-labelledby", Type: TypeIDRefList, Global: true},
+		{Name: "aria-level", Type: TypeInteger, MinValue: intPtr(1)},
+		{Name: "aria-live", Type: TypeToken, Values: []string{"assertive", "off", "polite"}, Global: true},
+		{Name: "aria-modal", Type: TypeBoolean},
+		{Name: "aria-multiline", Type: TypeBoolean},
+		{Name: "aria-multiselectable", Type: TypeBoolean},
+		{Name: "aria-orientation", Type: TypeToken, Values: []string{"horizontal", "vertical", "undefined"}},
+		{Name: "aria-owns", Type: TypeIDRefList, Global: true},
+		{Name: "aria-placeholder", Type: TypeString},
+		{Name: "aria-posinset", Type: TypeInteger, MinValue: intPtr(1)},
+		{Name: "aria-pressed", Type: TypeTristate},
+		{Name: "aria-readonly", Type: TypeBoolean},
+		{Name: "aria-relevant", Type: TypeTokenList, Values: []string{"additions", "all", "removals", "text"}, Global: true},
+		{Name: "aria-required", Type: TypeBoolean},
+		{Name: "aria-roledescription", Type: TypeString, Global: true},
+		{Name: "aria-rowcount", Type: TypeInteger, MinValue: intPtr(-1)},
+		{Name: "aria-rowindex", Type: TypeInteger, MinValue: intPtr(1)},
+		{Name: "aria-rowindextext", Type: TypeString},
+		{Name: "aria-rowspan", Type: TypeInteger, MinValue: intPtr(0)},
+		{Name: "aria-selected", Type: TypeTrueFalseUndefined},
+		{Name: "aria-setsize", Type: TypeInteger, MinValue: intPtr(-1)},
+		{Name: "aria-sort", Type: TypeToken, Values: []string{"ascending", "descending", "none", "other"}},
+		{Name: "aria-valuemax", Type: TypeNumber},
+		{Name: "aria-valuemin", Type: TypeNumber},
+		{Name: "aria-valuenow", Type: TypeNumber},
+		{Name: "aria-valuetext", Type: TypeString},
+	} {
+		attributes[attr.Name] = attr
+	}
+
+	for _, role := range roleTable {
+		roles[role.Name] = role
+	}
+}
+
+// Shared attribute sets of the role table
+var (
+	gridCellAttrs = []string{"aria-colindex", "aria-colindextext", "aria-colspan", "aria-rowindex", "aria-rowindextext", "aria-rowspan"}
+	headerAttrs   = append([]string{"aria-sort", "aria-readonly", "aria-required", "aria-selected", "aria-expanded"}, gridCellAttrs...)
+	rangeAttrs    = []string{"aria-valuemax", "aria-valuemin", "aria-valuenow", "aria-valuetext"}
+	textboxAttrs  = []string{"aria-activedescendant", "aria-autocomplete", "aria-multiline", "aria-placeholder", "aria-readonly", "aria-required"}
+	menuItems     = []string{"group", "menuitem", "menuitemcheckbox", "menuitemradio"}
+	menuContext   = []string{"group", "menu", "menubar"}
+	linkAttrs     = []string{"aria-expanded"}
+	namingAttrs   = []string{"aria-label", "aria-labelledby"}
+)
+
+// roleTable is the WAI-ARIA 1.2 role table, with the DPUB-ARIA and Graphics
+// ARIA roles
+var roleTable = []RoleSpec{
+	// Abstract roles may not be used in content
+	{Name: "command", Abstract: true},
+	{Name: "composite", Abstract: true},
+	{Name: "input", Abstract: true},
+	{Name: "landmark", Abstract: true},
+	{Name: "range", Abstract: true},
+	{Name: "roletype", Abstract: true},
+	{Name: "section", Abstract: true},
+	{Name: "sectionhead", Abstract: true},
+	{Name: "select", Abstract: true},
+	{Name: "structure", Abstract: true},
+	{Name: "widget", Abstract: true},
+	{Name: "window", Abstract: true},
+
+	{Name: "alert", Supported: []string{"aria-expanded"}},
+	{Name: "alertdialog", Supported: []string{"aria-expanded", "aria-modal"}},
+	{Name: "application", Supported: []string{"aria-activedescendant", "aria-expanded"}},
+	{Name: "article", Supported: []string{"aria-expanded", "aria-posinset", "aria-setsize"}},
+	{Name: "banner", Supported: []string{"aria-expanded"}},
+	{Name: "blockquote"},
+	{Name: "button", Supported: []string{"aria-expanded", "aria-pressed"}, NameFromContent: true, ChildrenPresentational: true},
+	{Name: "caption", Prohibited: namingAttrs},
+	{Name: "cell", Supported: gridCellAttrs, RequiredContext: []string{"row"}, NameFromContent: true},
+	{Name: "checkbox", Required: []string{"aria-checked"}, Supported: []string{"aria-expanded", "aria-readonly", "aria-required"}, NameFromContent: true, ChildrenPresentational: true},
+	{Name: "code", Prohibited: namingAttrs},
+	{Name: "columnheader", Supported: headerAttrs, RequiredContext: []string{"row"}, NameFromContent: true},
+	{Name: "combobox", Required: []string{"aria-expanded"}, Supported: []string{"aria-activedescendant", "aria-autocomplete", "aria-readonly", "aria-required"}},
+	{Name: "comment", Supported: []string{"aria-level", "aria-posinset", "aria-setsize"}, NameFromContent: true},
+	{Name: "complementary", Supported: []string{"aria-expanded"}},
+	{Name: "contentinfo", Supported: []string{"aria-expanded"}},
+	{Name: "definition"},
+	{Name: "deletion", Prohibited: namingAttrs},
+	{Name: "dialog", Supported: []string{"aria-expanded", "aria-modal"}},
+	{Name: "directory", Supported: []string{"aria-expanded"}},
+	{Name: "document", Supported: []string{"aria-expanded"}},
+	{Name: "emphasis", Prohibited: namingAttrs},
+	{Name: "feed", RequiredOwned: []string{"article"}},
+	{Name: "figure", Supported: []string{"aria-expanded"}},
+	{Name: "form", Supported: []string{"aria-expanded"}},
+	{Name: "generic", Prohibited: namingAttrs},
+	{Name: "grid", Supported: []string{"aria-activedescendant", "aria-colcount", "aria-expanded", "aria-multiselectable", "aria-readonly", "aria-rowcount"}, RequiredOwned: []string{"row", "rowgroup"}},
+	{Name: "gridcell", Supported: append([]string{"aria-readonly", "aria-required", "aria-selected", "aria-expanded"}, gridCellAttrs...), RequiredContext: []string{"row"}, NameFromContent: true},
+	{Name: "group", Supported: []string{"aria-activedescendant", "aria-expanded"}},
+	{Name: "heading", Supported: []string{"aria-expanded", "aria-level"}, NameFromContent: true},
+	{Name: "img", Supported: []string{"aria-expanded"}, ChildrenPresentational: true},
+	{Name: "insertion", Prohibited: namingAttrs},
+	{Name: "link", Supported: linkAttrs, NameFromContent: true},
+	{Name: "list", Supported: []string{"aria-expanded"}, RequiredOwned: []string{"listitem"}},
+	{Name: "listbox", Supported: []string{"aria-activedescendant", "aria-expanded", "aria-multiselectable", "aria-orientation", "aria-readonly", "aria-required"}, RequiredOwned: []string{"group", "option"}},
+	{Name: "listitem", Supported: []string{"aria-expanded", "aria-level", "aria-posinset", "aria-setsize"}, RequiredContext: []string{"directory", "list"}},
+	{Name: "log", Supported: []string{"aria-expanded"}},
+	{Name: "main", Supported: []string{"aria-expanded"}},
+	{Name: "mark"},
+	{Name: "marquee", Supported: []string{"aria-expanded"}},
+	{Name: "math", Supported: []string{"aria-expanded"}, ChildrenPresentational: true},
+	{Name: "menu", Supported: []string{"aria-activedescendant", "aria-expanded", "aria-orientation"}, RequiredOwned: menuItems},
+	{Name: "menubar", Supported: []string{"aria-activedescendant", "aria-expanded", "aria-orientation"}, RequiredOwned: menuItems},
+	{Name: "menuitem", Supported: []string{"aria-expanded", "aria-posinset", "aria-setsize"}, RequiredContext: menuContext, NameFromContent: true},
+	{Name: "menuitemcheckbox", Required: []string{"aria-checked"}, Supported: []string{"aria-expanded", "aria-posinset", "aria-readonly", "aria-setsize"}, RequiredContext: menuContext, NameFromContent: true, ChildrenPresentational: true},
+	{Name: "menuitemradio", Required: []string{"aria-checked"}, Supported: []string{"aria-expanded", "aria-posinset", "aria-readonly", "aria-setsize"}, RequiredContext: menuContext, NameFromContent: true, ChildrenPresentational: true},
+	{Name: "meter", Required: []string{"aria-valuenow"}, Supported: rangeAttrs, ChildrenPresentational: true},
+	{Name: "navigation", Supported: []string{"aria-expanded"}},
+	{Name: "none"},
+	{Name: "note", Supported: []string{"aria-expanded"}},
+	{Name: "option", Supported: []string{"aria-checked", "aria-posinset", "aria-selected", "aria-setsize"}, RequiredContext: []string{"group", "listbox"}, NameFromContent: true, ChildrenPresentational: true},
+	{Name: "paragraph", Prohibited: namingAttrs},
+	{Name: "presentation"},
+	{Name: "progressbar", Supported: append([]string{"aria-expanded"}, rangeAttrs...), ChildrenPresentational: true},
+	{Name: "radio", Required: []string{"aria-checked"}, Supported: []string{"aria-posinset", "aria-setsize"}, NameFromContent: true, ChildrenPresentational: true},
+	{Name: "radiogroup", Supported: []string{"aria-activedescendant", "aria-expanded", "aria-orientation", "aria-readonly", "aria-required"}},
+	{Name: "region", Supported: []string{"aria-expanded"}},
+	{Name: "row", Supported: []string{"aria-activedescendant", "aria-colindex", "aria-colindextext", "aria-expanded", "aria-level", "aria-posinset", "aria-rowindex", "aria-rowindextext", "aria-selected", "aria-setsize"}, RequiredOwned: []string{"cell", "columnheader", "gridcell", "rowheader"}, RequiredContext: []string{"grid", "rowgroup", "table", "treegrid"}, NameFromContent: true},
+	{Name: "rowgroup", RequiredOwned: []string{"row"}, RequiredContext: []string{"grid", "table", "treegrid"}},
+	{Name: "rowheader", Supported: headerAttrs, RequiredContext: []string{"row"}, NameFromContent: true},
+	{Name: "scrollbar", Required: []string{"aria-controls", "aria-valuenow"}, Supported: append([]string{"aria-orientation"}, rangeAttrs...), ChildrenPresentational: true},
+	{Name: "search", Supported: []string{"aria-expanded"}},
+	{Name: "searchbox", Supported: textboxAttrs},
+	{Name: "separator", Supported: append([]string{"aria-orientation"}, rangeAttrs...), ChildrenPresentational: true},
+	{Name: "slider", Required: []string{"aria-valuenow"}, Supported: append([]string{"aria-orientation", "aria-readonly"}, rangeAttrs...), ChildrenPresentational: true},
+	{Name: "spinbutton", Supported: append([]string{"aria-activedescendant", "aria-readonly", "aria-required"}, rangeAttrs...)},
+	{Name: "status", Supported: []string{"aria-expanded"}},
+	{Name: "strong", Prohibited: namingAttrs},
+	{Name: "subscript", Prohibited: namingAttrs},
+	{Name: "suggestion", RequiredOwned: []string{"insertion", "deletion"}},
+	{Name: "superscript", Prohibited: namingAttrs},
+	{Name: "switch", Required: []string{"aria-checked"}, Supported: []string{"aria-expanded", "aria-readonly", "aria-required"}, NameFromContent: true, ChildrenPresentational: true},
+	{Name: "tab", Supported: []string{"aria-expanded", "aria-posinset", "aria-selected", "aria-setsize"}, RequiredContext: []string{"tablist"}, NameFromContent: true, ChildrenPresentational: true},
+	{Name: "table", Supported: []string{"aria-colcount", "aria-expanded", "aria-rowcount"}, RequiredOwned: []string{"row", "rowgroup"}},
+	{Name: "tablist", Supported: []string{"aria-activedescendant", "aria-expanded", "aria-multiselectable", "aria-orientation"}, RequiredOwned: []string{"tab"}},
+	{Name: "tabpanel", Supported: []string{"aria-expanded"}},
+	{Name: "term"},
+	{Name: "textbox", Supported: textboxAttrs},
+	{Name: "time"},
+	{Name: "timer", Supported: []string{"aria-expanded"}},
+	{Name: "toolbar", Supported: []string{"aria-activedescendant", "aria-expanded", "aria-orientation"}},
+	{Name: "tooltip", Supported: []string{"aria-expanded"}, NameFromContent: true},
+	{Name: "tree", Supported: []string{"aria-activedescendant", "aria-expanded", "aria-multiselectable", "aria-orientation", "aria-required"}, RequiredOwned: []string{"group", "treeitem"}},
+	{Name: "treegrid", Supported: []string{"aria-activedescendant", "aria-colcount", "aria-expanded", "aria-multiselectable", "aria-orientation", "aria-readonly", "aria-required", "aria-rowcount"}, RequiredOwned: []string{"row", "rowgroup"}},
+	{Name: "treeitem", Supported: []string{"aria-checked", "aria-expanded", "aria-level", "aria-posinset", "aria-selected", "aria-setsize"}, RequiredContext: []string{"group", "tree"}, NameFromContent: true},
+
+	// Graphics ARIA
+	{Name: "graphics-document", Supported: []string{"aria-expanded"}, ChildrenPresentational: true},
+	{Name: "graphics-object", Supported: []string{"aria-expanded"}},
+	{Name: "graphics-symbol", ChildrenPresentational: true},
+
+	// DPUB-ARIA
+	{Name: "doc-abstract"},
+	{Name: "doc-acknowledgments"},
+	{Name: "doc-afterword"},
+	{Name: "doc-appendix"},
+	{Name: "doc-backlink", Supported: linkAttrs, NameFromContent: true},
+	{Name: "doc-biblioentry", Supported: []string{"aria-level", "aria-posinset", "aria-setsize"}, RequiredContext: []string{"list"}},
+	{Name: "doc-bibliography"},
+	{Name: "doc-biblioref", Supported: linkAttrs, NameFromContent: true},
+	{Name: "doc-chapter"},
+	{Name: "doc-colophon"},
+	{Name: "doc-conclusion"},
+	{Name: "doc-cover", ChildrenPresentational: true},
+	{Name: "doc-credit"},
+	{Name: "doc-credits"},
+	{Name: "doc-dedication"},
+	{Name: "doc-endnote", Supported: []string{"aria-level", "aria-posinset", "aria-setsize"}, RequiredContext: []string{"list"}},
+	{Name: "doc-endnotes"},
+	{Name: "doc-epigraph"},
+	{Name: "doc-epilogue"},
+	{Name: "doc-errata"},
+	{Name: "doc-example"},
+	{Name: "doc-footnote"},
+	{Name: "doc-foreword"},
+	{Name: "doc-glossary"},
+	{Name: "doc-glossref", Supported: linkAttrs, NameFromContent: true},
+	{Name: "doc-index"},
+	{Name: "doc-introduction"},
+	{Name: "doc-noteref", Supported: linkAttrs, NameFromContent: true},
+	{Name: "doc-notice"},
+	{Name: "doc-pagebreak", Supported: append([]string{"aria-orientation"}, rangeAttrs...), ChildrenPresentational: true},
+	{Name: "doc-pagefooter"},
+	{Name: "doc-pageheader"},
+	{Name: "doc-pagelist"},
+	{Name: "doc-part"},
+	{Name: "doc-preface"},
+	{Name: "doc-prologue"},
+	{Name: "doc-pullquote"},
+	{Name: "doc-qna"},
+	{Name: "doc-subtitle", Supported: []string{"aria-level"}},
+	{Name: "doc-tip"},
+	{Name: "doc-toc"},
+}
+
+var roles = map[string]RoleSpec{}
+
+// LookupRole returns the spec of a role, abstract or not
+func LookupRole(role string) (RoleSpec, bool) {
+	spec, ok := roles[role]
+	return spec, ok
+}
+
+// LookupAttribute returns the spec of an aria-* state or property
+func LookupAttribute(name string) (Attribute, bool) {
+	attr, ok := attributes[name]
+	return attr, ok
+}
+
+// IsValidRole reports whether a role token is a concrete, non-abstract role
+func IsValidRole(role string) bool {
+	spec, ok := roles[role]
+	return ok && !spec.Abstract
+}
+
+// NameFromContent reports whether a role takes its name from its content
+func NameFromContent(role string) bool {
+	return roles[role].NameFromContent
+}
+
+// ChildrenPresentational reports whether a role hides its descendants from
+// assistive technologies
+func ChildrenPresentational(role string) bool {
+	return roles[role].ChildrenPresentational
+}
+
+// Allowed reports whether an attribute may be used on an element with the
+// given role
+func (r RoleSpec) Allowed(name string) bool {
+	for _, prohibited := range r.Prohibited {
+		if prohibited == name {
+			return false
+		}
+	}
+	if attr, ok := attributes[name]; ok && attr.Global {
+		return true
+	}
+	for _, list := range [][]string{r.Required, r.Supported} {
+		for _, supported := range list {
+			if supported == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ValidValue reports whether a value is valid for the attribute. ID
+// references are only checked for syntax; resolving them is up to the caller.
+func (a Attribute) ValidValue(value string) bool {
+	value = strings.TrimSpace(value)
+	switch a.Type {
+	case TypeString, TypeIDRef, TypeIDRefList:
+		return true
+	case TypeBoolean:
+		return value == "true" || value == "false"
+	case TypeTristate:
+		return value == "true" || value == "false" || value == "mixed" || value == "undefined"
+	case TypeTrueFalseUndefined:
+		return value == "true" || value == "false" || value == "undefined"
+	case TypeInteger:
+		v, err := strconv.Atoi(value)
+		return err == nil && (a.MinValue == nil || v >= *a.MinValue)
+	case TypeNumber:
+		_, err := strconv.ParseFloat(value, 64)
+		return err == nil
+	case TypeToken:
+		return containsToken(a.Values, strings.ToLower(value))
+	case TypeTokenList:
+		tokens := strings.Fields(strings.ToLower(value))
+		if len(tokens) == 0 {
+			return false
+		}
+		for _, token := range tokens {
+			if !containsToken(a.Values, token) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func containsToken(tokens []string, token string) bool {
+	for _, t := range tokens {
+		if t == token {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,331 @@
+// Package color parses CSS colors and computes WCAG 2.x relative luminance
+// and contrast ratios.
+package color
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrCurrentColor is returned by Parse for the currentcolor keyword, whose
+// value depends on the element it is used on
+var ErrCurrentColor = errors.New("currentcolor must be resolved by the caller")
+
+// RGBA is an sRGB color with 0-255 channels and 0-1 alpha
+type RGBA struct {
+	R, G, B float64
+	A       float64
+}
+
+// Common colors
+var (
+	Black       = RGBA{0, 0, 0, 1}
+	White       = RGBA{255, 255, 255, 1}
+	Transparent = RGBA{0, 0, 0, 0}
+)
+
+// colorFunctions parses the arguments of the color functions other than
+// color()
+var colorFunctions = map[string]func([]string) (RGBA, error){
+	"rgb":   parseRGB,
+	"rgba":  parseRGB,
+	"hsl":   parseHSL,
+	"hsla":  parseHSL,
+	"hwb":   parseHWB,
+	"lab":   parseLab,
+	"lch":   parseLCH,
+	"oklab": parseOKLab,
+	"oklch": parseOKLCH,
+}
+
+// Parse parses any CSS color: hex (3, 4, 6 or 8 digits), rgb()/rgba(),
+// hsl()/hsla(), hwb(), lab(), lch(), oklab(), oklch(), color() in the
+// predefined color spaces, named colors and transparent
+func Parse(value string) (RGBA, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch {
+	case value == "":
+		return RGBA{}, fmt.Errorf("empty color")
+	case value == "currentcolor":
+		return RGBA{}, ErrCurrentColor
+	case strings.HasPrefix(value, "#"):
+		return parseHex(value[1:])
+	case strings.HasSuffix(value, ")"):
+		open := strings.Index(value, "(")
+		if open < 0 {
+			return RGBA{}, fmt.Errorf("invalid color %q", value)
+		}
+		name := strings.TrimSpace(value[:open])
+		inner := strings.TrimSpace(value[open+1 : len(value)-1])
+		if name == "color" {
+			c, err := parseColorFunction(inner)
+			if err != nil {
+				return RGBA{}, fmt.Errorf("invalid color %q: %v", value, err)
+			}
+			return c, nil
+		}
+		parse, ok := colorFunctions[name]
+		if !ok {
+			return RGBA{}, fmt.Errorf("unsupported color function %q", name)
+		}
+		args, err := splitArgs(inner)
+		if err != nil {
+			return RGBA{}, fmt.Errorf("invalid color %q: %v", value, err)
+		}
+		return parse(args)
+	}
+	if c, ok := named[value]; ok {
+		return c, nil
+	}
+	return RGBA{}, fmt.Errorf("unknown color %q", value)
+}
+
+// IsColor reports whether value is a CSS color, including currentcolor
+func IsColor(value string) bool {
+	_, err := Parse(value)
+	return err == nil || errors.Is(err, ErrCurrentColor)
+}
+
+func parseHex(hex string) (RGBA, error) {
+	switch len(hex) {
+	case 3, 4:
+		expanded := make([]byte, 0, 8)
+		for i := 0; i < len(hex); i++ {
+			expanded = append(expanded, hex[i], hex[i])
+		}
+		hex = string(expanded)
+	case 6, 8:
+	default:
+		return RGBA{}, fmt.Errorf("invalid hex color #%s", hex)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return RGBA{}, fmt.Errorf("invalid hex color #%s", hex)
+	}
+	return RGBA{
+		R: float64(v >> 24 & 0xff),
+		G: float64(v >> 16 & 0xff),
+		B: float64(v >> 8 & 0xff),
+		A: float64(v&0xff) / 255,
+	}, nil
+}
+
+// splitArgs splits legacy comma syntax and modern space/slash syntax into
+// three or four components
+func splitArgs(args string) ([]string, error) {
+	args = strings.TrimSpace(args)
+	var parts []string
+	if strings.Contains(args, ",") {
+		for _, p := range strings.Split(args, ",") {
+			parts = append(parts, strings.TrimSpace(p))
+		}
+	} else {
+		main, alpha, hasAlpha := strings.Cut(args, "/")
+		parts = strings.Fields(main)
+		if hasAlpha {
+			parts = append(parts, strings.TrimSpace(alpha))
+		}
+	}
+	if len(parts) != 3 && len(parts) != 4 {
+		return nil, fmt.Errorf("expected 3 or 4 components, got %d", len(parts))
+	}
+	return parts, nil
+}
+
+// parseNumber parses a number or percentage; percentages are scaled so that
+// 100% equals full
+func parseNumber(s string, full float64) (float64, error) {
+	if s == "none" {
+		return 0, nil
+	}
+	if strings.HasSuffix(s, "%") {
+		v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+		if err != nil {
+			return 0, err
+		}
+		return v / 100 * full, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+func parseAlpha(parts []string) (float64, error) {
+	if len(parts) < 4 {
+		return 1, nil
+	}
+	a, err := parseNumber(parts[3], 1)
+	if err != nil {
+		return 0, err
+	}
+	return clamp(a, 0, 1), nil
+}
+
+func parseRGB(parts []string) (RGBA, error) {
+	var channels [3]float64
+	for i := 0; i < 3; i++ {
+		v, err := parseNumber(parts[i], 255)
+		if err != nil {
+			return RGBA{}, fmt.Errorf("invalid rgb component %q", parts[i])
+		}
+		channels[i] = clamp(v, 0, 255)
+	}
+	a, err := parseAlpha(parts)
+	if err != nil {
+		return RGBA{}, fmt.Errorf("invalid alpha %q", parts[3])
+	}
+	return RGBA{channels[0], channels[1], channels[2], a}, nil
+}
+
+// parseHue parses an angle in degrees, radians, gradians or turns
+func parseHue(s string) (float64, error) {
+	units := []struct {
+		suffix string
+		factor float64
+	}{
+		{"deg", 1},
+		{"grad", 0.9},
+		{"rad", 180 / math.Pi},
+		{"turn", 360},
+	}
+	for _, unit := range units {
+		if strings.HasSuffix(s, unit.suffix) {
+			v, err := strconv.ParseFloat(strings.TrimSuffix(s, unit.suffix), 64)
+			return v * unit.factor, err
+		}
+	}
+	if s == "none" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+func parseHSL(parts []string) (RGBA, error) {
+	h, err := parseHue(parts[0])
+	if err != nil {
+		return RGBA{}, fmt.Errorf("invalid hue %q", parts[0])
+	}
+	s, err := parseNumber(parts[1], 100)
+	if err != nil {
+		return RGBA{}, fmt.Errorf("invalid saturation %q", parts[1])
+	}
+	l, err := parseNumber(parts[2], 100)
+	if err != nil {
+		return RGBA{}, fmt.Errorf("invalid lightness %q", parts[2])
+	}
+	a, err := parseAlpha(parts)
+	if err != nil {
+		return RGBA{}, fmt.Errorf("invalid alpha %q", parts[3])
+	}
+	r, g, b := hslToRGB(h, clamp(s, 0, 100)/100, clamp(l, 0, 100)/100)
+	return RGBA{r * 255, g * 255, b * 255, a}, nil
+}
+
+// hslToRGB converts HSL to 0-1 RGB as specified in CSS Color 4
+func hslToRGB(h, s, l float64) (float64, float64, float64) {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	f := func(n float64) float64 {
+		k := math.Mod(n+h/30, 12)
+		a := s * math.Min(l, 1-l)
+		return l - a*math.Max(-1, math.Min(math.Min(k-3, 9-k), 1))
+	}
+	return f(0), f(8), f(4)
+}
+
+func parseHWB(parts []string) (RGBA, error) {
+	h, err := parseHue(parts[0])
+	if err != nil {
+		return RGBA{}, fmt.Errorf("invalid hue %q", parts[0])
+	}
+	w, err := parseNumber(parts[1], 100)
+	if err != nil {
+		return RGBA{}, fmt.Errorf("invalid whiteness %q", parts[1])
+	}
+	bl, err := parseNumber(parts[2], 100)
+	if err != nil {
+		return RGBA{}, fmt.Errorf("invalid blackness %q", parts[2])
+	}
+	a, err := parseAlpha(parts)
+	if err != nil {
+		return RGBA{}, fmt.Errorf("invalid alpha %q", parts[3])
+	}
+	w, bl = clamp(w, 0, 100)/100, clamp(bl, 0, 100)/100
+	if w+bl >= 1 {
+		gray := w / (w + bl) * 255
+		return RGBA{gray, gray, gray, a}, nil
+	}
+	r, g, b := hslToRGB(h, 1, 0.5)
+	scale := func(c float64) float64 { return (c*(1-w-bl) + w) * 255 }
+	return RGBA{scale(r), scale(g), scale(b), a}, nil
+}
+
+func clamp(v, min, max float64) float64 {
+	return math.Max(min, math.Min(max, v))
+}
+
+// Over composites c over an opaque or translucent backdrop using
+// source-over alpha compositing
+func (c RGBA) Over(backdrop RGBA) RGBA {
+	if c.A >= 1 {
+		return c
+	}
+	a := c.A + backdrop.A*(1-c.A)
+	if a == 0 {
+		return Transparent
+	}
+	mix := func(fg, bg float64) float64 {
+		return (fg*c.A + bg*backdrop.A*(1-c.A)) / a
+	}
+	return RGBA{mix(c.R, backdrop.R), mix(c.G, backdrop.G), mix(c.B, backdrop.B), a}
+}
+
+// Opaque reports whether the color has full alpha
+func (c RGBA) Opaque() bool {
+	return c.A >= 1
+}
+
+// Hex formats the color as #rrggbb, or #rrggbbaa when it is translucent
+func (c RGBA) Hex() string {
+	channel := func(v float64) int { return int(math.Round(clamp(v, 0, 255))) }
+	if c.A < 1 {
+		return fmt.Sprintf("#%02x%02x%02x%02x", channel(c.R), channel(c.G), channel(c.B), int(math.Round(c.A*255)))
+	}
+	return fmt.Sprintf("#%02x%02x%02x", channel(c.R), channel(c.G), channel(c.B))
+}
+
+// String implements fmt.Stringer
+func (c RGBA) String() string {
+	return c.Hex()
+}
+
+// linearize converts an 8-bit sRGB channel to linear light
+func linearize(channel float64) float64 {
+	c := channel / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// RelativeLuminance returns the WCAG 2.x relative luminance of the color,
+// ignoring alpha
+func (c RGBA) RelativeLuminance() float64 {
+	return 0.2126*linearize(c.R) + 0.7152*linearize(c.G) + 0.0722*linearize(c.B)
+}
+
+// ContrastRatio returns the WCAG 2.x contrast ratio between two opaque
+// colors, from 1 to 21
+func ContrastRatio(a, b RGBA) float64 {
+	l1, l2 := a.RelativeLuminance(), b.RelativeLuminance()
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05)
+}
@@ -0,0 +1,126 @@
+package color
+
+import (
+	"math"
+	"testing"
+)
+
+func TestContrastRatio(t *testing.T) {
+	tests := []struct {
+		foreground, background string
+		want                   float64
+	}{
+		{"#000", "#fff", 21},
+		{"#000000", "#ffffff", 21},
+		{"#fff", "#fff", 1},
+		{"#777", "#fff", 4.48},
+		{"#767676", "#fff", 4.54},
+		{"#595959", "#fff", 7.00},
+		{"#757575", "#000", 4.56},
+		{"red", "white", 4.00},
+		{"rgba(0, 0, 0, 0.5)", "#fff", 3.98},
+	}
+	for _, tt := range tests {
+		t.Run(tt.foreground+" on "+tt.background, func(t *testing.T) {
+			fg, err := Parse(tt.foreground)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bg, err := Parse(tt.background)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := ContrastRatio(fg.Over(bg), bg)
+			if math.Abs(got-tt.want) > 0.005 {
+				t.Errorf("contrast = %.3f, want %.2f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string // Hex of the parsed color
+	}{
+		// Hex
+		{"#abc", "#aabbcc"},
+		{"#f008", "#ff000088"},
+		{"#AbCdEf", "#abcdef"},
+		{"#11223344", "#11223344"},
+		// Named
+		{"rebeccapurple", "#663399"},
+		{"Navy", "#000080"},
+		{"transparent", "#00000000"},
+		// rgb()
+		{"rgb(255, 0, 0)", "#ff0000"},
+		{"rgba(0, 0, 255, 50%)", "#0000ff80"},
+		{"rgb(100% 50% 0% / 0.5)", "#ff800080"},
+		// hsl() and hwb()
+		{"hsl(120, 100%, 25%)", "#008000"},
+		{"hsla(0 0% 100% / 1)", "#ffffff"},
+		{"hsl(0.5turn 100% 50%)", "#00ffff"},
+		{"hwb(0 0% 0%)", "#ff0000"},
+		{"hwb(0 60% 60%)", "#808080"},
+		// lab() and lch()
+		{"lab(0 0 0)", "#000000"},
+		{"lab(100 0 0)", "#ffffff"},
+		{"lab(54.29 80.8 69.89)", "#ff0000"},
+		{"lab(29.2345% 39.3825 20.0664)", "#7d2329"},
+		{"lab(32.393 38.428 -47.69)", "#663399"},
+		{"lch(54.29 106.84 40.85)", "#ff0000"},
+		{"lch(29.2345% 44.2 27)", "#7d2329"},
+		{"lch(32.393 61.24 308.86 / 0.5)", "#66339980"},
+		// oklab() and oklch()
+		{"oklab(1 0 0)", "#ffffff"},
+		{"oklab(0.628 0.2249 0.1258)", "#ff0000"},
+		{"oklch(62.8% 0.2577 29.23)", "#ff0000"},
+		{"oklab(40.101% 0.1147 0.0453)", "#7d2329"},
+		{"oklch(59.686% 0.15619 49.7694)", "#c65d06"},
+		{"oklch(0.4403 0.1603 303.37)", "#663399"},
+		// color()
+		{"color(srgb 1 0 0)", "#ff0000"},
+		{"color(srgb 40% 20% 60% / 0.5)", "#66339980"},
+		{"color(srgb-linear 0.2158 0.2158 0.2158)", "#808080"},
+		{"color(display-p3 1 0 0)", "#ff0000"}, // outside sRGB, clipped
+		{"color(display-p3 0.9175 0.2003 0.1386)", "#ff0000"},
+		{"color(rec2020 0 0 0)", "#000000"},
+		{"color(a98-rgb 1 1 1)", "#ffffff"},
+		{"color(prophoto-rgb 1 1 1)", "#ffffff"},
+		{"color(xyz-d65 0.9505 1 1.089)", "#ffffff"},
+		{"color(xyz-d50 0.9642 1 0.8252)", "#ffffff"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			c, err := Parse(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := Parse(tt.want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Allow one step of rounding in converted color spaces
+			for _, d := range []float64{c.R - want.R, c.G - want.G, c.B - want.B, (c.A - want.A) * 255} {
+				if math.Abs(d) > 1.5 {
+					t.Errorf("Parse(%q) = %s, want %s", tt.value, c.Hex(), tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, value := range []string{
+		"", "#12", "#ggg", "notacolor", "rgb(1, 2)", "hsl(red 1 2)",
+		"lab(a b c)", "color(cmyk 0 0 0)", "color-mix(in srgb, red, blue)",
+	} {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", value)
+		}
+	}
+	if _, err := Parse("currentColor"); err != ErrCurrentColor {
+		t.Errorf("Parse(currentColor) = %v, want ErrCurrentColor", err)
+	}
+}
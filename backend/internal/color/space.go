@@ -0,0 +1,278 @@
+package color
+
+import (
+	"fmt"
+	"math"
+)
+
+// CSS Color 4 colors outside sRGB: lab(), lch(), oklab(), oklch() and
+// color(). They are converted through CIE XYZ to sRGB and clipped to its
+// gamut, which is what WCAG 2.x contrast is defined on.
+
+type vector [3]float64
+
+type matrix [3]vector
+
+func (m matrix) apply(v vector) vector {
+	var out vector
+	for i, row := range m {
+		out[i] = row[0]*v[0] + row[1]*v[1] + row[2]*v[2]
+	}
+	return out
+}
+
+var (
+	// d50ToD65 is the Bradford chromatic adaptation from the D50 white
+	// point of Lab and ProPhoto to the D65 white point of sRGB
+	d50ToD65 = matrix{
+		{0.955473421488075, -0.02309845494876471, 0.06325924320057072},
+		{-0.0283697093338637, 1.0099953980813041, 0.021041441191917323},
+		{0.012314014864481998, -0.020507649298898964, 1.330365926242124},
+	}
+	xyzToLinearSRGB = matrix{
+		{3.2409699419045226, -1.537383177570094, -0.4986107602930034},
+		{-0.9692436362808796, 1.8759675015077202, 0.04155505740717559},
+		{0.05563007969699366, -0.20397695888897652, 1.0569715142428786},
+	}
+	linearSRGBToXYZ = matrix{
+		{0.41239079926595934, 0.357584339383878, 0.1804807884018343},
+		{0.21263900587151027, 0.715168678767756, 0.07219231536073371},
+		{0.01933081871559182, 0.11919477979462598, 0.9505321522496607},
+	}
+	displayP3ToXYZ = matrix{
+		{0.4865709486482162, 0.26566769316909306, 0.1982172852343625},
+		{0.2289745640697488, 0.6917385218365064, 0.079286914093745},
+		{0, 0.04511338185890264, 1.043944368900976},
+	}
+	a98RGBToXYZ = matrix{
+		{0.5766690429101305, 0.1855582379065463, 0.1882286462349947},
+		{0.29734497525053605, 0.6273635662554661, 0.07529145849399788},
+		{0.02703136138641234, 0.07068885253582723, 0.9913375368376388},
+	}
+	// proPhotoToXYZ converts to D50 XYZ
+	proPhotoToXYZ = matrix{
+		{0.7977604896723027, 0.13518583717574031, 0.0313493495815248},
+		{0.2880711282292934, 0.7118432178101014, 0.00008565396060525902},
+		{0, 0, 0.8251046025104601},
+	}
+	rec2020ToXYZ = matrix{
+		{0.6369580483012914, 0.14461690358620832, 0.1688809751641721},
+		{0.2627002120112671, 0.6779980715188708, 0.05930171646986196},
+		{0, 0.028072693049087428, 1.060985057710791},
+	}
+)
+
+// fromXYZ converts D65 XYZ to an sRGB color clipped to its gamut
+func fromXYZ(xyz vector, alpha float64) RGBA {
+	return fromLinearSRGB(xyzToLinearSRGB.apply(xyz), alpha)
+}
+
+func fromLinearSRGB(rgb vector, alpha float64) RGBA {
+	channel := func(v float64) float64 { return clamp(srgbGamma(v), 0, 1) * 255 }
+	return RGBA{channel(rgb[0]), channel(rgb[1]), channel(rgb[2]), alpha}
+}
+
+// srgbGamma encodes a linear light sRGB channel
+func srgbGamma(v float64) float64 {
+	sign, abs := math.Copysign(1, v), math.Abs(v)
+	if abs <= 0.0031308 {
+		return v * 12.92
+	}
+	return sign * (1.055*math.Pow(abs, 1/2.4) - 0.055)
+}
+
+// srgbLinear decodes an sRGB channel, also used by Display P3
+func srgbLinear(v float64) float64 {
+	sign, abs := math.Copysign(1, v), math.Abs(v)
+	if abs <= 0.04045 {
+		return v / 12.92
+	}
+	return sign * math.Pow((abs+0.055)/1.055, 2.4)
+}
+
+// labToXYZ converts CIE Lab to D50 XYZ
+func labToXYZ(l, a, b float64) vector {
+	const (
+		kappa   = 24389.0 / 27
+		epsilon = 216.0 / 24389
+	)
+	f1 := (l + 16) / 116
+	f0 := a/500 + f1
+	f2 := f1 - b/200
+
+	x := (116*f0 - 16) / kappa
+	if cube := f0 * f0 * f0; cube > epsilon {
+		x = cube
+	}
+	y := l / kappa
+	if l > kappa*epsilon {
+		y = f1 * f1 * f1
+	}
+	z := (116*f2 - 16) / kappa
+	if cube := f2 * f2 * f2; cube > epsilon {
+		z = cube
+	}
+	// Scale by the D50 white point
+	return vector{x * 0.3457 / 0.3585, y, z * (1 - 0.3457 - 0.3585) / 0.3585}
+}
+
+// oklabToLinearSRGB converts OKLab to linear light sRGB
+func oklabToLinearSRGB(l, a, b float64) vector {
+	lms := vector{
+		l + 0.3963377774*a + 0.2158037573*b,
+		l - 0.1055613458*a - 0.0638541728*b,
+		l - 0.0894841775*a - 1.2914855480*b,
+	}
+	for i, v := range lms {
+		lms[i] = v * v * v
+	}
+	return matrix{
+		{4.0767416621, -3.3077115913, 0.2309699292},
+		{-1.2684380046, 2.6097574011, -0.3413193965},
+		{-0.0041960863, -0.7034186147, 1.7076147010},
+	}.apply(lms)
+}
+
+// polar converts a chroma and hue in degrees to a and b
+func polar(c, h float64) (float64, float64) {
+	c = math.Max(c, 0)
+	rad := h * math.Pi / 180
+	return c * math.Cos(rad), c * math.Sin(rad)
+}
+
+// parseComponents parses the three components of a color function, each
+// scaled so that 100% equals the given full value. A full value of 0 marks
+// a hue.
+func parseComponents(name string, parts []string, full vector) (vector, float64, error) {
+	var v vector
+	for i := 0; i < 3; i++ {
+		var err error
+		if full[i] == 0 {
+			v[i], err = parseHue(parts[i])
+		} else {
+			v[i], err = parseNumber(parts[i], full[i])
+		}
+		if err != nil {
+			return v, 0, fmt.Errorf("invalid %s component %q", name, parts[i])
+		}
+	}
+	a, err := parseAlpha(parts)
+	if err != nil {
+		return v, 0, fmt.Errorf("invalid alpha %q", parts[3])
+	}
+	return v, a, nil
+}
+
+func parseLab(parts []string) (RGBA, error) {
+	v, a, err := parseComponents("lab", parts, vector{100, 125, 125})
+	if err != nil {
+		return RGBA{}, err
+	}
+	return fromXYZ(d50ToD65.apply(labToXYZ(clamp(v[0], 0, 100), v[1], v[2])), a), nil
+}
+
+func parseLCH(parts []string) (RGBA, error) {
+	v, a, err := parseComponents("lch", parts, vector{100, 150, 0})
+	if err != nil {
+		return RGBA{}, err
+	}
+	labA, labB := polar(v[1], v[2])
+	return fromXYZ(d50ToD65.apply(labToXYZ(clamp(v[0], 0, 100), labA, labB)), a), nil
+}
+
+func parseOKLab(parts []string) (RGBA, error) {
+	v, a, err := parseComponents("oklab", parts, vector{1, 0.4, 0.4})
+	if err != nil {
+		return RGBA{}, err
+	}
+	return fromLinearSRGB(oklabToLinearSRGB(clamp(v[0], 0, 1), v[1], v[2]), a), nil
+}
+
+func parseOKLCH(parts []string) (RGBA, error) {
+	v, a, err := parseComponents("oklch", parts, vector{1, 0.4, 0})
+	if err != nil {
+		return RGBA{}, err
+	}
+	labA, labB := polar(v[1], v[2])
+	return fromLinearSRGB(oklabToLinearSRGB(clamp(v[0], 0, 1), labA, labB), a), nil
+}
+
+// rgbSpace is a predefined RGB color space of color()
+type rgbSpace struct {
+	toLinear func(float64) float64
+	toXYZ    matrix
+	d50      bool
+}
+
+var rgbSpaces = map[string]rgbSpace{
+	"srgb":         {srgbLinear, linearSRGBToXYZ, false},
+	"srgb-linear":  {func(v float64) float64 { return v }, linearSRGBToXYZ, false},
+	"display-p3":   {srgbLinear, displayP3ToXYZ, false},
+	"a98-rgb":      {a98Linear, a98RGBToXYZ, false},
+	"prophoto-rgb": {proPhotoLinear, proPhotoToXYZ, true},
+	"rec2020":      {rec2020Linear, rec2020ToXYZ, false},
+}
+
+func a98Linear(v float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(v), 563.0/256), v)
+}
+
+func proPhotoLinear(v float64) float64 {
+	if math.Abs(v) <= 16.0/512 {
+		return v / 16
+	}
+	return math.Copysign(math.Pow(math.Abs(v), 1.8), v)
+}
+
+func rec2020Linear(v float64) float64 {
+	const (
+		alpha = 1.09929682680944
+		beta  = 0.018053968510807
+	)
+	if math.Abs(v) < beta*4.5 {
+		return v / 4.5
+	}
+	return math.Copysign(math.Pow((math.Abs(v)+alpha-1)/alpha, 1/0.45), v)
+}
+
+// parseColorFunction parses color(space c1 c2 c3 [/ alpha])
+func parseColorFunction(args string) (RGBA, error) {
+	space, rest, _ := cutSpace(args)
+	parts, err := splitArgs(rest)
+	if err != nil {
+		return RGBA{}, err
+	}
+	v, a, err := parseComponents("color", parts, vector{1, 1, 1})
+	if err != nil {
+		return RGBA{}, err
+	}
+
+	switch space {
+	case "xyz", "xyz-d65":
+		return fromXYZ(v, a), nil
+	case "xyz-d50":
+		return fromXYZ(d50ToD65.apply(v), a), nil
+	}
+	rgb, ok := rgbSpaces[space]
+	if !ok {
+		return RGBA{}, fmt.Errorf("unsupported color space %q", space)
+	}
+	for i := range v {
+		v[i] = rgb.toLinear(v[i])
+	}
+	xyz := rgb.toXYZ.apply(v)
+	if rgb.d50 {
+		xyz = d50ToD65.apply(xyz)
+	}
+	return fromXYZ(xyz, a), nil
+}
+
+// cutSpace splits off the first space-separated word
+func cutSpace(s string) (string, string, bool) {
+	for i, r := range s {
+		if r == ' ' || r == '\t' || r == '\n' {
+			return s[:i], s[i+1:], true
+		}
+	}
+	return s, "", false
+}
@@ -0,0 +1,460 @@
+package css
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"tokubetsu/internal/color"
+	"tokubetsu/internal/dom"
+
+	"golang.org/x/net/html"
+)
+
+// inheritedProperties are the properties an element takes from its parent
+// when no rule sets them
+var inheritedProperties = map[string]bool{
+	"color":           true,
+	"font-family":     true,
+	"font-size":       true,
+	"font-style":      true,
+	"font-weight":     true,
+	"letter-spacing":  true,
+	"line-height":     true,
+	"text-align":      true,
+	"text-transform":  true,
+	"visibility":      true,
+	"white-space":     true,
+	"word-spacing":    true,
+	"direction":       true,
+	"cursor":          true,
+	"list-style-type": true,
+}
+
+// userAgentCSS is the subset of the browser default stylesheet that affects
+// visibility, colors and text size
+const userAgentCSS = `
+head, script, style, template, title, meta, link, noscript, datalist, param, [hidden], area, base { display: none }
+input[type=hidden] { display: none }
+html { color: #000000; background-color: transparent; font-size: 16px; font-weight: 400 }
+a:link { color: #0000ee }
+b, strong, th { font-weight: bold }
+h1 { font-size: 2em; font-weight: bold }
+h2 { font-size: 1.5em; font-weight: bold }
+h3 { font-size: 1.17em; font-weight: bold }
+h4 { font-size: 1em; font-weight: bold }
+h5 { font-size: 0.83em; font-weight: bold }
+h6 { font-size: 0.67em; font-weight: bold }
+small, sub, sup { font-size: smaller }
+big { font-size: larger }
+button, input, select, textarea { font-size: 13.3333px }
+mark { background-color: #ffff00; color: #000000 }
+`
+
+var (
+	userAgentOnce  sync.Once
+	userAgentSheet *Stylesheet
+)
+
+func defaultSheet() *Stylesheet {
+	userAgentOnce.Do(func() {
+		userAgentSheet = ParseStylesheet(userAgentCSS)
+	})
+	return userAgentSheet
+}
+
+// Cascade origins, in increasing precedence for normal declarations
+const (
+	originUserAgent = iota
+	originPresentational
+	originAuthor
+	originInline
+)
+
+type cascadeRule struct {
+	selector     *Selector
+	declarations []Declaration
+	origin       int
+	order        int
+}
+
+type matchedDeclaration struct {
+	Declaration
+	origin      int
+	specificity Specificity
+	order       int
+}
+
+// Style is the computed style of an element
+type Style struct {
+	props map[string]string
+
+	// FontSize is the computed font size in CSS pixels
+	FontSize float64
+	// FontWeight is the computed numeric font weight
+	FontWeight int
+
+	rootFontSize float64
+}
+
+// Get returns the computed value of a property, or "" when it is not set
+func (s *Style) Get(property string) string {
+	return s.props[property]
+}
+
+// Color returns the computed foreground color
+func (s *Style) Color() string {
+	return s.props["color"]
+}
+
+// ForegroundColor parses the computed foreground color. It reports false
+// when the value is not a valid color.
+func (s *Style) ForegroundColor() (color.RGBA, bool) {
+	c, err := color.Parse(s.props["color"])
+	if err != nil {
+		return color.RGBA{}, false
+	}
+	return c, true
+}
+
+// Display returns the computed display value
+func (s *Style) Display() string {
+	if display := s.props["display"]; display != "" {
+		return display
+	}
+	return "inline"
+}
+
+// Length resolves a length property of the element to pixels
+func (s *Style) Length(property string) (float64, bool) {
+	return ParseLength(s.props[property], s.FontSize, s.rootFontSize)
+}
+
+// Size returns the element's specified width and height in pixels. Each
+// dimension is the larger of its size and min-size, and reports false when
+// neither can be resolved without layout.
+func (s *Style) Size() (width, height float64, widthOK, heightOK bool) {
+	width, widthOK = s.Length("width")
+	if min, ok := s.Length("min-width"); ok && (!widthOK || min > width) {
+		width, widthOK = min, true
+	}
+	height, heightOK = s.Length("height")
+	if min, ok := s.Length("min-height"); ok && (!heightOK || min > height) {
+		height, heightOK = min, true
+	}
+	return width, height, widthOK, heightOK
+}
+
+// Resolver computes the styles of the elements of one document. It caches
+// results and is not safe for concurrent use.
+type Resolver struct {
+	rules []cascadeRule
+	cache map[*html.Node]*Style
+}
+
+// NewResolver creates a resolver for a document styled by the given author
+// stylesheets, in document order
+func NewResolver(sheets ...*Stylesheet) *Resolver {
+	r := &Resolver{cache: make(map[*html.Node]*Style)}
+	r.addSheet(defaultSheet(), originUserAgent)
+	for _, sheet := range sheets {
+		r.addSheet(sheet, originAuthor)
+	}
+	return r
+}
+
+func (r *Resolver) addSheet(sheet *Stylesheet, origin int) {
+	for _, rule := range sheet.Rules {
+		for _, sel := range rule.Selectors {
+			r.rules = append(r.rules, cascadeRule{
+				selector:     sel,
+				declarations: rule.Declarations,
+				origin:       origin,
+				order:        len(r.rules),
+			})
+		}
+	}
+}
+
+// Style returns the computed style of an element
+func (r *Resolver) Style(n *html.Node) *Style {
+	if style, ok := r.cache[n]; ok {
+		return style
+	}
+
+	var parent *Style
+	if p := parentElement(n); p != nil {
+		parent = r.Style(p)
+	} else {
+		parent = &Style{
+			props:        map[string]string{"color": "#000000"},
+			FontSize:     DefaultFontSize,
+			FontWeight:   400,
+			rootFontSize: DefaultFontSize,
+		}
+	}
+
+	style := &Style{
+		props:        make(map[string]string),
+		FontSize:     parent.FontSize,
+		FontWeight:   parent.FontWeight,
+		rootFontSize: parent.rootFontSize,
+	}
+	for prop := range inheritedProperties {
+		if val, ok := parent.props[prop]; ok {
+			style.props[prop] = val
+		}
+	}
+
+	for _, decl := range r.matchedDeclarations(n) {
+		for _, expanded := range expandShorthand(decl.Declaration) {
+			style.apply(expanded, parent)
+		}
+	}
+
+	if parentElement(n) == nil {
+		// The root element defines the size rem units are relative to
+		style.rootFontSize = style.FontSize
+	}
+
+	r.cache[n] = style
+	return style
+}
+
+func (s *Style) apply(decl Declaration, parent *Style) {
+	value := decl.Value
+	switch strings.ToLower(value) {
+	case "inherit":
+		s.inherit(decl.Property, parent)
+		return
+	case "initial":
+		s.reset(decl.Property)
+		return
+	case "unset", "revert":
+		if inheritedProperties[decl.Property] {
+			s.inherit(decl.Property, parent)
+		} else {
+			s.reset(decl.Property)
+		}
+		return
+	}
+
+	switch decl.Property {
+	case "font-size":
+		size, ok := parseFontSize(value, parent.FontSize, parent.rootFontSize)
+		if !ok {
+			return
+		}
+		s.FontSize = size
+	case "font-weight":
+		weight, ok := parseFontWeight(value, parent.FontWeight)
+		if !ok {
+			return
+		}
+		s.FontWeight = weight
+	case "color":
+		if strings.EqualFold(value, "currentcolor") {
+			value = parent.props["color"]
+		}
+	}
+	s.props[decl.Property] = value
+}
+
+// inherit takes the parent's computed value of a property. Font sizes and
+// weights are copied as computed, since resolving the parent's declared value
+// again would apply relative units such as em twice.
+func (s *Style) inherit(property string, parent *Style) {
+	switch property {
+	case "font-size":
+		s.FontSize = parent.FontSize
+	case "font-weight":
+		s.FontWeight = parent.FontWeight
+	}
+	if value, ok := parent.props[property]; ok {
+		s.props[property] = value
+	} else {
+		delete(s.props, property)
+	}
+}
+
+// reset sets a property to its initial value
+func (s *Style) reset(property string) {
+	switch property {
+	case "font-size":
+		s.FontSize = DefaultFontSize
+	case "font-weight":
+		s.FontWeight = 400
+	}
+	delete(s.props, property)
+}
+
+// matchedDeclarations returns the declarations that apply to n in cascade
+// order, so that later declarations win
+func (r *Resolver) matchedDeclarations(n *html.Node) []matchedDeclaration {
+	var matched []matchedDeclaration
+	for _, rule := range r.rules {
+		if !rule.selector.Matches(n) {
+			continue
+		}
+		for _, decl := range rule.declarations {
+			matched = append(matched, matchedDeclaration{
+				Declaration: decl,
+				origin:      rule.origin,
+				specificity: rule.selector.specificity,
+				order:       rule.order,
+			})
+		}
+	}
+
+	for _, decl := range presentationalHints(n) {
+		matched = append(matched, matchedDeclaration{Declaration: decl, origin: originPresentational})
+	}
+	if style, ok := dom.LookupAttr(n, "style"); ok {
+		for _, decl := range ParseDeclarations(style) {
+			matched = append(matched, matchedDeclaration{Declaration: decl, origin: originInline})
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		if a.Important != b.Important {
+			return !a.Important
+		}
+		if a.origin != b.origin {
+			return a.origin < b.origin
+		}
+		if a.specificity != b.specificity {
+			return a.specificity.Less(b.specificity)
+		}
+		return a.order < b.order
+	})
+	return matched
+}
+
+// presentationalHints maps legacy presentational attributes to declarations
+func presentationalHints(n *html.Node) []Declaration {
+	var decls []Declaration
+	if bg, ok := dom.LookupAttr(n, "bgcolor"); ok {
+		decls = append(decls, Declaration{Property: "background-color", Value: bg})
+	}
+	if n.Data == "font" {
+		if color, ok := dom.LookupAttr(n, "color"); ok {
+			decls = append(decls, Declaration{Property: "color", Value: color})
+		}
+	}
+	if n.Data == "body" {
+		if text, ok := dom.LookupAttr(n, "text"); ok {
+			decls = append(decls, Declaration{Property: "color", Value: text})
+		}
+	}
+	for _, dim := range []string{"width", "height"} {
+		if val, ok := dom.LookupAttr(n, dim); ok {
+			val = strings.TrimSpace(val)
+			if _, ok := ParseLength(val+"px", DefaultFontSize, DefaultFontSize); ok {
+				decls = append(decls, Declaration{Property: dim, Value: val + "px"})
+			}
+		}
+	}
+	return decls
+}
+
+// expandShorthand expands the shorthands that carry colors or font sizes
+func expandShorthand(decl Declaration) []Declaration {
+	switch decl.Property {
+	case "background":
+		expanded := []Declaration{
+			{Property: "background-color", Value: "transparent", Important: decl.Important},
+			{Property: "background-image", Value: "none", Important: decl.Important},
+		}
+		if lower := strings.ToLower(decl.Value); lower == "inherit" || lower == "initial" || lower == "unset" {
+			expanded[0].Value, expanded[1].Value = decl.Value, decl.Value
+			return expanded
+		}
+		// Only the final layer may carry a color
+		layers := splitTopLevel(decl.Value, ',')
+		for i, layer := range layers {
+			for _, component := range splitValue(layer) {
+				switch {
+				case isImageValue(component):
+					expanded[1].Value = component
+				case i == len(layers)-1 && color.IsColor(component):
+					expanded[0].Value = component
+				}
+			}
+		}
+		return expanded
+	case "font":
+		var expanded []Declaration
+		for _, component := range splitValue(decl.Value) {
+			size := component
+			if i := strings.Index(size, "/"); i >= 0 {
+				size = size[:i]
+			}
+			if _, ok := parseFontSize(size, DefaultFontSize, DefaultFontSize); ok {
+				expanded = append(expanded, Declaration{Property: "font-size", Value: size, Important: decl.Important})
+				// The family follows the size, so no weight can come after it
+				break
+			}
+			if _, ok := parseFontWeight(component, 400); ok {
+				expanded = append(expanded, Declaration{Property: "font-weight", Value: component, Important: decl.Important})
+			}
+		}
+		return expanded
+	}
+	return []Declaration{decl}
+}
+
+// Hidden reports whether an element is not rendered because it or an
+// ancestor has display: none, or it has visibility: hidden
+func (r *Resolver) Hidden(n *html.Node) bool {
+	if v := r.Style(n).Get("visibility"); v == "hidden" || v == "collapse" {
+		return true
+	}
+	for e := n; e != nil && e.Type == html.ElementNode; e = e.Parent {
+		if r.Style(e).Display() == "none" {
+			return true
+		}
+	}
+	return false
+}
+
+// Background returns the effective opaque background color behind an
+// element's text. Translucent backgrounds of the element and its ancestors
+// are composited down to the first opaque one, or to the white canvas. It
+// reports false when a background image or a color it cannot parse lies in
+// between, since the color behind the text is then unknown.
+func (r *Resolver) Background(n *html.Node) (color.RGBA, bool) {
+	var layers []color.RGBA
+	for e := n; e != nil && e.Type == html.ElementNode; e = e.Parent {
+		style := r.Style(e)
+		if image := style.Get("background-image"); image != "" && image != "none" {
+			return color.RGBA{}, false
+		}
+		value := style.Get("background-color")
+		if strings.EqualFold(value, "currentcolor") {
+			value = style.Color()
+		}
+		if value == "" {
+			continue
+		}
+		bg, err := color.Parse(value)
+		if err != nil {
+			// An unsupported color such as color-mix() hides what is behind
+			// the text as much as an image does
+			return color.RGBA{}, false
+		}
+		if bg.A == 0 {
+			continue
+		}
+		layers = append(layers, bg)
+		if bg.Opaque() {
+			break
+		}
+	}
+
+	result := color.White
+	for i := len(layers) - 1; i >= 0; i-- {
+		result = layers[i].Over(result)
+	}
+	return result, true
+}
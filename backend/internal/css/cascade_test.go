@@ -0,0 +1,53 @@
+package css
+
+import (
+	"strings"
+	"testing"
+
+	"tokubetsu/internal/dom"
+
+	"golang.org/x/net/html"
+)
+
+func TestFontSizeKeywords(t *testing.T) {
+	tests := []struct {
+		name  string
+		style string
+		want  float64
+	}{
+		{"em parent, inherit child", "#child { font-size: inherit }", 32},
+		{"em parent, unset child", "#child { font-size: unset }", 32},
+		{"em parent, em child", "#child { font-size: 1.5em }", 48},
+		{"em parent, percent child", "#child { font-size: 50% }", 16},
+		{"em parent, initial child", "#child { font-size: initial }", DefaultFontSize},
+		{"em parent, no declaration", "", 32},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := `<div id="parent"><p id="child">Text</p></div>`
+			doc, err := html.Parse(strings.NewReader(source))
+			if err != nil {
+				t.Fatal(err)
+			}
+			sheet := ParseStylesheet("#parent { font-size: 2em }\n" + tt.style)
+			child := dom.Find(doc, "p")
+
+			if got := NewResolver(sheet).Style(child).FontSize; got != tt.want {
+				t.Errorf("font-size = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFontWeightInherit(t *testing.T) {
+	doc, err := html.Parse(strings.NewReader(`<div id="parent"><span id="child">Text</span></div>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheet := ParseStylesheet("#parent { font-weight: bolder }\n#child { font-weight: inherit }")
+	child := dom.Find(doc, "span")
+
+	if got := NewResolver(sheet).Style(child).FontWeight; got != 700 {
+		t.Errorf("font-weight = %v, want 700", got)
+	}
+}
@@ -0,0 +1,571 @@
+package css
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"tokubetsu/internal/dom"
+
+	"golang.org/x/net/html"
+)
+
+// Specificity is a selector's (id, class, type) specificity
+type Specificity [3]int
+
+// Less reports whether a has lower specificity than b
+func (a Specificity) Less(b Specificity) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+func (a Specificity) add(b Specificity) Specificity {
+	return Specificity{a[0] + b[0], a[1] + b[1], a[2] + b[2]}
+}
+
+// Selector is a complex selector such as "nav > ul li.active"
+type Selector struct {
+	compounds   []compound // left to right
+	combinators []byte     // combinators[i] joins compounds[i] and compounds[i+1]
+	specificity Specificity
+	// Selectors targeting pseudo-elements never style the element itself
+	pseudoElement bool
+}
+
+type compound struct {
+	tag     string // "" matches any element
+	ids     []string
+	classes []string
+	attrs   []attrSelector
+	pseudos []pseudoClass
+}
+
+type attrSelector struct {
+	name        string
+	op          string // "", "=", "~=", "|=", "^=", "$=", "*="
+	value       string
+	insensitive bool
+}
+
+type pseudoClass struct {
+	name string
+	args []*Selector // for :not, :is, :where
+	a, b int         // for the :nth-* family
+}
+
+// Specificity returns the selector's specificity
+func (s *Selector) Specificity() Specificity {
+	return s.specificity
+}
+
+// ParseSelectorList parses a comma-separated selector list. An invalid
+// selector invalidates the whole list, as in a stylesheet.
+func ParseSelectorList(text string) ([]*Selector, error) {
+	var selectors []*Selector
+	for _, part := range splitTopLevel(text, ',') {
+		sel, err := parseSelector(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, sel)
+	}
+	return selectors, nil
+}
+
+type selectorParser struct {
+	text string
+	pos  int
+}
+
+func parseSelector(text string) (*Selector, error) {
+	if text == "" {
+		return nil, fmt.Errorf("empty selector")
+	}
+	p := &selectorParser{text: text}
+	sel := &Selector{}
+
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.text) {
+			break
+		}
+
+		if len(sel.compounds) > 0 {
+			combinator := byte(' ')
+			if c := p.text[p.pos]; c == '>' || c == '+' || c == '~' {
+				combinator = c
+				p.pos++
+				p.skipSpace()
+			} else if !isSpace(p.text[p.pos-1]) {
+				return nil, fmt.Errorf("unexpected %q in selector %q", p.text[p.pos], text)
+			}
+			sel.combinators = append(sel.combinators, combinator)
+		}
+
+		comp, spec, pseudoElement, err := p.parseCompound()
+		if err != nil {
+			return nil, err
+		}
+		sel.compounds = append(sel.compounds, comp)
+		sel.specificity = sel.specificity.add(spec)
+		if pseudoElement {
+			sel.pseudoElement = true
+		}
+	}
+
+	if len(sel.compounds) == 0 || len(sel.combinators) != len(sel.compounds)-1 {
+		return nil, fmt.Errorf("invalid selector %q", text)
+	}
+	return sel, nil
+}
+
+func (p *selectorParser) skipSpace() {
+	for p.pos < len(p.text) && isSpace(p.text[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *selectorParser) parseCompound() (compound, Specificity, bool, error) {
+	var comp compound
+	var spec Specificity
+	pseudoElement := false
+	start := p.pos
+
+	if p.pos < len(p.text) && p.text[p.pos] == '*' {
+		p.pos++
+	} else if name := p.ident(); name != "" {
+		comp.tag = strings.ToLower(name)
+		spec[2]++
+	}
+
+	for p.pos < len(p.text) {
+		switch p.text[p.pos] {
+		case '#':
+			p.pos++
+			id := p.ident()
+			if id == "" {
+				return comp, spec, false, fmt.Errorf("empty id selector")
+			}
+			comp.ids = append(comp.ids, id)
+			spec[0]++
+		case '.':
+			p.pos++
+			class := p.ident()
+			if class == "" {
+				return comp, spec, false, fmt.Errorf("empty class selector")
+			}
+			comp.classes = append(comp.classes, class)
+			spec[1]++
+		case '[':
+			attr, err := p.parseAttr()
+			if err != nil {
+				return comp, spec, false, err
+			}
+			comp.attrs = append(comp.attrs, attr)
+			spec[1]++
+		case ':':
+			p.pos++
+			if p.pos < len(p.text) && p.text[p.pos] == ':' {
+				p.pos++
+				p.ident()
+				p.skipArgs()
+				pseudoElement = true
+				spec[2]++
+				continue
+			}
+			name := strings.ToLower(p.ident())
+			switch name {
+			case "before", "after", "first-line", "first-letter":
+				// Legacy single-colon pseudo-elements
+				pseudoElement = true
+				spec[2]++
+				continue
+			}
+			pseudo, pseudoSpec, err := p.parsePseudo(name)
+			if err != nil {
+				return comp, spec, false, err
+			}
+			comp.pseudos = append(comp.pseudos, pseudo)
+			spec = spec.add(pseudoSpec)
+		default:
+			if p.pos == start {
+				return comp, spec, false, fmt.Errorf("unexpected %q in selector", p.text[p.pos])
+			}
+			return comp, spec, pseudoElement, nil
+		}
+	}
+	if p.pos == start {
+		return comp, spec, false, fmt.Errorf("empty compound selector")
+	}
+	return comp, spec, pseudoElement, nil
+}
+
+func (p *selectorParser) ident() string {
+	var sb strings.Builder
+	for p.pos < len(p.text) {
+		c := p.text[p.pos]
+		switch {
+		case c == '\\' && p.pos+1 < len(p.text):
+			sb.WriteByte(p.text[p.pos+1])
+			p.pos += 2
+		case c == '-' || c == '_' || c >= 0x80 ||
+			(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9'):
+			sb.WriteByte(c)
+			p.pos++
+		default:
+			return sb.String()
+		}
+	}
+	return sb.String()
+}
+
+func (p *selectorParser) parseAttr() (attrSelector, error) {
+	end := indexTopLevel(p.text[p.pos+1:], "]")
+	if end < 0 {
+		return attrSelector{}, fmt.Errorf("unterminated attribute selector")
+	}
+	body := strings.TrimSpace(p.text[p.pos+1 : p.pos+1+end])
+	p.pos += end + 2
+
+	var attr attrSelector
+	for _, op := range []string{"~=", "|=", "^=", "$=", "*=", "="} {
+		if i := strings.Index(body, op); i >= 0 {
+			attr.name = strings.ToLower(strings.TrimSpace(body[:i]))
+			attr.op = op
+			value := strings.TrimSpace(body[i+len(op):])
+			if strings.HasSuffix(value, " i") || strings.HasSuffix(value, " I") {
+				attr.insensitive = true
+				value = strings.TrimSpace(value[:len(value)-2])
+			}
+			attr.value = strings.Trim(value, `"'`)
+			break
+		}
+	}
+	if attr.op == "" {
+		attr.name = strings.ToLower(body)
+	}
+	if attr.name == "" {
+		return attr, fmt.Errorf("empty attribute selector")
+	}
+	return attr, nil
+}
+
+// skipArgs skips a parenthesized argument list if one follows
+func (p *selectorParser) skipArgs() string {
+	if p.pos >= len(p.text) || p.text[p.pos] != '(' {
+		return ""
+	}
+	end := indexTopLevel(p.text[p.pos+1:], ")")
+	if end < 0 {
+		p.pos = len(p.text)
+		return ""
+	}
+	args := p.text[p.pos+1 : p.pos+1+end]
+	p.pos += end + 2
+	return args
+}
+
+func (p *selectorParser) parsePseudo(name string) (pseudoClass, Specificity, error) {
+	pseudo := pseudoClass{name: name}
+	args := p.skipArgs()
+
+	switch name {
+	case "not", "is", "matches", "where", "any", "-webkit-any":
+		list, err := ParseSelectorList(args)
+		if err != nil {
+			return pseudo, Specificity{}, err
+		}
+		pseudo.args = list
+		if name == "where" {
+			return pseudo, Specificity{}, nil
+		}
+		var max Specificity
+		for _, sel := range list {
+			if max.Less(sel.specificity) {
+				max = sel.specificity
+			}
+		}
+		return pseudo, max, nil
+	case "nth-child", "nth-last-child", "nth-of-type", "nth-last-of-type":
+		a, b, err := parseNth(args)
+		if err != nil {
+			return pseudo, Specificity{}, err
+		}
+		pseudo.a, pseudo.b = a, b
+	}
+	return pseudo, Specificity{0, 1, 0}, nil
+}
+
+// parseNth parses an+b, odd and even
+func parseNth(expr string) (int, int, error) {
+	expr = strings.ToLower(strings.ReplaceAll(expr, " ", ""))
+	if i := strings.Index(expr, "of"); i >= 0 {
+		// "An+B of S" is not supported; match on An+B alone
+		expr = expr[:i]
+	}
+	switch expr {
+	case "odd":
+		return 2, 1, nil
+	case "even":
+		return 2, 0, nil
+	}
+	n := strings.Index(expr, "n")
+	if n < 0 {
+		b, err := strconv.Atoi(expr)
+		return 0, b, err
+	}
+	var a int
+	switch coef := expr[:n]; coef {
+	case "", "+":
+		a = 1
+	case "-":
+		a = -1
+	default:
+		var err error
+		if a, err = strconv.Atoi(coef); err != nil {
+			return 0, 0, err
+		}
+	}
+	b := 0
+	if rest := expr[n+1:]; rest != "" {
+		var err error
+		if b, err = strconv.Atoi(rest); err != nil {
+			return 0, 0, err
+		}
+	}
+	return a, b, nil
+}
+
+// Matches reports whether the selector matches the element
+func (s *Selector) Matches(n *html.Node) bool {
+	if s.pseudoElement || n == nil || n.Type != html.ElementNode {
+		return false
+	}
+	return s.matchFrom(len(s.compounds)-1, n)
+}
+
+func (s *Selector) matchFrom(i int, n *html.Node) bool {
+	if !s.compounds[i].matches(n) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+
+	switch s.combinators[i-1] {
+	case '>':
+		parent := parentElement(n)
+		return parent != nil && s.matchFrom(i-1, parent)
+	case '+':
+		prev := previousElement(n)
+		return prev != nil && s.matchFrom(i-1, prev)
+	case '~':
+		for prev := previousElement(n); prev != nil; prev = previousElement(prev) {
+			if s.matchFrom(i-1, prev) {
+				return true
+			}
+		}
+		return false
+	default:
+		for parent := parentElement(n); parent != nil; parent = parentElement(parent) {
+			if s.matchFrom(i-1, parent) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (c *compound) matches(n *html.Node) bool {
+	if c.tag != "" && !strings.EqualFold(c.tag, n.Data) {
+		return false
+	}
+	for _, id := range c.ids {
+		if dom.Attr(n, "id") != id {
+			return false
+		}
+	}
+	if len(c.classes) > 0 {
+		classes := strings.Fields(dom.Attr(n, "class"))
+		for _, class := range c.classes {
+			if !containsString(classes, class) {
+				return false
+			}
+		}
+	}
+	for _, attr := range c.attrs {
+		if !attr.matches(n) {
+			return false
+		}
+	}
+	for _, pseudo := range c.pseudos {
+		if !pseudo.matches(n) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *attrSelector) matches(n *html.Node) bool {
+	val, ok := dom.LookupAttr(n, a.name)
+	if !ok {
+		return false
+	}
+	want := a.value
+	if a.insensitive {
+		val = strings.ToLower(val)
+		want = strings.ToLower(want)
+	}
+	switch a.op {
+	case "":
+		return true
+	case "=":
+		return val == want
+	case "~=":
+		return containsString(strings.Fields(val), want)
+	case "|=":
+		return val == want || strings.HasPrefix(val, want+"-")
+	case "^=":
+		return want != "" && strings.HasPrefix(val, want)
+	case "$=":
+		return want != "" && strings.HasSuffix(val, want)
+	case "*=":
+		return want != "" && strings.Contains(val, want)
+	}
+	return false
+}
+
+func (p *pseudoClass) matches(n *html.Node) bool {
+	switch p.name {
+	case "not":
+		for _, sel := range p.args {
+			if sel.Matches(n) {
+				return false
+			}
+		}
+		return true
+	case "is", "matches", "where", "any", "-webkit-any":
+		for _, sel := range p.args {
+			if sel.Matches(n) {
+				return true
+			}
+		}
+		return false
+	case "root":
+		return parentElement(n) == nil
+	case "empty":
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode || (c.Type == html.TextNode && c.Data != "") {
+				return false
+			}
+		}
+		return true
+	case "first-child":
+		return previousElement(n) == nil
+	case "last-child":
+		return nextElement(n) == nil
+	case "only-child":
+		return previousElement(n) == nil && nextElement(n) == nil
+	case "first-of-type":
+		return siblingPosition(n, true, false) == 1
+	case "last-of-type":
+		return siblingPosition(n, true, true) == 1
+	case "only-of-type":
+		return siblingPosition(n, true, false) == 1 && siblingPosition(n, true, true) == 1
+	case "nth-child":
+		return nthMatches(p.a, p.b, siblingPosition(n, false, false))
+	case "nth-last-child":
+		return nthMatches(p.a, p.b, siblingPosition(n, false, true))
+	case "nth-of-type":
+		return nthMatches(p.a, p.b, siblingPosition(n, true, false))
+	case "nth-last-of-type":
+		return nthMatches(p.a, p.b, siblingPosition(n, true, true))
+	case "link", "any-link":
+		return (n.Data == "a" || n.Data == "area") && dom.HasAttr(n, "href")
+	case "checked":
+		return dom.HasAttr(n, "checked") || dom.HasAttr(n, "selected")
+	case "disabled":
+		return dom.HasAttr(n, "disabled")
+	case "enabled":
+		return isFormControl(n) && !dom.HasAttr(n, "disabled")
+	case "required":
+		return dom.HasAttr(n, "required")
+	case "optional":
+		return isFormControl(n) && !dom.HasAttr(n, "required")
+	}
+	// Dynamic states (:hover, :focus, :visited, ...) are evaluated as inactive
+	return false
+}
+
+func nthMatches(a, b, position int) bool {
+	if a == 0 {
+		return position == b
+	}
+	diff := position - b
+	return diff%a == 0 && diff/a >= 0
+}
+
+// siblingPosition returns the 1-based position of n among its element
+// siblings, optionally only those of the same type and counting from the end
+func siblingPosition(n *html.Node, sameType, fromEnd bool) int {
+	position := 1
+	for s := nextOrPrevious(n, fromEnd); s != nil; s = nextOrPrevious(s, fromEnd) {
+		if !sameType || s.Data == n.Data {
+			position++
+		}
+	}
+	return position
+}
+
+func nextOrPrevious(n *html.Node, next bool) *html.Node {
+	if next {
+		return nextElement(n)
+	}
+	return previousElement(n)
+}
+
+func parentElement(n *html.Node) *html.Node {
+	if n.Parent != nil && n.Parent.Type == html.ElementNode {
+		return n.Parent
+	}
+	return nil
+}
+
+func previousElement(n *html.Node) *html.Node {
+	for s := n.PrevSibling; s != nil; s = s.PrevSibling {
+		if s.Type == html.ElementNode {
+			return s
+		}
+	}
+	return nil
+}
+
+func nextElement(n *html.Node) *html.Node {
+	for s := n.NextSibling; s != nil; s = s.NextSibling {
+		if s.Type == html.ElementNode {
+			return s
+		}
+	}
+	return nil
+}
+
+func isFormControl(n *html.Node) bool {
+	switch n.Data {
+	case "input", "select", "textarea", "button", "fieldset", "optgroup", "option":
+		return true
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
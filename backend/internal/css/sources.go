@@ -0,0 +1,57 @@
+package css
+
+import (
+	"strings"
+
+	"tokubetsu/internal/dom"
+
+	"golang.org/x/net/html"
+)
+
+// Source is a stylesheet referenced by a document: either the text of a
+// <style> element or the href of a <link rel="stylesheet">
+type Source struct {
+	Text string
+	Href string
+}
+
+// Sources lists the screen stylesheets of a document in document order
+func Sources(doc *html.Node) []Source {
+	var sources []Source
+	dom.Walk(doc, func(n *html.Node) bool {
+		if n.Type != html.ElementNode {
+			return true
+		}
+		switch n.Data {
+		case "style":
+			if mediaApplies(dom.Attr(n, "media")) {
+				var text strings.Builder
+				for c := n.FirstChild; c != nil; c = c.NextSibling {
+					if c.Type == html.TextNode {
+						text.WriteString(c.Data)
+					}
+				}
+				sources = append(sources, Source{Text: text.String()})
+			}
+			return false
+		case "link":
+			rel := strings.Fields(strings.ToLower(dom.Attr(n, "rel")))
+			if containsString(rel, "stylesheet") && !containsString(rel, "alternate") &&
+				mediaApplies(dom.Attr(n, "media")) && dom.Attr(n, "href") != "" {
+				sources = append(sources, Source{Href: strings.TrimSpace(dom.Attr(n, "href"))})
+			}
+		case "template", "svg":
+			return false
+		}
+		return true
+	})
+	return sources
+}
+
+// BaseURL returns the href of the document's first <base> element, if any
+func BaseURL(doc *html.Node) string {
+	if base := dom.Find(doc, "base"); base != nil {
+		return strings.TrimSpace(dom.Attr(base, "href"))
+	}
+	return ""
+}
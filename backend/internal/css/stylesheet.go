@@ -0,0 +1,234 @@
+// Package css parses stylesheets and computes the styles the cascade assigns
+// to the elements of an html.Node tree.
+package css
+
+import (
+	"strings"
+)
+
+// Declaration is a single property: value pair
+type Declaration struct {
+	Property  string
+	Value     string
+	Important bool
+}
+
+// Rule is a style rule with its selectors and declarations
+type Rule struct {
+	Selectors    []*Selector
+	Declarations []Declaration
+}
+
+// Stylesheet is a parsed stylesheet
+type Stylesheet struct {
+	Rules   []Rule
+	Imports []string // URLs referenced by @import, in order
+}
+
+// ParseStylesheet parses CSS text. Invalid rules and selectors are skipped,
+// as a browser would. Rules in print-only media blocks are dropped.
+func ParseStylesheet(text string) *Stylesheet {
+	sheet := &Stylesheet{}
+	parseRules(stripComments(text), sheet)
+	return sheet
+}
+
+// ParseDeclarations parses a declaration block such as a style attribute
+func ParseDeclarations(text string) []Declaration {
+	var decls []Declaration
+	for _, part := range splitTopLevel(stripComments(text), ';') {
+		prop, value, found := strings.Cut(part, ":")
+		if !found {
+			continue
+		}
+		prop = strings.ToLower(strings.TrimSpace(prop))
+		value = strings.TrimSpace(value)
+		if prop == "" || value == "" {
+			continue
+		}
+
+		important := false
+		if i := strings.LastIndex(value, "!"); i >= 0 && strings.EqualFold(strings.TrimSpace(value[i+1:]), "important") {
+			important = true
+			value = strings.TrimSpace(value[:i])
+		}
+		decls = append(decls, Declaration{Property: prop, Value: value, Important: important})
+	}
+	return decls
+}
+
+func parseRules(text string, sheet *Stylesheet) {
+	for i := 0; i < len(text); {
+		// Skip whitespace and stray separators between rules
+		for i < len(text) && (isSpace(text[i]) || text[i] == ';' || text[i] == '}') {
+			i++
+		}
+		if i >= len(text) {
+			return
+		}
+
+		end := indexTopLevel(text[i:], "{;")
+		if end < 0 {
+			return
+		}
+		prelude := strings.TrimSpace(text[i : i+end])
+		i += end
+
+		if text[i] == ';' {
+			// Statement at-rule such as @import or @charset
+			if strings.HasPrefix(strings.ToLower(prelude), "@import") {
+				if u := importURL(prelude); u != "" {
+					sheet.Imports = append(sheet.Imports, u)
+				}
+			}
+			i++
+			continue
+		}
+
+		blockEnd := matchingBrace(text, i)
+		block := text[i+1 : blockEnd]
+		i = blockEnd + 1
+
+		if strings.HasPrefix(prelude, "@") {
+			name := strings.ToLower(prelude)
+			switch {
+			case strings.HasPrefix(name, "@media"):
+				if mediaApplies(strings.TrimSpace(prelude[len("@media"):])) {
+					parseRules(block, sheet)
+				}
+			case strings.HasPrefix(name, "@supports"), strings.HasPrefix(name, "@layer"), strings.HasPrefix(name, "@container"):
+				parseRules(block, sheet)
+			}
+			// Other block at-rules (@font-face, @keyframes, @page, ...) do not style elements
+			continue
+		}
+
+		selectors, err := ParseSelectorList(prelude)
+		if err != nil {
+			continue
+		}
+		sheet.Rules = append(sheet.Rules, Rule{Selectors: selectors, Declarations: ParseDeclarations(block)})
+	}
+}
+
+// mediaApplies reports whether a media query list can apply to a screen
+func mediaApplies(query string) bool {
+	query = strings.ToLower(query)
+	if query == "" {
+		return true
+	}
+	for _, q := range strings.Split(query, ",") {
+		q = strings.TrimSpace(q)
+		if strings.HasPrefix(q, "not ") || strings.HasPrefix(q, "print") || strings.HasPrefix(q, "only print") || q == "speech" {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func importURL(prelude string) string {
+	rest := strings.TrimSpace(prelude[len("@import"):])
+	if strings.HasPrefix(strings.ToLower(rest), "url(") {
+		end := strings.Index(rest, ")")
+		if end < 0 {
+			return ""
+		}
+		rest = rest[4:end]
+	} else if fields := strings.Fields(rest); len(fields) > 0 {
+		rest = fields[0]
+	}
+	return strings.Trim(strings.TrimSpace(rest), `"'`)
+}
+
+func stripComments(text string) string {
+	var sb strings.Builder
+	for {
+		start := strings.Index(text, "/*")
+		if start < 0 {
+			sb.WriteString(text)
+			return sb.String()
+		}
+		sb.WriteString(text[:start])
+		end := strings.Index(text[start+2:], "*/")
+		if end < 0 {
+			return sb.String()
+		}
+		text = text[start+2+end+2:]
+	}
+}
+
+// indexTopLevel returns the index of the first of chars outside strings and
+// parentheses, or -1
+func indexTopLevel(text, chars string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case depth == 0 && strings.IndexByte(chars, c) >= 0:
+			return i
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			if depth > 0 {
+				depth--
+			}
+		}
+	}
+	return -1
+}
+
+// matchingBrace returns the index of the brace closing the one at open, or
+// the end of text when it is unterminated
+func matchingBrace(text string, open int) int {
+	depth := 0
+	var quote byte
+	for i := open; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(text)
+}
+
+// splitTopLevel splits text on sep outside strings and parentheses
+func splitTopLevel(text string, sep byte) []string {
+	var parts []string
+	for {
+		i := indexTopLevel(text, string(sep))
+		if i < 0 {
+			parts = append(parts, text)
+			return parts
+		}
+		parts = append(parts, text[:i])
+		text = text[i+1:]
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
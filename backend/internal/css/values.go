@@ -0,0 +1,133 @@
+package css
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DefaultFontSize is the initial font size in CSS pixels
+const DefaultFontSize = 16.0
+
+// fontSizeKeywords maps absolute font-size keywords to pixels
+var fontSizeKeywords = map[string]float64{
+	"xx-small":  9,
+	"x-small":   10,
+	"small":     13,
+	"medium":    16,
+	"large":     18,
+	"x-large":   24,
+	"xx-large":  32,
+	"xxx-large": 48,
+}
+
+// ParseLength converts a CSS length to pixels. fontSize and rootFontSize
+// resolve em and rem units. Percentages and viewport units cannot be
+// resolved without layout and report false.
+func ParseLength(value string, fontSize, rootFontSize float64) (float64, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "0" {
+		return 0, true
+	}
+
+	units := []struct {
+		suffix string
+		factor float64
+	}{
+		{"px", 1},
+		{"rem", rootFontSize},
+		{"em", fontSize},
+		{"ex", fontSize / 2},
+		{"ch", fontSize / 2},
+		{"pt", 96.0 / 72.0},
+		{"pc", 16},
+		{"in", 96},
+		{"cm", 96 / 2.54},
+		{"mm", 96 / 25.4},
+		{"q", 96 / 101.6},
+	}
+	for _, unit := range units {
+		if !strings.HasSuffix(value, unit.suffix) {
+			continue
+		}
+		number, err := strconv.ParseFloat(strings.TrimSuffix(value, unit.suffix), 64)
+		if err != nil {
+			return 0, false
+		}
+		return number * unit.factor, true
+	}
+	return 0, false
+}
+
+// parseFontSize resolves a font-size value against the parent's font size
+func parseFontSize(value string, parentSize, rootSize float64) (float64, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if size, ok := fontSizeKeywords[value]; ok {
+		return size, true
+	}
+	switch value {
+	case "smaller":
+		return parentSize / 1.2, true
+	case "larger":
+		return parentSize * 1.2, true
+	}
+	if strings.HasSuffix(value, "%") {
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+		if err != nil {
+			return 0, false
+		}
+		return parentSize * percent / 100, true
+	}
+	// em and ex in font-size are relative to the parent's font size
+	return ParseLength(value, parentSize, rootSize)
+}
+
+// parseFontWeight resolves a font-weight value against the parent's weight
+func parseFontWeight(value string, parentWeight int) (int, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "normal":
+		return 400, true
+	case "bold":
+		return 700, true
+	case "bolder":
+		switch {
+		case parentWeight < 350:
+			return 400, true
+		case parentWeight < 550:
+			return 700, true
+		default:
+			return 900, true
+		}
+	case "lighter":
+		switch {
+		case parentWeight < 550:
+			return 100, true
+		case parentWeight < 750:
+			return 400, true
+		default:
+			return 700, true
+		}
+	}
+	weight, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || weight < 1 || weight > 1000 {
+		return 0, false
+	}
+	return weight, true
+}
+
+// splitValue splits a property value into space-separated components,
+// keeping function arguments together
+func splitValue(value string) []string {
+	var parts []string
+	for _, part := range splitTopLevel(value, ' ') {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// isImageValue reports whether a background component paints an image
+func isImageValue(component string) bool {
+	lower := strings.ToLower(component)
+	return strings.HasPrefix(lower, "url(") || strings.Contains(lower, "gradient(") || strings.HasPrefix(lower, "image-set(")
+}
@@ -0,0 +1,98 @@
+// Package cvd simulates color vision deficiencies. It applies the same color
+// matrices as the frontend's simulation filters, so a color pair reported
+// here looks the same as in the simulated page.
+package cvd
+
+import (
+	"math"
+
+	"tokubetsu/internal/color"
+)
+
+// Color vision deficiencies
+const (
+	Protanopia    = "protanopia"    // Red-blind
+	Deuteranopia  = "deuteranopia"  // Green-blind
+	Tritanopia    = "tritanopia"    // Blue-blind
+	Achromatopsia = "achromatopsia" // No color
+)
+
+// simulation is a 3x3 color matrix. SVG feColorMatrix filters apply their
+// matrix to linear RGB by default, CSS filter functions to sRGB.
+type simulation struct {
+	matrix [3][3]float64
+	linear bool
+}
+
+var simulations = map[string]simulation{
+	Protanopia: {matrix: [3][3]float64{
+		{0.567, 0.433, 0},
+		{0.558, 0.442, 0},
+		{0, 0.242, 0.758},
+	}, linear: true},
+	Deuteranopia: {matrix: [3][3]float64{
+		{0.625, 0.375, 0},
+		{0.7, 0.3, 0},
+		{0, 0.3, 0.7},
+	}, linear: true},
+	Tritanopia: {matrix: [3][3]float64{
+		{0.95, 0.05, 0},
+		{0, 0.433, 0.567},
+		{0, 0.475, 0.525},
+	}, linear: true},
+	// grayscale(100%)
+	Achromatopsia: {matrix: [3][3]float64{
+		{0.2126, 0.7152, 0.0722},
+		{0.2126, 0.7152, 0.0722},
+		{0.2126, 0.7152, 0.0722},
+	}},
+}
+
+// All returns the simulated deficiencies
+func All() []string {
+	return []string{Protanopia, Deuteranopia, Tritanopia, Achromatopsia}
+}
+
+// Simulate returns a color as seen with a deficiency. Alpha is kept, and
+// unknown deficiencies return the color unchanged.
+func Simulate(c color.RGBA, deficiency string) color.RGBA {
+	sim, ok := simulations[deficiency]
+	if !ok {
+		return c
+	}
+
+	in := [3]float64{c.R / 255, c.G / 255, c.B / 255}
+	if sim.linear {
+		for i := range in {
+			in[i] = toLinear(in[i])
+		}
+	}
+	var out [3]float64
+	for i, row := range sim.matrix {
+		out[i] = clamp(row[0]*in[0] + row[1]*in[1] + row[2]*in[2])
+		if sim.linear {
+			out[i] = fromLinear(out[i])
+		}
+	}
+	return color.RGBA{R: out[0] * 255, G: out[1] * 255, B: out[2] * 255, A: c.A}
+}
+
+// toLinear converts a 0-1 sRGB channel to linear light
+func toLinear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// fromLinear converts a 0-1 linear light channel to sRGB
+func fromLinear(v float64) float64 {
+	if v <= 0.0031308 {
+		return v * 12.92
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
+
+func clamp(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
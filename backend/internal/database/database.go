@@ -40,6 +40,11 @@ func Connect() {
 		&models.TeamInvite{},
 		&models.ComplianceReport{},
 		&models.ComplianceViolation{},
+		&models.CriterionResult{},
+		&models.ACRRemark{},
+		&models.ManualCheck{},
+		&models.ManualEvidence{},
+		&models.ManualCheckChange{},
 		&models.ActivityLog{},
 	)
 	if err != nil {
@@ -0,0 +1,108 @@
+// Package dom provides helpers for working with golang.org/x/net/html trees.
+package dom
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// LookupAttr returns the value of an attribute and whether it is present
+func LookupAttr(n *html.Node, key string) (string, bool) {
+	for _, attr := range n.Attr {
+		if attr.Key == key && attr.Namespace == "" {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
+
+// Attr returns the value of an attribute, or "" when it is missing
+func Attr(n *html.Node, key string) string {
+	val, _ := LookupAttr(n, key)
+	return val
+}
+
+// HasAttr reports whether an attribute is present
+func HasAttr(n *html.Node, key string) bool {
+	_, ok := LookupAttr(n, key)
+	return ok
+}
+
+// IsElement reports whether n is an element with one of the given tag names
+func IsElement(n *html.Node, tags ...string) bool {
+	if n == nil || n.Type != html.ElementNode {
+		return false
+	}
+	if len(tags) == 0 {
+		return true
+	}
+	for _, tag := range tags {
+		if n.Data == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Walk calls f for n and every descendant in document order. Returning false
+// from f skips the descendants of that node.
+func Walk(n *html.Node, f func(*html.Node) bool) {
+	if !f(n) {
+		return
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		Walk(c, f)
+	}
+}
+
+// Elements returns every element below n (including n) in document order
+func Elements(n *html.Node) []*html.Node {
+	var elements []*html.Node
+	Walk(n, func(node *html.Node) bool {
+		if node.Type == html.ElementNode {
+			elements = append(elements, node)
+		}
+		return true
+	})
+	return elements
+}
+
+// Find returns the first element below n (including n) with one of the given tag names
+func Find(n *html.Node, tags ...string) *html.Node {
+	var found *html.Node
+	Walk(n, func(node *html.Node) bool {
+		if found != nil {
+			return false
+		}
+		if IsElement(node, tags...) {
+			found = node
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// Closest returns the nearest ancestor of n (excluding n) with one of the given tag names
+func Closest(n *html.Node, tags ...string) *html.Node {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if IsElement(p, tags...) {
+			return p
+		}
+	}
+	return nil
+}
+
+// Text returns the concatenated text content of n with collapsed whitespace
+func Text(n *html.Node) string {
+	var sb strings.Builder
+	Walk(n, func(node *html.Node) bool {
+		if node.Type == html.TextNode {
+			sb.WriteString(node.Data)
+			sb.WriteString(" ")
+		}
+		return !IsElement(node, "script", "style", "template")
+	})
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
@@ -0,0 +1,262 @@
+package dom
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Location identifies an element in a document
+type Location struct {
+	Selector string `json:"selector"`
+	XPath    string `json:"xpath"`
+	Line     int    `json:"line,omitempty"`   // 1-based line of the start tag in the source, 0 if unknown
+	Column   int    `json:"column,omitempty"` // 1-based column of the start tag in the source, 0 if unknown
+}
+
+type sourcePosition struct {
+	line, column int
+}
+
+// Locator computes locations for the elements of one parsed document
+type Locator struct {
+	ids       map[string]int
+	positions map[*html.Node]sourcePosition
+}
+
+// NewLocator indexes a parsed document. source is the markup the document was
+// parsed from and is used to find source positions; it may be nil.
+func NewLocator(doc *html.Node, source []byte) *Locator {
+	l := &Locator{
+		ids:       make(map[string]int),
+		positions: make(map[*html.Node]sourcePosition),
+	}
+	Walk(doc, func(n *html.Node) bool {
+		if n.Type == html.ElementNode {
+			if id := Attr(n, "id"); id != "" {
+				l.ids[id]++
+			}
+		}
+		return true
+	})
+	if source != nil {
+		l.mapPositions(doc, source)
+	}
+	return l
+}
+
+// Locate returns the location of an element
+func (l *Locator) Locate(n *html.Node) Location {
+	loc := Location{
+		Selector: l.Selector(n),
+		XPath:    XPath(n),
+	}
+	if pos, ok := l.positions[n]; ok {
+		loc.Line = pos.line
+		loc.Column = pos.column
+	}
+	return loc
+}
+
+// Selector returns a CSS selector that matches only n. It is anchored at the
+// nearest element with a document-unique id, or at the root element.
+func (l *Locator) Selector(n *html.Node) string {
+	if n == nil || n.Type != html.ElementNode {
+		return ""
+	}
+
+	var parts []string
+	for e := n; e != nil && e.Type == html.ElementNode; e = e.Parent {
+		if id := Attr(e, "id"); id != "" && l.ids[id] == 1 {
+			parts = append(parts, "#"+cssEscape(id))
+			break
+		}
+		part := e.Data
+		if index, count := typeIndex(e); count > 1 {
+			part += fmt.Sprintf(":nth-of-type(%d)", index)
+		}
+		parts = append(parts, part)
+	}
+
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return strings.Join(parts, " > ")
+}
+
+// XPath returns the absolute XPath of an element
+func XPath(n *html.Node) string {
+	if n == nil || n.Type != html.ElementNode {
+		return ""
+	}
+
+	var parts []string
+	for e := n; e != nil && e.Type == html.ElementNode; e = e.Parent {
+		part := e.Data
+		if index, count := typeIndex(e); count > 1 {
+			part += fmt.Sprintf("[%d]", index)
+		}
+		parts = append(parts, part)
+	}
+
+	var sb strings.Builder
+	for i := len(parts) - 1; i >= 0; i-- {
+		sb.WriteString("/")
+		sb.WriteString(parts[i])
+	}
+	return sb.String()
+}
+
+// typeIndex returns the 1-based position of n among its siblings with the same
+// tag name, and how many such siblings there are
+func typeIndex(n *html.Node) (index, count int) {
+	if n.Parent == nil {
+		return 1, 1
+	}
+	for c := n.Parent.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == n.Data {
+			count++
+			if c == n {
+				index = count
+			}
+		}
+	}
+	return index, count
+}
+
+// cssEscape escapes an identifier for use in a CSS selector
+func cssEscape(ident string) string {
+	var sb strings.Builder
+	for i, r := range ident {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_', r == '-', r > 0x7f:
+			sb.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				fmt.Fprintf(&sb, "\\%x ", r)
+			} else {
+				sb.WriteRune(r)
+			}
+		default:
+			sb.WriteRune('\\')
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+type sourceTag struct {
+	name  string
+	attrs string // sorted attribute names, see attrNames
+	pos   sourcePosition
+}
+
+// attrNames returns the distinct attribute names of a tag, lowercased, sorted
+// and joined so a parsed element can be compared with its source tag
+func attrNames(names []string) string {
+	for i := range names {
+		names[i] = strings.ToLower(names[i])
+	}
+	slices.Sort(names)
+	return strings.Join(slices.Compact(names), " ")
+}
+
+// elementAttrNames is attrNames of a parsed element
+func elementAttrNames(n *html.Node) string {
+	names := make([]string, 0, len(n.Attr))
+	for _, a := range n.Attr {
+		if a.Namespace != "" {
+			names = append(names, a.Namespace+":"+a.Key)
+		} else {
+			names = append(names, a.Key)
+		}
+	}
+	return attrNames(names)
+}
+
+// positionLookahead bounds how far ahead in the source a parsed element may
+// be matched, to step over tags the parser dropped or moved
+const positionLookahead = 8
+
+// mapPositions aligns the elements of the parsed tree with the start tags of
+// the source. Elements the parser created implicitly get no position.
+func (l *Locator) mapPositions(doc *html.Node, source []byte) {
+	tags := tokenizeStartTags(source)
+
+	var elements []*html.Node
+	Walk(doc, func(n *html.Node) bool {
+		if n.Type == html.ElementNode {
+			elements = append(elements, n)
+		}
+		return true
+	})
+
+	i := 0
+	for e, n := range elements {
+		if i >= len(tags) {
+			break
+		}
+		attrs := elementAttrNames(n)
+		for j := i; j < len(tags) && j < i+positionLookahead; j++ {
+			if !strings.EqualFold(tags[j].name, n.Data) || tags[j].attrs != attrs {
+				continue
+			}
+			// An implied element such as tbody would claim a later tag of
+			// the same name, while the element after it in the tree starts
+			// before that tag. Implied elements have no attributes either.
+			if e+1 < len(elements) && hasTag(tags[i:j], elements[e+1].Data) {
+				break
+			}
+			l.positions[n] = tags[j].pos
+			i = j + 1
+			break
+		}
+	}
+}
+
+// hasTag reports whether one of the tags has the given name
+func hasTag(tags []sourceTag, name string) bool {
+	for _, tag := range tags {
+		if strings.EqualFold(tag.name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenizeStartTags lists the start tags of the source with their positions
+func tokenizeStartTags(source []byte) []sourceTag {
+	var tags []sourceTag
+	z := html.NewTokenizer(bytes.NewReader(source))
+	line, column := 1, 1
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			// io.EOF or a read error; either way the source is exhausted
+			return tags
+		}
+		raw := z.Raw()
+		if tt == html.StartTagToken || tt == html.SelfClosingTagToken {
+			name, hasAttr := z.TagName()
+			var names []string
+			for hasAttr {
+				var key []byte
+				key, _, hasAttr = z.TagAttr()
+				names = append(names, string(key))
+			}
+			tags = append(tags, sourceTag{name: string(name), attrs: attrNames(names), pos: sourcePosition{line: line, column: column}})
+		}
+		for _, b := range raw {
+			if b == '\n' {
+				line++
+				column = 1
+			} else if b&0xc0 != 0x80 {
+				// Count runes rather than bytes
+				column++
+			}
+		}
+	}
+}
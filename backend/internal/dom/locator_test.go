@@ -0,0 +1,49 @@
+package dom
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestLocatePositions(t *testing.T) {
+	source := "<table><tr><td id=\"a\">A</td></tr></table>\n" +
+		"<table><tbody><tr><td id=\"b\">B</td></tr></tbody></table>\n" +
+		"<div></p><p id=\"c\">C</p></div>"
+	doc, err := html.Parse(strings.NewReader(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := NewLocator(doc, []byte(source))
+
+	find := func(id string) *html.Node {
+		var found *html.Node
+		Walk(doc, func(n *html.Node) bool {
+			if n.Type == html.ElementNode && Attr(n, "id") == id {
+				found = n
+			}
+			return found == nil
+		})
+		return found
+	}
+	for _, tt := range []struct {
+		id           string
+		line, column int
+	}{
+		{"a", 1, 12},
+		{"b", 2, 19},
+		{"c", 3, 10},
+	} {
+		loc := l.Locate(find(tt.id))
+		if loc.Line != tt.line || loc.Column != tt.column {
+			t.Errorf("#%s at %d:%d, want %d:%d", tt.id, loc.Line, loc.Column, tt.line, tt.column)
+		}
+	}
+
+	// The implied tbody of the first table has no position
+	tbody := find("a").Parent.Parent
+	if loc := l.Locate(tbody); loc.Line != 0 {
+		t.Errorf("implied tbody at %d:%d, want no position", loc.Line, loc.Column)
+	}
+}
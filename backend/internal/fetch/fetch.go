@@ -0,0 +1,281 @@
+// Package fetch downloads pages and their resources for scanning. It bounds
+// every request by a timeout, a redirect limit and a body size, decodes HTML
+// and CSS to UTF-8, and sends a site's credentials only to that site.
+package fetch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding"
+)
+
+// Defaults for a zero Config
+const (
+	DefaultTimeout      = 30 * time.Second
+	DefaultMaxRedirects = 10
+	DefaultMaxBodySize  = 10 << 20
+	DefaultUserAgent    = "TokubetsuScanner/1.0 (+accessibility scan)"
+)
+
+// Auth types
+const (
+	AuthBasic  = "basic"
+	AuthBearer = "bearer"
+)
+
+var (
+	// ErrTooManyRedirects is returned when a request exceeds the redirect limit
+	ErrTooManyRedirects = errors.New("too many redirects")
+	// ErrBodyTooLarge is returned when a response exceeds the maximum body size
+	ErrBodyTooLarge = errors.New("response body too large")
+)
+
+// StatusError is returned by Response.Check for a response outside 2xx
+type StatusError struct {
+	URL        string
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned %s", e.URL, e.Status)
+}
+
+// Auth is the HTTP authentication of a site
+type Auth struct {
+	Type     string // AuthBasic or AuthBearer
+	Username string
+	Password string
+	Token    string
+}
+
+// Credentials are sent with requests to Host only, never to third-party
+// resources such as stylesheets on a CDN
+type Credentials struct {
+	Host    string // host[:port] of the site
+	Headers map[string]string
+	Cookies map[string]string
+	Auth    *Auth
+}
+
+// Config configures a Client. Zero values use the defaults.
+type Config struct {
+	Timeout      time.Duration
+	MaxRedirects int
+	MaxBodySize  int64
+	UserAgent    string
+	Credentials  *Credentials
+}
+
+// Client fetches pages. It is safe for concurrent use.
+type Client struct {
+	config Config
+	http   *http.Client
+}
+
+// Response is a fetched resource. The body of HTML and CSS is decoded to
+// UTF-8; Charset names the encoding it was decoded from.
+type Response struct {
+	URL         string // Final URL after redirects
+	StatusCode  int
+	Status      string
+	ContentType string
+	Charset     string
+	Body        []byte
+}
+
+// Check returns a *StatusError when the response status is not 2xx
+func (r *Response) Check() error {
+	if r.StatusCode < 200 || r.StatusCode > 299 {
+		return &StatusError{URL: r.URL, StatusCode: r.StatusCode, Status: r.Status}
+	}
+	return nil
+}
+
+// New creates a client
+func New(config Config) *Client {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.MaxRedirects <= 0 {
+		config.MaxRedirects = DefaultMaxRedirects
+	}
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = DefaultMaxBodySize
+	}
+	if config.UserAgent == "" {
+		config.UserAgent = DefaultUserAgent
+	}
+
+	c := &Client{config: config}
+	c.http = &http.Client{
+		Timeout: config.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > config.MaxRedirects {
+				return ErrTooManyRedirects
+			}
+			c.authorize(req)
+			return nil
+		},
+	}
+	return c
+}
+
+// UserAgent returns the User-Agent header the client sends
+func (c *Client) UserAgent() string {
+	return c.config.UserAgent
+}
+
+// authorize adds the credentials to a request for the site's host and
+// removes them from requests to any other host, including redirects, which
+// carry over the headers of the original request
+func (c *Client) authorize(req *http.Request) {
+	creds := c.config.Credentials
+	if creds == nil {
+		return
+	}
+	if !strings.EqualFold(req.URL.Host, creds.Host) {
+		for name := range creds.Headers {
+			req.Header.Del(name)
+		}
+		req.Header.Del("Authorization")
+		req.Header.Del("Cookie")
+		return
+	}
+	for name, value := range creds.Headers {
+		req.Header.Set(name, value)
+	}
+	for name, value := range creds.Cookies {
+		if _, err := req.Cookie(name); err != nil {
+			req.AddCookie(&http.Cookie{Name: name, Value: value})
+		}
+	}
+	if auth := creds.Auth; auth != nil {
+		switch auth.Type {
+		case AuthBasic:
+			req.SetBasicAuth(auth.Username, auth.Password)
+		case AuthBearer:
+			req.Header.Set("Authorization", "Bearer "+auth.Token)
+		}
+	}
+}
+
+// Get fetches a URL. Responses of any status are returned; use Check to
+// treat statuses outside 2xx as errors.
+func (c *Client) Get(rawURL string) (*Response, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %v", err)
+	}
+	req.Header.Set("User-Agent", c.config.UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+	c.authorize(req)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) && urlErr.Timeout() {
+			return nil, fmt.Errorf("timed out after %s fetching %s", c.config.Timeout, rawURL)
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.config.MaxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+	if int64(len(body)) > c.config.MaxBodySize {
+		return nil, fmt.Errorf("%w: %s exceeds %d bytes", ErrBodyTooLarge, rawURL, c.config.MaxBodySize)
+	}
+
+	r := &Response{
+		URL:         resp.Request.URL.String(),
+		StatusCode:  resp.StatusCode,
+		Status:      resp.Status,
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        body,
+	}
+	if err := r.decode(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// decode converts an HTML or CSS body to UTF-8, determining its encoding as
+// browsers do. Other bodies are left as they are.
+func (r *Response) decode() error {
+	mediaType, params, _ := mime.ParseMediaType(r.ContentType)
+	var (
+		enc  encoding.Encoding
+		name string
+	)
+	switch mediaType {
+	case "", "text/html", "application/xhtml+xml":
+		// A byte order mark, the Content-Type header or a <meta> declaration
+		enc, name, _ = charset.DetermineEncoding(r.Body, r.ContentType)
+	case "text/css":
+		enc, name = cssEncoding(r.Body, params["charset"])
+	default:
+		return nil
+	}
+
+	r.Charset = name
+	// Strip a byte order mark, as the decoder would
+	r.Body = bytes.TrimPrefix(r.Body, []byte(byteOrderMarks[name]))
+	if name == "utf-8" {
+		return nil
+	}
+	decoded, err := enc.NewDecoder().Bytes(r.Body)
+	if err != nil {
+		return fmt.Errorf("failed to decode %s body: %v", name, err)
+	}
+	r.Body = decoded
+	return nil
+}
+
+// byteOrderMarks are the byte order marks of the encodings that have one
+var byteOrderMarks = map[string]string{
+	"utf-8":    "\xef\xbb\xbf",
+	"utf-16be": "\xfe\xff",
+	"utf-16le": "\xff\xfe",
+}
+
+// cssEncoding determines the encoding of a stylesheet as CSS Syntax does:
+// from a byte order mark, the charset of the Content-Type header, a leading
+// @charset rule, or else UTF-8
+func cssEncoding(body []byte, label string) (encoding.Encoding, string) {
+	for name, bom := range byteOrderMarks {
+		if bytes.HasPrefix(body, []byte(bom)) {
+			enc, _ := charset.Lookup(name)
+			return enc, name
+		}
+	}
+	if enc, name := charset.Lookup(label); enc != nil {
+		return enc, name
+	}
+
+	// The rule must be exactly @charset "label"; at the very start
+	const prefix = `@charset "`
+	if rest, ok := bytes.CutPrefix(body, []byte(prefix)); ok {
+		if end := bytes.Index(rest, []byte(`";`)); end >= 0 && end < 1024 {
+			if enc, name := charset.Lookup(string(rest[:end])); enc != nil {
+				// An ASCII-compatible rule cannot be UTF-16
+				if name == "utf-16be" || name == "utf-16le" {
+					return encoding.Nop, "utf-8"
+				}
+				return enc, name
+			}
+		}
+	}
+	return encoding.Nop, "utf-8"
+}
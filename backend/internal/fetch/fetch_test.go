@@ -0,0 +1,41 @@
+package fetch
+
+import "testing"
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		charset     string
+		want        string
+	}{
+		{"html utf-8", "text/html; charset=utf-8", "<p>caf\xc3\xa9</p>", "utf-8", "<p>café</p>"},
+		{"html header", "text/html; charset=iso-8859-1", "<p>caf\xe9</p>", "windows-1252", "<p>café</p>"},
+		{"html meta", "text/html", `<meta charset="iso-8859-1"><p>caf` + "\xe9", "windows-1252", `<meta charset="iso-8859-1"><p>café`},
+		{"html utf-16 bom", "text/html", "\xff\xfe<\x00p\x00>\x00", "utf-16le", "<p>"},
+		{"css default", "text/css", "a::after { content: \"caf\xc3\xa9\" }", "utf-8", "a::after { content: \"café\" }"},
+		{"css utf-8 bom", "text/css", "\xef\xbb\xbfa { color: red }", "utf-8", "a { color: red }"},
+		{"css utf-16 bom", "text/css; charset=iso-8859-1", "\xfe\xff\x00a\x00{\x00}", "utf-16be", "a{}"},
+		{"css header", "text/css; charset=iso-8859-1", `@charset "utf-8"; a::after { content: "caf` + "\xe9\" }", "windows-1252", `@charset "utf-8"; a::after { content: "café" }`},
+		{"css @charset", "text/css", `@charset "iso-8859-1"; a::after { content: "caf` + "\xe9\" }", "windows-1252", `@charset "iso-8859-1"; a::after { content: "café" }`},
+		{"css @charset utf-16", "text/css", `@charset "utf-16"; a { color: red }`, "utf-8", `@charset "utf-16"; a { color: red }`},
+		{"css @charset not at start", "text/css", ` @charset "iso-8859-1"; a::after { content: "caf` + "\xc3\xa9\" }", "utf-8", ` @charset "iso-8859-1"; a::after { content: "café" }`},
+		{"css meta ignored", "text/css", `/* <meta charset="iso-8859-1"> */ a::after { content: "caf` + "\xc3\xa9\" }", "utf-8", `/* <meta charset="iso-8859-1"> */ a::after { content: "café" }`},
+		{"other", "image/png", "\xff\xfe\x00", "", "\xff\xfe\x00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Response{ContentType: tt.contentType, Body: []byte(tt.body)}
+			if err := r.decode(); err != nil {
+				t.Fatal(err)
+			}
+			if r.Charset != tt.charset {
+				t.Errorf("charset = %q, want %q", r.Charset, tt.charset)
+			}
+			if string(r.Body) != tt.want {
+				t.Errorf("body = %q, want %q", r.Body, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"tokubetsu/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetScanA11yTree returns the accessibility tree recorded for a page scan
+func (h *ProjectHandler) GetScanA11yTree(c *gin.Context) {
+	userIDVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
+
+	projectID, err := uuid.Parse(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+	scanID, err := uuid.Parse(c.Param("scanId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid scan ID"})
+		return
+	}
+
+	var project models.Project
+	if err := h.db.Where("id = ? AND user_id = ?", projectID, userID).First(&project).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "project not found"})
+		return
+	}
+
+	var scan models.Scan
+	if err := h.db.Where("id = ? AND project_id = ?", scanID, projectID).First(&scan).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "scan not found"})
+		return
+	}
+
+	// Site scans and scans that have not completed have no tree of their own
+	if scan.A11yTree == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no accessibility tree recorded for this scan"})
+		return
+	}
+
+	if !json.Valid([]byte(*scan.A11yTree)) {
+		log.Printf("Stored accessibility tree of scan %s is not valid JSON", scan.ID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read accessibility tree"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"scan_id":  scan.ID,
+		"page_url": scan.PageURL,
+		"tree":     json.RawMessage(*scan.A11yTree),
+	})
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"tokubetsu/internal/acr"
+	"tokubetsu/internal/models"
+	"tokubetsu/internal/standards"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const docxContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+// buildACR loads a report owned by the user and builds its Accessibility
+// Conformance Report. It writes the error response and returns nil on failure.
+func (h *ComplianceHandler) buildACR(c *gin.Context) *acr.Report {
+	report := h.ownedReport(c, "Results", "Checklist")
+	if report == nil {
+		return nil
+	}
+
+	var stored []models.ACRRemark
+	if err := h.db.Where("report_id = ?", report.ID).Find(&stored).Error; err != nil {
+		log.Printf("Failed to load ACR remarks of report %s: %v", report.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load remarks"})
+		return nil
+	}
+	remarks := make(map[string]string, len(stored))
+	for _, remark := range stored {
+		remarks[remark.Criterion] = remark.Remarks
+	}
+
+	return acr.Build(report.Project, *report, remarks)
+}
+
+// ExportACR renders the Accessibility Conformance Report of a compliance
+// report. ?format= selects html (default), docx or json.
+func (h *ComplianceHandler) ExportACR(c *gin.Context) {
+	format := c.DefaultQuery("format", "html")
+	if format != "html" && format != "docx" && format != "json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be html, docx or json"})
+		return
+	}
+
+	report := h.buildACR(c)
+	if report == nil {
+		return
+	}
+	if format == "json" {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	var buf bytes.Buffer
+	var err error
+	contentType := "text/html; charset=utf-8"
+	if format == "docx" {
+		err = acr.RenderDOCX(&buf, report)
+		contentType = docxContentType
+	} else {
+		err = acr.RenderHTML(&buf, report)
+	}
+	if err != nil {
+		log.Printf("Failed to render ACR: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to render report"})
+		return
+	}
+
+	filename := fmt.Sprintf("%s-acr-%s.%s", acrFilename(report.Product), report.Date.Format("2006-01-02"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// acrFilename turns a product name into a safe file name
+func acrFilename(product string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return '-'
+	}, product)
+	name = strings.Trim(name, "-")
+	if name == "" {
+		return "report"
+	}
+	return name
+}
+
+// UpdateACRRemarks stores reviewer remarks for criteria of a report's ACR.
+// The body maps criteria to remarks; empty remarks restore the generated text.
+func (h *ComplianceHandler) UpdateACRRemarks(c *gin.Context) {
+	var req struct {
+		Remarks map[string]string `json:"remarks" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	owned := h.ownedReport(c)
+	if owned == nil {
+		return
+	}
+	reportID := owned.ID
+
+	// Rows depend on the profile the report was measured against
+	profile := standards.Resolve(owned.Profile)
+	for criterion := range req.Remarks {
+		if !acr.ValidRow(profile, criterion) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown criterion %q for profile %s", criterion, profile.ID)})
+			return
+		}
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		for criterion, text := range req.Remarks {
+			text = strings.TrimSpace(text)
+			if text == "" {
+				if err := tx.Unscoped().Where("report_id = ? AND criterion = ?", reportID, criterion).Delete(&models.ACRRemark{}).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			remark := models.ACRRemark{ReportID: reportID, Criterion: criterion, Remarks: text}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "report_id"}, {Name: "criterion"}},
+				DoUpdates: clause.AssignmentColumns([]string{"remarks", "updated_at"}),
+			}).Create(&remark).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to save ACR remarks of report %s: %v", reportID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save remarks"})
+		return
+	}
+
+	report := h.buildACR(c)
+	if report == nil {
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
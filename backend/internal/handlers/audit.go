@@ -0,0 +1,333 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"tokubetsu/internal/models"
+	"tokubetsu/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxEvidenceSize caps the size of a file attached to a manual check
+const maxEvidenceSize = 10 << 20 // 10 MiB
+
+// omitEvidenceData leaves the file contents out when evidence is listed
+func omitEvidenceData(db *gorm.DB) *gorm.DB {
+	return db.Omit("data")
+}
+
+// ownedCheck loads a report owned by the current user and one item of its
+// checklist. It writes the error response and returns nil on failure.
+func (h *ComplianceHandler) ownedCheck(c *gin.Context) (*models.ComplianceReport, *models.ManualCheck) {
+	report := h.ownedReport(c)
+	if report == nil {
+		return nil, nil
+	}
+
+	var check models.ManualCheck
+	if err := h.db.Where("report_id = ? AND criterion = ?", report.ID, c.Param("criterion")).
+		First(&check).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "criterion is not on the checklist"})
+		return nil, nil
+	}
+	return report, &check
+}
+
+// GetChecklist returns the manual audit checklist of a report
+func (h *ComplianceHandler) GetChecklist(c *gin.Context) {
+	report := h.ownedReport(c)
+	if report == nil {
+		return
+	}
+
+	if err := h.db.Where("report_id = ?", report.ID).
+		Preload("Evidence", omitEvidenceData).
+		Find(&report.Checklist).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch checklist"})
+		return
+	}
+	services.PrepareReport(report)
+
+	audited := 0
+	for _, check := range report.Checklist {
+		if check.Result != models.ManualPending {
+			audited++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"report_id":         report.ID,
+		"conformance_level": report.ConformanceLevel,
+		"overall_score":     report.OverallScore,
+		"total":             len(report.Checklist),
+		"audited":           audited,
+		"checklist":         report.Checklist,
+	})
+}
+
+// UpdateCheck records the result and notes of a manual check and merges the
+// result into the report's conformance level and scores
+func (h *ComplianceHandler) UpdateCheck(c *gin.Context) {
+	var req struct {
+		Result *string `json:"result"`
+		Notes  *string `json:"notes"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Result == nil && req.Notes == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "result or notes is required"})
+		return
+	}
+	if req.Result != nil {
+		switch *req.Result {
+		case models.ManualPending, models.ManualPassed, models.ManualFailed, models.ManualNotApplicable:
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "result must be pending, passed, failed or not_applicable"})
+			return
+		}
+	}
+
+	report := h.ownedReport(c, services.ManualResultAssociations...)
+	if report == nil {
+		return
+	}
+	var check *models.ManualCheck
+	for i := range report.Checklist {
+		if report.Checklist[i].Criterion == c.Param("criterion") {
+			check = &report.Checklist[i]
+		}
+	}
+	if check == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "criterion is not on the checklist"})
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	var changes []models.ManualCheckChange
+	if req.Result != nil && *req.Result != check.Result {
+		changes = append(changes, models.ManualCheckChange{CheckID: check.ID, UserID: userID, Field: "result", OldValue: check.Result, NewValue: *req.Result})
+		check.Result = *req.Result
+		check.AuditorID = &userID
+	}
+	if req.Notes != nil && *req.Notes != check.Notes {
+		changes = append(changes, models.ManualCheckChange{CheckID: check.ID, UserID: userID, Field: "notes", OldValue: check.Notes, NewValue: *req.Notes})
+		check.Notes = *req.Notes
+	}
+	if len(changes) > 0 {
+		services.ApplyManualResults(report)
+
+		err := h.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Save(check).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&changes).Error; err != nil {
+				return err
+			}
+			for i := range report.Results {
+				if report.Results[i].Criterion == check.Criterion {
+					if err := tx.Save(&report.Results[i]).Error; err != nil {
+						return err
+					}
+				}
+			}
+			return tx.Model(&models.ComplianceReport{}).Where("id = ?", report.ID).Updates(map[string]interface{}{
+				"conformance_level":    report.ConformanceLevel,
+				"overall_score":        report.OverallScore,
+				"level_a_score":        report.LevelAScore,
+				"level_aa_score":       report.LevelAAScore,
+				"level_aaa_score":      report.LevelAAAScore,
+				"perceivable_score":    report.PerceivableScore,
+				"operable_score":       report.OperableScore,
+				"understandable_score": report.UnderstandableScore,
+				"robust_score":         report.RobustScore,
+			}).Error
+		})
+		if err != nil {
+			log.Printf("Failed to save manual check %s of report %s: %v", check.Criterion, report.ID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save manual check"})
+			return
+		}
+
+		go func() {
+			details := fmt.Sprintf("Manual check %s of report %s set to %s", check.Criterion, report.ID, check.Result)
+			if err := RecordActivity(userID, "updated_manual_check", "compliance", &report.ProjectID, details); err != nil {
+				log.Printf("Error recording activity for manual check: %v", err)
+			}
+		}()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"check":             check,
+		"conformance_level": report.ConformanceLevel,
+		"overall_score":     report.OverallScore,
+		"level_a_score":     report.LevelAScore,
+		"level_aa_score":    report.LevelAAScore,
+		"level_aaa_score":   report.LevelAAAScore,
+	})
+}
+
+// GetCheckHistory returns the changes made to a manual check, oldest first
+func (h *ComplianceHandler) GetCheckHistory(c *gin.Context) {
+	_, check := h.ownedCheck(c)
+	if check == nil {
+		return
+	}
+
+	var history []models.ManualCheckChange
+	if err := h.db.Where("check_id = ?", check.ID).Order("created_at ASC").Find(&history).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch history"})
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
+
+// AddEvidence attaches the "file" field of a multipart form to a manual
+// check, with an optional "description" field
+func (h *ComplianceHandler) AddEvidence(c *gin.Context) {
+	_, check := h.ownedCheck(c)
+	if check == nil {
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	// Leave room for the other form fields
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxEvidenceSize+1<<20)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("evidence exceeds %d bytes", maxEvidenceSize)})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "evidence upload requires a \"file\" field"})
+		return
+	}
+	if fileHeader.Size > maxEvidenceSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("evidence exceeds %d bytes", maxEvidenceSize)})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to open uploaded file"})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read uploaded file"})
+		return
+	}
+
+	contentType := fileHeader.Header.Get("Content-Type")
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = http.DetectContentType(data)
+	}
+	evidence := models.ManualEvidence{
+		CheckID:     check.ID,
+		FileName:    path.Base(fileHeader.Filename),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Description: strings.TrimSpace(c.PostForm("description")),
+		Data:        data,
+		UploadedBy:  userID,
+	}
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&evidence).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.ManualCheckChange{CheckID: check.ID, UserID: userID, Field: "evidence", NewValue: evidence.FileName}).Error
+	})
+	if err != nil {
+		log.Printf("Failed to save evidence for manual check %s: %v", check.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save evidence"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, evidence)
+}
+
+// ownedEvidence loads an evidence file of a manual check owned by the
+// current user. It writes the error response and returns nil on failure.
+func (h *ComplianceHandler) ownedEvidence(c *gin.Context, withData bool) (*models.ManualCheck, *models.ManualEvidence) {
+	_, check := h.ownedCheck(c)
+	if check == nil {
+		return nil, nil
+	}
+	evidenceID, err := uuid.Parse(c.Param("evidenceId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid evidence ID"})
+		return nil, nil
+	}
+
+	query := h.db
+	if !withData {
+		query = omitEvidenceData(query)
+	}
+	var evidence models.ManualEvidence
+	if err := query.Where("id = ? AND check_id = ?", evidenceID, check.ID).First(&evidence).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "evidence not found"})
+		return nil, nil
+	}
+	return check, &evidence
+}
+
+// GetEvidence downloads an evidence file
+func (h *ComplianceHandler) GetEvidence(c *gin.Context) {
+	_, evidence := h.ownedEvidence(c, true)
+	if evidence == nil {
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", evidence.FileName))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, evidenceContentType(evidence.ContentType), evidence.Data)
+}
+
+// evidenceContentType returns the type an evidence file is served with.
+// Only images, videos and PDFs keep the type they were uploaded with, other
+// files and SVG, which can run scripts, are served as plain downloads.
+func evidenceContentType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType == "image/svg+xml" {
+		return "application/octet-stream"
+	}
+	if strings.HasPrefix(mediaType, "image/") || strings.HasPrefix(mediaType, "video/") || mediaType == "application/pdf" {
+		return mediaType
+	}
+	return "application/octet-stream"
+}
+
+// DeleteEvidence removes an evidence file from a manual check
+func (h *ComplianceHandler) DeleteEvidence(c *gin.Context) {
+	check, evidence := h.ownedEvidence(c, false)
+	if evidence == nil {
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(evidence).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.ManualCheckChange{CheckID: check.ID, UserID: userID, Field: "evidence", OldValue: evidence.FileName}).Error
+	})
+	if err != nil {
+		log.Printf("Failed to delete evidence %s: %v", evidence.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete evidence"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "evidence deleted"})
+}
@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"tokubetsu/internal/models"
 	"tokubetsu/internal/services"
+	"tokubetsu/internal/standards"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -22,7 +24,8 @@ func NewComplianceHandler(db *gorm.DB, scanner *services.Scanner) *ComplianceHan
 	}
 }
 
-// GenerateReport generates a compliance report for a project
+// GenerateReport generates a compliance report for a project against the
+// ?profile= standards profile, or the project's default profile
 func (h *ComplianceHandler) GenerateReport(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -43,10 +46,19 @@ func (h *ComplianceHandler) GenerateReport(c *gin.Context) {
 		return
 	}
 
+	profileID := c.DefaultQuery("profile", project.DefaultProfile)
+	if profileID == "" {
+		profileID = standards.DefaultProfile
+	}
+	if _, ok := standards.Lookup(profileID); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown profile %q", profileID)})
+		return
+	}
+
 	// Generate compliance report
-	report, err := h.service.GenerateReport(projectID, project.URL)
+	report, err := h.service.GenerateReport(&project, profileID, scoringMethod(project))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(scanErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -56,11 +68,12 @@ func (h *ComplianceHandler) GenerateReport(c *gin.Context) {
 		return
 	}
 
-	// Preload violations for the response
-	if err := h.db.Preload("Violations").First(&report, report.ID).Error; err != nil {
+	// Preload violations and criterion results for the response
+	if err := h.db.Preload("Violations").Preload("Results").Preload("Checklist").First(&report, report.ID).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load report violations"})
 		return
 	}
+	services.PrepareReport(report)
 
 	c.JSON(http.StatusOK, report)
 }
@@ -90,11 +103,15 @@ func (h *ComplianceHandler) GetProjectReports(c *gin.Context) {
 	var reports []models.ComplianceReport
 	if err := h.db.Where("project_id = ?", projectID).
 		Preload("Violations").
+		Preload("Results").
 		Order("generated_at DESC").
 		Find(&reports).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch reports"})
 		return
 	}
+	for i := range reports {
+		services.PrepareReport(&reports[i])
+	}
 
 	c.JSON(http.StatusOK, reports)
 }
@@ -117,11 +134,42 @@ func (h *ComplianceHandler) GetReport(c *gin.Context) {
 	var report models.ComplianceReport
 	if err := h.db.Joins("Project").
 		Preload("Violations").
+		Preload("Results").
+		Preload("Checklist").
 		Where("compliance_reports.id = ? AND Project.user_id = ?", reportID, userID).
 		First(&report).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "report not found"})
 		return
 	}
+	services.PrepareReport(&report)
 
 	c.JSON(http.StatusOK, report)
 }
+
+// ownedReport loads a report of a project owned by the current user with
+// the given associations. It writes the error response and returns nil on failure.
+func (h *ComplianceHandler) ownedReport(c *gin.Context, preloads ...string) *models.ComplianceReport {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return nil
+	}
+
+	reportID, err := uuid.Parse(c.Param("reportId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report ID"})
+		return nil
+	}
+
+	query := h.db.Joins("Project")
+	for _, association := range preloads {
+		query = query.Preload(association)
+	}
+	var report models.ComplianceReport
+	if err := query.Where("compliance_reports.id = ? AND Project.user_id = ?", reportID, userID).
+		First(&report).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "report not found"})
+		return nil
+	}
+	return &report
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"tokubetsu/internal/models"
+	"tokubetsu/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SitePageSummary is the per-page entry stored in a site-level scan result
+type SitePageSummary struct {
+	ScanID     *uuid.UUID `json:"scan_id,omitempty"`
+	URL        string     `json:"url"`
+	Score      float64    `json:"score"`
+	Violations int        `json:"violations"`
+	Passes     int        `json:"passes"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// SiteScanSummary is stored as the result of a site-level scan
+type SiteScanSummary struct {
+	StartURL   string            `json:"start_url"`
+	Score      float64           `json:"score"`
+	Violations int               `json:"violations"`
+	Passes     int               `json:"passes"`
+	Pages      []SitePageSummary `json:"pages"`
+}
+
+// RunCrawl initiates a multi-page scan that starts at the project URL. The
+// optional JSON body holds services.CrawlOptions. The result is stored as a
+// site-level scan with one child scan per page.
+func (h *ProjectHandler) RunCrawl(c *gin.Context) {
+	userIDVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
+
+	projectID, err := uuid.Parse(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+
+	var project models.Project
+	if err := h.db.Where("id = ? AND user_id = ?", projectID, userID).First(&project).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "project not found"})
+		return
+	}
+
+	if project.URL == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "project URL is required for crawling"})
+		return
+	}
+
+	var opts services.CrawlOptions
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&opts); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	opts.ScoringMethod = scoringMethod(project)
+	opts.Profile = project.DefaultProfile
+	if err := opts.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	scan := models.Scan{
+		ProjectID: projectID,
+		ScanType:  "site",
+		Status:    "pending",
+		PageURL:   project.URL,
+	}
+	if err := h.db.Create(&scan).Error; err != nil {
+		log.Printf("Failed to create site scan record: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create scan record"})
+		return
+	}
+
+	project.LastScan = time.Now()
+	if err := h.db.Save(&project).Error; err != nil {
+		log.Printf("Failed to update project: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	go func() {
+		details := fmt.Sprintf("Site crawl initiated for project '%s'.", project.Title)
+		if err := RecordActivity(userID, "initiated_crawl", "scan", &project.ID, details); err != nil {
+			log.Printf("Error recording activity for crawl initiation: %v", err)
+		}
+	}()
+
+	go h.crawl(userID, project, scan, opts)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "crawl initiated",
+		"scan_id": scan.ID,
+		"status":  scan.Status,
+	})
+}
+
+// crawl performs a site crawl and stores its results
+func (h *ProjectHandler) crawl(userID uuid.UUID, project models.Project, scan models.Scan, opts services.CrawlOptions) {
+	scan.Status = "in_progress"
+	if err := h.db.Save(&scan).Error; err != nil {
+		log.Printf("Failed to update site scan status to in_progress: %v", err)
+		return
+	}
+
+	scanner := services.NewScanner().WithFetcher(services.ProjectFetcher(&project))
+	site, err := scanner.CrawlSite(project.URL, opts)
+	if err != nil {
+		log.Printf("Error crawling site: %v", err)
+		scan.Status = "failed"
+		scan.Summary = fmt.Sprintf("Crawl failed: %v", err)
+		if err := h.db.Save(&scan).Error; err != nil {
+			log.Printf("Failed to update site scan status to failed: %v", err)
+		}
+
+		details := fmt.Sprintf("Crawl failed for project '%s': %v", project.Title, err)
+		if err := RecordActivity(userID, "crawl_failed", "scan", &project.ID, details); err != nil {
+			log.Printf("Error recording activity for crawl failure: %v", err)
+		}
+		return
+	}
+
+	summary := SiteScanSummary{
+		StartURL:   site.StartURL,
+		Score:      site.Score,
+		Violations: site.Violations,
+		Passes:     site.Passes,
+		Pages:      make([]SitePageSummary, 0, len(site.Pages)),
+	}
+	for _, page := range site.Pages {
+		pageSummary := SitePageSummary{URL: page.URL, Error: page.Error}
+
+		child := models.Scan{
+			ProjectID: project.ID,
+			ParentID:  &scan.ID,
+			ScanType:  "page",
+			Status:    "failed",
+			PageURL:   page.URL,
+		}
+		if page.Error != "" {
+			child.Summary = fmt.Sprintf("Scan failed: %s", page.Error)
+		}
+		if err := h.db.Create(&child).Error; err != nil {
+			log.Printf("Failed to create page scan record for %s: %v", page.URL, err)
+			summary.Pages = append(summary.Pages, pageSummary)
+			continue
+		}
+		pageSummary.ScanID = &child.ID
+
+		if page.Result != nil {
+			if err := saveScanResult(h.db, &child, page.Result, page.Score); err != nil {
+				log.Printf("Failed to save page scan result for %s: %v", page.URL, err)
+			}
+			pageSummary.Score = page.Score
+			pageSummary.Violations = len(page.Result.Violations)
+			pageSummary.Passes = len(page.Result.Passes)
+		}
+		summary.Pages = append(summary.Pages, pageSummary)
+	}
+
+	resultJSON, err := json.Marshal(summary)
+	if err != nil {
+		log.Printf("Failed to marshal site scan result to JSON: %v", err)
+		return
+	}
+	resultJSONStr := string(resultJSON)
+
+	scan.Status = "completed"
+	scan.Score = site.Score
+	scan.ResultJSON = &resultJSONStr
+	scan.Summary = fmt.Sprintf("Scanned %d pages: found %d violations and %d passes", len(site.Pages), site.Violations, site.Passes)
+	if err := h.db.Save(&scan).Error; err != nil {
+		log.Printf("Failed to update site scan with results: %v", err)
+		return
+	}
+
+	project.Score = site.Score
+	if err := h.db.Save(&project).Error; err != nil {
+		log.Printf("Failed to update project score: %v", err)
+	}
+
+	details := fmt.Sprintf("Crawl completed for project '%s': %d pages with score %.2f%%", project.Title, len(site.Pages), site.Score)
+	if err := RecordActivity(userID, "crawl_completed", "scan", &project.ID, details); err != nil {
+		log.Printf("Error recording activity for crawl completion: %v", err)
+	}
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"tokubetsu/internal/fetch"
+	"tokubetsu/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/net/http/httpguts"
+)
+
+// secretMask replaces stored secrets in responses. Sending it back keeps the
+// stored value, so clients can save settings without knowing the secrets.
+const secretMask = "********"
+
+// Limits of the fetch settings of a project
+const (
+	maxFetchTimeoutSeconds = 120
+	maxFetchRedirects      = 20
+	maxFetchBodyMB         = 50
+)
+
+// redactFetchSettings masks the header values, cookie values, password and
+// token of fetch settings
+func redactFetchSettings(settings models.FetchSettings) models.FetchSettings {
+	mask := func(values map[string]string) map[string]string {
+		masked := make(map[string]string, len(values))
+		for name := range values {
+			masked[name] = secretMask
+		}
+		return masked
+	}
+	settings.Headers = mask(settings.Headers)
+	settings.Cookies = mask(settings.Cookies)
+	if settings.Password != "" {
+		settings.Password = secretMask
+	}
+	if settings.Token != "" {
+		settings.Token = secretMask
+	}
+	return settings
+}
+
+// unmaskFetchSettings replaces masked values in input with the stored ones
+func unmaskFetchSettings(input *models.FetchSettings, stored models.FetchSettings) {
+	for _, values := range []struct{ input, stored map[string]string }{
+		{input.Headers, stored.Headers},
+		{input.Cookies, stored.Cookies},
+	} {
+		for name, value := range values.input {
+			if value == secretMask {
+				values.input[name] = values.stored[name]
+			}
+		}
+	}
+	if input.Password == secretMask {
+		input.Password = stored.Password
+	}
+	if input.Token == secretMask {
+		input.Token = stored.Token
+	}
+}
+
+// validateFetchSettings checks fetch settings before they are stored
+func validateFetchSettings(settings models.FetchSettings) error {
+	switch {
+	case settings.TimeoutSeconds < 0 || settings.TimeoutSeconds > maxFetchTimeoutSeconds:
+		return fmt.Errorf("timeout_seconds must be between 0 and %d", maxFetchTimeoutSeconds)
+	case settings.MaxRedirects < 0 || settings.MaxRedirects > maxFetchRedirects:
+		return fmt.Errorf("max_redirects must be between 0 and %d", maxFetchRedirects)
+	case settings.MaxBodyMB < 0 || settings.MaxBodyMB > maxFetchBodyMB:
+		return fmt.Errorf("max_body_mb must be between 0 and %d", maxFetchBodyMB)
+	}
+	for name, value := range settings.Headers {
+		if !httpguts.ValidHeaderFieldName(name) || !httpguts.ValidHeaderFieldValue(value) {
+			return fmt.Errorf("invalid header %q", name)
+		}
+	}
+	for name, value := range settings.Cookies {
+		if !httpguts.ValidHeaderFieldName(name) || !httpguts.ValidHeaderFieldValue(value) {
+			return fmt.Errorf("invalid cookie %q", name)
+		}
+	}
+	switch settings.AuthType {
+	case "":
+	case fetch.AuthBasic:
+		if settings.Username == "" {
+			return fmt.Errorf("basic auth requires a username")
+		}
+	case fetch.AuthBearer:
+		if settings.Token == "" {
+			return fmt.Errorf("bearer auth requires a token")
+		}
+	default:
+		return fmt.Errorf("unknown auth_type %q, must be basic or bearer", settings.AuthType)
+	}
+	return nil
+}
+
+// GetFetchSettings returns how the project's site is fetched, with its
+// secrets masked
+func (h *ProjectHandler) GetFetchSettings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	projectID, err := uuid.Parse(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+
+	var project models.Project
+	if err := h.db.Where("id = ? AND user_id = ?", projectID, userID).First(&project).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "project not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, redactFetchSettings(project.FetchSettings))
+}
+
+// UpdateFetchSettings replaces how the project's site is fetched. Masked
+// values keep the stored secret they stand for.
+func (h *ProjectHandler) UpdateFetchSettings(c *gin.Context) {
+	userIDVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
+
+	projectID, err := uuid.Parse(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+
+	var project models.Project
+	if err := h.db.Where("id = ? AND user_id = ?", projectID, userID).First(&project).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "project not found"})
+		return
+	}
+
+	var input models.FetchSettings
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// The flag describes the stored settings, the input replaces them
+	input.SecretsUnavailable = false
+	unmaskFetchSettings(&input, project.FetchSettings)
+	if err := validateFetchSettings(input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	project.FetchSettings = input
+	if err := h.db.Save(&project).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Record activity, without the settings themselves
+	go func() {
+		details := fmt.Sprintf("Fetch settings of project '%s' updated.", project.Title)
+		if err := RecordActivity(userID, "updated_fetch_settings", "project", &project.ID, details); err != nil {
+			log.Printf("Error recording activity for fetch settings update: %v", err)
+		}
+	}()
+
+	c.JSON(http.StatusOK, redactFetchSettings(input))
+}
@@ -6,7 +6,9 @@ import (
 	"time"
 
 	"tokubetsu/internal/models"
+	"tokubetsu/internal/scoring"
 	"tokubetsu/internal/services"
+	"tokubetsu/internal/standards"
 
 	"fmt"
 	"log"
@@ -25,6 +27,10 @@ type CreateProjectInput struct {
 	Name        string `json:"name" binding:"required"`
 	Description string `json:"description"`
 	URL         string `json:"url"`
+	// Standards profile reports are generated against, see /api/profiles
+	DefaultProfile string `json:"default_profile"`
+	// impact_weighted (default), pass_ratio or criteria
+	ScoringMethod string `json:"scoring_method"`
 }
 
 func NewProjectHandler(db *gorm.DB) *ProjectHandler {
@@ -47,13 +53,30 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 	}
 	userID := userIDVal.(uuid.UUID)
 
+	if input.DefaultProfile == "" {
+		input.DefaultProfile = standards.DefaultProfile
+	}
+	if _, ok := standards.Lookup(input.DefaultProfile); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown profile %q", input.DefaultProfile)})
+		return
+	}
+	if input.ScoringMethod == "" {
+		input.ScoringMethod = scoring.DefaultMethod
+	}
+	if !scoring.Valid(input.ScoringMethod) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown scoring method %q", input.ScoringMethod)})
+		return
+	}
+
 	project := models.Project{
-		Title:       input.Title,
-		Name:        input.Name,
-		Description: input.Description,
-		URL:         input.URL,
-		UserID:      userID,
-		Status:      "active",
+		Title:          input.Title,
+		Name:           input.Name,
+		Description:    input.Description,
+		URL:            input.URL,
+		DefaultProfile: input.DefaultProfile,
+		ScoringMethod:  input.ScoringMethod,
+		UserID:         userID,
+		Status:         "active",
 	}
 
 	if err := h.db.Create(&project).Error; err != nil {
@@ -146,6 +169,21 @@ func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 	project.UserID = userID
 	project.ID = projectID
 
+	if project.DefaultProfile == "" {
+		project.DefaultProfile = standards.DefaultProfile
+	}
+	if _, ok := standards.Lookup(project.DefaultProfile); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown profile %q", project.DefaultProfile)})
+		return
+	}
+	if project.ScoringMethod == "" {
+		project.ScoringMethod = scoring.DefaultMethod
+	}
+	if !scoring.Valid(project.ScoringMethod) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown scoring method %q", project.ScoringMethod)})
+		return
+	}
+
 	if err := h.db.Save(&project).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -237,7 +275,7 @@ func (h *ProjectHandler) RunScan(c *gin.Context) {
 		return
 	}
 
-	log.Printf("Found project: %+v", project)
+	log.Printf("Found project: %s", project.ID)
 
 	// Validate project URL
 	if project.URL == "" {
@@ -291,8 +329,8 @@ func (h *ProjectHandler) RunScan(c *gin.Context) {
 			return
 		}
 
-		// Create scanner instance
-		scanner := services.NewScanner()
+		// Create a scanner that fetches with the project's settings
+		scanner := services.NewScanner().WithFetcher(services.ProjectFetcher(&project))
 
 		// Perform the scan
 		result, err := scanner.ScanURL(project.URL)
@@ -315,68 +353,15 @@ func (h *ProjectHandler) RunScan(c *gin.Context) {
 			return
 		}
 
-		// Calculate score
-		totalChecks := len(result.Passes) + len(result.Violations)
-		var score float64
-		if totalChecks > 0 {
-			score = float64(len(result.Passes)) / float64(totalChecks) * 100
-		}
-
+		score := scoreResult(scanner, project, result)
 		log.Printf("Scan completed with score: %.2f", score)
 
-		// Update scan with results
-		scan.Status = "completed"
-		scan.Score = score
-		scan.Summary = fmt.Sprintf("Found %d violations and %d passes", len(result.Violations), len(result.Passes))
-
-		// Store result as JSON
-		resultJSON, err := json.Marshal(result)
-		if err != nil {
-			log.Printf("Failed to marshal scan result to JSON: %v", err)
-			return
-		}
-		resultJSONStr := string(resultJSON)
-		scan.ResultJSON = &resultJSONStr
-
-		if err := h.db.Save(&scan).Error; err != nil {
+		// Update scan with results and create its issues
+		if err := saveScanResult(h.db, &scan, result, score); err != nil {
 			log.Printf("Failed to update scan with results: %v", err)
 			return
 		}
 
-		// Create accessibility issues from violations
-		for _, violation := range result.Violations {
-			// Determine severity based on impact
-			severity := "medium"
-			switch violation.Impact {
-			case "critical":
-				severity = "critical"
-			case "serious":
-				severity = "high"
-			case "moderate":
-				severity = "medium"
-			case "minor":
-				severity = "low"
-			}
-
-			// Get first node if available
-			htmlSnippet := "No element specified"
-			if len(violation.Nodes) > 0 {
-				htmlSnippet = violation.Nodes[0]
-			}
-
-			issue := models.AccessibilityIssue{
-				ScanID:        scan.ID,
-				Severity:      severity,
-				Description:   violation.Description,
-				HTMLSnippet:   htmlSnippet,
-				FixSuggestion: violation.Help,
-			}
-
-			if err := h.db.Create(&issue).Error; err != nil {
-				log.Printf("Failed to create accessibility issue: %v", err)
-			}
-		}
-
 		// Update project score with the latest scan score
 		project.Score = score
 		if err := h.db.Save(&project).Error; err != nil {
@@ -400,6 +385,96 @@ func (h *ProjectHandler) RunScan(c *gin.Context) {
 	})
 }
 
+// scoreResult scores a scan result with the project's scoring method against
+// its default standards profile
+func scoreResult(scanner *services.Scanner, project models.Project, result *services.ScanResult) float64 {
+	score, err := scanner.Score(result, scoringMethod(project), standards.Resolve(project.DefaultProfile))
+	if err != nil {
+		log.Printf("Failed to score scan of project %s: %v", project.ID, err)
+	}
+	return score.Overall
+}
+
+// scoringMethod returns the scoring method of a project, or the default
+// method for projects that predate it
+func scoringMethod(project models.Project) string {
+	if scoring.Valid(project.ScoringMethod) {
+		return project.ScoringMethod
+	}
+	return scoring.DefaultMethod
+}
+
+// saveScanResult marks a scan as completed with the given result and score
+// and creates an accessibility issue for each violation
+func saveScanResult(db *gorm.DB, scan *models.Scan, result *services.ScanResult, score float64) error {
+	scan.Status = "completed"
+	scan.Score = score
+	scan.Summary = fmt.Sprintf("Found %d violations and %d passes", len(result.Violations), len(result.Passes))
+
+	// Store result as JSON
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal scan result to JSON: %v", err)
+	}
+	resultJSONStr := string(resultJSON)
+	scan.ResultJSON = &resultJSONStr
+
+	if result.Tree != nil {
+		treeJSON, err := json.Marshal(result.Tree)
+		if err != nil {
+			return fmt.Errorf("failed to marshal accessibility tree to JSON: %v", err)
+		}
+		treeJSONStr := string(treeJSON)
+		scan.A11yTree = &treeJSONStr
+	}
+
+	if err := db.Save(scan).Error; err != nil {
+		return err
+	}
+
+	// Create accessibility issues from violations
+	for _, violation := range result.Violations {
+		// Determine severity based on impact
+		severity := "medium"
+		switch violation.Impact {
+		case "critical":
+			severity = "critical"
+		case "serious":
+			severity = "high"
+		case "moderate":
+			severity = "medium"
+		case "minor":
+			severity = "low"
+		}
+
+		// Get first node if available
+		htmlSnippet := "No element specified"
+		if len(violation.Nodes) > 0 {
+			htmlSnippet = violation.Nodes[0]
+		}
+
+		issue := models.AccessibilityIssue{
+			ScanID:        scan.ID,
+			Severity:      severity,
+			Description:   violation.Description,
+			HTMLSnippet:   htmlSnippet,
+			FixSuggestion: violation.Help,
+		}
+		if len(violation.Targets) > 0 {
+			issue.Selector = violation.Targets[0].Selector
+			issue.XPath = violation.Targets[0].XPath
+			issue.Line = violation.Targets[0].Line
+			issue.Column = violation.Targets[0].Column
+		}
+
+		if err := db.Create(&issue).Error; err != nil {
+			log.Printf("Failed to create accessibility issue: %v", err)
+		}
+	}
+
+	return nil
+}
+
 // ListProjects returns all projects for the authenticated user
 func (h *ProjectHandler) ListProjects(c *gin.Context) {
 	userID, exists := c.Get("user_id")
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tokubetsu/internal/services"
+	"tokubetsu/internal/standards"
+	"tokubetsu/internal/wcag"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RulesHandler struct {
+	rules *services.RuleRegistry
+}
+
+func NewRulesHandler(rules *services.RuleRegistry) *RulesHandler {
+	return &RulesHandler{rules: rules}
+}
+
+// ListRules returns the scanner rules with the WCAG success criteria each
+// one tests. ?criterion=1.4.3 limits the list to rules testing that criterion.
+func (h *RulesHandler) ListRules(c *gin.Context) {
+	catalog := h.rules.Catalog()
+
+	if number := c.Query("criterion"); number != "" {
+		if _, ok := wcag.Lookup(number); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown success criterion"})
+			return
+		}
+		filtered := make([]services.CatalogEntry, 0)
+		for _, entry := range catalog {
+			for _, criterion := range entry.Criteria {
+				if criterion.Number == number {
+					filtered = append(filtered, entry)
+					break
+				}
+			}
+		}
+		catalog = filtered
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"wcag_version": "2.2",
+		"rules":        catalog,
+	})
+}
+
+// ListProfiles returns the standards profiles reports can be generated
+// against, without their requirements
+func (h *RulesHandler) ListProfiles(c *gin.Context) {
+	profiles := make([]gin.H, 0)
+	for _, profile := range standards.All() {
+		profiles = append(profiles, gin.H{
+			"id":           profile.ID,
+			"name":         profile.Name,
+			"description":  profile.Description,
+			"level":        profile.Level,
+			"wcag_version": profile.WCAG,
+			"requirements": len(profile.Requirements),
+			"default":      profile.ID == standards.DefaultProfile,
+		})
+	}
+	c.JSON(http.StatusOK, profiles)
+}
+
+// GetProfile returns a standards profile with its requirements
+func (h *RulesHandler) GetProfile(c *gin.Context) {
+	profile, ok := standards.Lookup(c.Param("profileId"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "profile not found"})
+		return
+	}
+	c.JSON(http.StatusOK, profile)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -8,8 +9,11 @@ import (
 	"strconv"
 	"time"
 	"tokubetsu/internal/database"
+	"tokubetsu/internal/fetch"
 	"tokubetsu/internal/models"
+	"tokubetsu/internal/scoring"
 	"tokubetsu/internal/services"
+	"tokubetsu/internal/standards"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -19,13 +23,25 @@ type ScanHandler struct {
 	scanner *services.Scanner
 }
 
+// scanErrorStatus returns the response status for a failed scan: bad gateway
+// when the scanned site answered with an error status, so clients can tell a
+// broken site from a failure of the scanner
+func scanErrorStatus(err error) int {
+	var statusErr *fetch.StatusError
+	if errors.As(err, &statusErr) {
+		return http.StatusBadGateway
+	}
+	return http.StatusInternalServerError
+}
+
 func NewScanHandler() *ScanHandler {
 	return &ScanHandler{
 		scanner: services.NewScanner(),
 	}
 }
 
-// ScanHandler handles accessibility scanning of external URLs
+// ScanHandler handles accessibility scanning of external URLs. ?scoring=
+// selects the scoring method, see package scoring.
 func (h *ScanHandler) ScanHandler(c *gin.Context) {
 	// Get URL from query parameter
 	targetURL := c.Query("url")
@@ -38,6 +54,12 @@ func (h *ScanHandler) ScanHandler(c *gin.Context) {
 		return
 	}
 
+	method := c.DefaultQuery("scoring", scoring.DefaultMethod)
+	if !scoring.Valid(method) {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("Unknown scoring method: %s", method)})
+		return
+	}
+
 	// Parse and validate the URL
 	parsedURL, err := url.Parse(targetURL)
 	if err != nil {
@@ -66,28 +88,38 @@ func (h *ScanHandler) ScanHandler(c *gin.Context) {
 	result, err := h.scanner.ScanURL(targetURL)
 	if err != nil {
 		log.Printf("Error running scan: %v", err)
-		c.JSON(500, gin.H{
+		c.JSON(scanErrorStatus(err), gin.H{
 			"error": fmt.Sprintf("Failed to scan URL: %v", err),
 		})
 		return
 	}
 
-	// Calculate score (0-100)
-	totalChecks := len(result.Passes) + len(result.Violations)
-	var score int
-	if totalChecks > 0 {
-		score = (len(result.Passes) * 100) / totalChecks
+	// Calculate score (0-100) against the default profile
+	score, err := h.scanner.Score(result, method, standards.Resolve(standards.DefaultProfile))
+	if err != nil {
+		log.Printf("Error scoring scan: %v", err)
+		c.JSON(500, gin.H{"error": fmt.Sprintf("Failed to score scan: %v", err)})
+		return
 	}
 
 	log.Printf("=== Scan Results ===")
 	log.Printf("Number of passes: %d", len(result.Passes))
 	log.Printf("Number of violations: %d", len(result.Violations))
-	log.Printf("Score: %d", score)
+	log.Printf("Score: %.2f (%s)", score.Overall, score.Method)
 
 	response := gin.H{
-		"violations": result.Violations,
-		"passes":     len(result.Passes),
-		"score":      score,
+		"violations":     result.Violations,
+		"passes":         len(result.Passes),
+		"score":          score.Overall,
+		"scoring_method": score.Method,
+		"tab_order":      result.TabOrder,
+		"readability":    result.Readability,
+		"color_vision":   result.ColorVision,
+	}
+
+	// The accessibility tree is only included on request since it can be large
+	if includeTree, _ := strconv.ParseBool(c.Query("a11y_tree")); includeTree {
+		response["a11y_tree"] = result.Tree
 	}
 
 	// Debug log the violations data
@@ -156,6 +188,9 @@ func ListScans(c *gin.Context) {
 	var scans []models.Scan
 	query := database.DB.Model(&models.Scan{}).Joins("JOIN projects ON projects.id = scans.project_id AND projects.user_id = ?", userID.(uuid.UUID))
 
+	// Page scans of a crawl are listed through their site-level scan
+	query = query.Where("scans.parent_id IS NULL")
+
 	projectIDStr := c.Query("projectId")
 	if projectIDStr != "" {
 		projectID, err := uuid.Parse(projectIDStr)
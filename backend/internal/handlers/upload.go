@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"tokubetsu/internal/models"
+	"tokubetsu/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxUploadSize caps the size of an uploaded HTML document or site archive
+const maxUploadSize = 50 << 20 // 50 MiB
+
+// UploadedPageResponse summarizes the stored scan of one uploaded page
+type UploadedPageResponse struct {
+	ScanID     uuid.UUID `json:"scan_id,omitempty"`
+	Path       string    `json:"path"`
+	URL        string    `json:"url"`
+	Status     string    `json:"status"`
+	Score      float64   `json:"score"`
+	Violations int       `json:"violations"`
+	Passes     int       `json:"passes"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// ScanUpload scans HTML that has not been deployed yet. The request body may be
+// a raw HTML document, a zip archive of a static site, or a multipart form
+// whose "file" field holds either. Every page is stored as its own scan.
+func (h *ProjectHandler) ScanUpload(c *gin.Context) {
+	userIDVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
+
+	projectID, err := uuid.Parse(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+
+	var project models.Project
+	if err := h.db.Where("id = ? AND user_id = ?", projectID, userID).First(&project).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "project not found"})
+		return
+	}
+
+	body, filename, err := readUpload(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Pages are resolved against the URL they will be deployed to
+	baseURL := c.DefaultQuery("base_url", project.URL)
+
+	// Stylesheets of the project's site are fetched with its settings
+	scanner := services.NewScanner().WithFetcher(services.ProjectFetcher(&project))
+	var pages []services.PageResult
+	if isZipUpload(c.ContentType(), filename, body) {
+		pages, err = scanner.ScanArchive(bytes.NewReader(body), int64(len(body)), baseURL)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	} else {
+		result, err := scanner.ScanHTML(bytes.NewReader(body), baseURL)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		pages = []services.PageResult{{Path: filename, URL: baseURL, Result: result}}
+	}
+
+	responses := make([]UploadedPageResponse, 0, len(pages))
+	var totalScore float64
+	var scored int
+	for _, page := range pages {
+		response := UploadedPageResponse{Path: page.Path, URL: page.URL}
+
+		scan := models.Scan{
+			ProjectID: projectID,
+			ScanType:  "upload",
+			Status:    "failed",
+			PageURL:   page.URL,
+		}
+		if page.Error != "" {
+			scan.Summary = fmt.Sprintf("Scan failed: %s", page.Error)
+		}
+		if err := h.db.Create(&scan).Error; err != nil {
+			log.Printf("Failed to create scan record for %s: %v", page.Path, err)
+			response.Status = "failed"
+			response.Error = "failed to create scan record"
+			responses = append(responses, response)
+			continue
+		}
+		response.ScanID = scan.ID
+
+		if page.Result != nil {
+			if err := saveScanResult(h.db, &scan, page.Result, scoreResult(scanner, project, page.Result)); err != nil {
+				log.Printf("Failed to save scan result for %s: %v", page.Path, err)
+				scan.Status = "failed"
+				page.Error = "failed to save scan result"
+			} else {
+				response.Violations = len(page.Result.Violations)
+				response.Passes = len(page.Result.Passes)
+				totalScore += scan.Score
+				scored++
+			}
+		}
+
+		response.Status = scan.Status
+		response.Score = scan.Score
+		response.Error = page.Error
+		responses = append(responses, response)
+	}
+
+	// The project score reflects the average of the uploaded pages
+	project.LastScan = time.Now()
+	if scored > 0 {
+		project.Score = totalScore / float64(scored)
+	}
+	if err := h.db.Save(&project).Error; err != nil {
+		log.Printf("Failed to update project: %v", err)
+	}
+
+	go func() {
+		details := fmt.Sprintf("Uploaded content scanned for project '%s': %d page(s).", project.Title, len(pages))
+		err := RecordActivity(userID, "upload_scan_completed", "scan", &project.ID, details)
+		if err != nil {
+			log.Printf("Error recording activity for upload scan: %v", err)
+		}
+	}()
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "upload scanned",
+		"pages":   responses,
+	})
+}
+
+// readUpload returns the uploaded content and its file name, if any
+func readUpload(c *gin.Context) ([]byte, string, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadSize)
+
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return nil, "", fmt.Errorf("multipart upload requires a \"file\" field")
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to open uploaded file")
+		}
+		defer file.Close()
+
+		body, err := io.ReadAll(file)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read uploaded file")
+		}
+		return body, path.Base(fileHeader.Filename), nil
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, "", fmt.Errorf("upload exceeds %d bytes", maxUploadSize)
+		}
+		return nil, "", fmt.Errorf("failed to read request body")
+	}
+	if len(body) == 0 {
+		return nil, "", fmt.Errorf("request body is empty")
+	}
+	return body, "", nil
+}
+
+// isZipUpload detects a site archive by content type, file extension or magic bytes
+func isZipUpload(contentType, filename string, body []byte) bool {
+	switch contentType {
+	case "application/zip", "application/x-zip-compressed":
+		return true
+	}
+	if strings.EqualFold(path.Ext(filename), ".zip") {
+		return true
+	}
+	return bytes.HasPrefix(body, []byte("PK\x03\x04"))
+}
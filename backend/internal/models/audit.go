@@ -0,0 +1,63 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// Manual check results
+const (
+	ManualPending       = "pending"
+	ManualPassed        = "passed"
+	ManualFailed        = "failed"
+	ManualNotApplicable = "not_applicable"
+)
+
+// ManualCheck is an item of a report's manual audit checklist: a requirement
+// automated testing could not decide, or whose automated pass needs review.
+// Criterion is the key of the matching
+// CriterionResult.
+type ManualCheck struct {
+	Base
+	ReportID        uuid.UUID  `json:"report_id" gorm:"type:uuid;not null;uniqueIndex:idx_manual_checks_report_criterion"`
+	Criterion       string     `json:"criterion" gorm:"not null;uniqueIndex:idx_manual_checks_report_criterion"`
+	Requirement     string     `json:"requirement"`
+	Level           string     `json:"level" gorm:"type:varchar(4)"`
+	AutomatedStatus string     `json:"automated_status" gorm:"type:varchar(20);not null"` // Criterion status before the audit
+	Result          string     `json:"result" gorm:"type:varchar(20);not null;default:'pending'"`
+	Notes           string     `json:"notes" gorm:"type:text"`
+	AuditorID       *uuid.UUID `json:"auditor_id,omitempty" gorm:"type:uuid"` // Last user to record a result
+
+	// Relationships
+	Report   ComplianceReport    `json:"-" gorm:"foreignKey:ReportID"`
+	Evidence []ManualEvidence    `json:"evidence,omitempty" gorm:"foreignKey:CheckID"`
+	History  []ManualCheckChange `json:"history,omitempty" gorm:"foreignKey:CheckID"`
+}
+
+// ManualEvidence is a file attached to a manual check, such as a screenshot
+// or screen reader recording
+type ManualEvidence struct {
+	Base
+	CheckID     uuid.UUID `json:"check_id" gorm:"type:uuid;not null;index"`
+	FileName    string    `json:"file_name" gorm:"not null"`
+	ContentType string    `json:"content_type" gorm:"not null"`
+	Size        int64     `json:"size"`
+	Description string    `json:"description"`
+	Data        []byte    `json:"-" gorm:"type:bytea;not null"`
+	UploadedBy  uuid.UUID `json:"uploaded_by" gorm:"type:uuid;not null"`
+
+	// Relationship
+	Check ManualCheck `json:"-" gorm:"foreignKey:CheckID"`
+}
+
+// ManualCheckChange records one change to a manual check
+type ManualCheckChange struct {
+	Base
+	CheckID  uuid.UUID `json:"check_id" gorm:"type:uuid;not null;index"`
+	UserID   uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	Field    string    `json:"field" gorm:"type:varchar(20);not null"` // result, notes or evidence
+	OldValue string    `json:"old_value" gorm:"type:text"`
+	NewValue string    `json:"new_value" gorm:"type:text"`
+
+	// Relationship
+	Check ManualCheck `json:"-" gorm:"foreignKey:CheckID"`
+}
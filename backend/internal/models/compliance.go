@@ -11,23 +11,89 @@ type ComplianceReport struct {
 	Base
 	ProjectID    uuid.UUID `json:"project_id" gorm:"type:uuid;not null"`
 	URL          string    `json:"url" gorm:"not null"`
+	Profile      string    `json:"profile" gorm:"type:varchar(40);not null;default:'wcag22-aa'"` // Standards profile the report is measured against
 	GeneratedAt  time.Time `json:"generated_at" gorm:"not null"`
 	OverallScore float64   `json:"overall_score" gorm:"not null"`
+	// Scoring method of the project when the report was generated
+	ScoringMethod string `json:"scoring_method" gorm:"type:varchar(20);not null;default:'impact_weighted'"`
+	// Elements that passed, by the criteria of their rule separated by spaces
+	PassCounts map[string]int `json:"pass_counts" gorm:"type:jsonb;serializer:json"`
 
-	// WCAG Level Scores
-	LevelAScore   float64 `json:"level_a_score" gorm:"not null"`
-	LevelAAScore  float64 `json:"level_aa_score" gorm:"not null"`
-	LevelAAAScore float64 `json:"level_aaa_score" gorm:"not null"`
+	// WCAG Level Scores, null when the report has nothing to score at a level
+	LevelAScore   *float64 `json:"level_a_score"`
+	LevelAAScore  *float64 `json:"level_aa_score"`
+	LevelAAAScore *float64 `json:"level_aaa_score"`
 
-	// Category Scores
-	PerceivableScore    float64 `json:"perceivable_score" gorm:"not null"`
-	OperableScore       float64 `json:"operable_score" gorm:"not null"`
-	UnderstandableScore float64 `json:"understandable_score" gorm:"not null"`
-	RobustScore         float64 `json:"robust_score" gorm:"not null"`
+	// Highest WCAG level every criterion of the profile conforms to: A, AA, AAA or none
+	ConformanceLevel string `json:"conformance_level" gorm:"type:varchar(4);not null;default:'none'"`
+	// Every requirement of the profile passed or does not apply
+	Conforms bool `json:"conforms"`
+
+	// Category Scores, null when the report has nothing to score for a principle
+	PerceivableScore    *float64 `json:"perceivable_score"`
+	OperableScore       *float64 `json:"operable_score"`
+	UnderstandableScore *float64 `json:"understandable_score"`
+	RobustScore         *float64 `json:"robust_score"`
 
 	// Relationships
 	Project    Project               `json:"-" gorm:"foreignKey:ProjectID"`
 	Violations []ComplianceViolation `json:"violations" gorm:"foreignKey:ReportID"`
+	Results    []CriterionResult     `json:"results" gorm:"foreignKey:ReportID"`
+	Checklist  []ManualCheck         `json:"checklist,omitempty" gorm:"foreignKey:ReportID"`
+
+	// Violations grouped by success criterion, computed when the report is served
+	Criteria []CriterionGroup `json:"criteria" gorm:"-"`
+}
+
+// CriterionGroup collects the violations of a report that fail one
+// requirement of its profile. Violations of criteria outside the profile are
+// grouped by criterion and marked out of scope.
+type CriterionGroup struct {
+	Requirement string   `json:"requirement"` // Clause number in the profile
+	InScope     bool     `json:"in_scope"`
+	Criterion   string   `json:"criterion"`
+	Title       string   `json:"title"`
+	Level       string   `json:"level"`
+	Principle   string   `json:"principle"`
+	URL         string   `json:"url"`
+	Rules       []string `json:"rules"`
+	Count       int      `json:"count"`
+}
+
+// Criterion result statuses
+const (
+	CriterionPassed        = "passed"
+	CriterionFailed        = "failed"
+	CriterionNotApplicable = "not_applicable"
+	CriterionNeedsManual   = "needs_manual" // no automated rule can decide the criterion
+	CriterionNotTested     = "not_tested"   // rules exist but none of them ran
+	CriterionNeedsReview   = "needs_review" // automated rules passed, but only a reviewer can confirm the criterion
+)
+
+// Criterion result sources
+const (
+	SourceAutomated = "automated"
+	SourceManual    = "manual"
+)
+
+// CriterionResult is the outcome of one requirement of the report's profile.
+// Criterion is the WCAG success criterion the requirement incorporates, or the
+// requirement's own clause number for additions of the standard.
+type CriterionResult struct {
+	Base
+	ReportID    uuid.UUID `json:"report_id" gorm:"type:uuid;not null;index"`
+	Criterion   string    `json:"criterion" gorm:"not null"`
+	Requirement string    `json:"requirement"`                  // Clause number in the profile, e.g. "9.1.1.1"
+	Level       string    `json:"level" gorm:"type:varchar(4)"` // WCAG level, empty for additions
+	Status      string    `json:"status" gorm:"type:varchar(20);not null"`
+	Source      string    `json:"source" gorm:"type:varchar(20);not null;default:'automated'"` // automated or manual
+	Automatable bool      `json:"automatable"`                                                 // At least one scanner rule tests the criterion
+	Rules       []string  `json:"rules" gorm:"type:jsonb;serializer:json"`                     // Rules that ran against the criterion
+	Passes      int       `json:"passes"`
+	Violations  int       `json:"violations"`
+
+	// Relationship
+	Report ComplianceReport `json:"-" gorm:"foreignKey:ReportID"`
 }
 
 // ComplianceViolation represents a specific WCAG violation found during scanning
@@ -36,12 +102,30 @@ type ComplianceViolation struct {
 	ReportID    uuid.UUID `json:"report_id" gorm:"type:uuid;not null"`
 	RuleID      string    `json:"rule_id" gorm:"not null"`
 	WCAGLevel   string    `json:"wcag_level" gorm:"not null"`
-	Criterion   string    `json:"criterion" gorm:"not null"`
+	Criterion   string    `json:"criterion" gorm:"not null"`                  // Primary success criterion, e.g. "1.4.3"
+	Criteria    []string  `json:"criteria" gorm:"type:jsonb;serializer:json"` // Every success criterion the rule tests
 	Impact      string    `json:"impact" gorm:"not null"`
 	Description string    `json:"description" gorm:"not null"`
 	Element     string    `json:"element" gorm:"not null"`
+	Selector    string    `json:"selector"`
+	XPath       string    `json:"xpath"`
+	Line        int       `json:"line"`
+	Column      int       `json:"column"`
 	Suggestion  string    `json:"suggestion"`
 
 	// Relationship
 	Report ComplianceReport `json:"-" gorm:"foreignKey:ReportID"`
 }
+
+// ACRRemark is a reviewer's remarks on one criterion of a report's
+// Accessibility Conformance Report. Criterion is a WCAG success criterion or
+// a functional performance criterion such as "302.1".
+type ACRRemark struct {
+	Base
+	ReportID  uuid.UUID `json:"report_id" gorm:"type:uuid;not null;uniqueIndex:idx_acr_remarks_report_criterion"`
+	Criterion string    `json:"criterion" gorm:"not null;uniqueIndex:idx_acr_remarks_report_criterion"`
+	Remarks   string    `json:"remarks" gorm:"type:text;not null"`
+
+	// Relationship
+	Report ComplianceReport `json:"-" gorm:"foreignKey:ReportID"`
+}
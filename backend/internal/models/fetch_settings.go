@@ -0,0 +1,277 @@
+package models
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+// encryptedPrefix marks a secret stored encrypted. Values without it were
+// stored before encryption and are read as they are.
+const encryptedPrefix = "enc:v1:"
+
+// ErrNoSecretKey is returned when fetch settings with secrets are stored
+// without a key to encrypt them
+var ErrNoSecretKey = errors.New("FETCH_SETTINGS_KEY is not set, cannot store fetch credentials")
+
+// secretKey returns the AES-256 key that encrypts fetch settings secrets,
+// derived from FETCH_SETTINGS_KEY. Changing the key makes stored secrets
+// unreadable, so they must be entered again.
+func secretKey() ([]byte, error) {
+	secret := os.Getenv("FETCH_SETTINGS_KEY")
+	if secret == "" {
+		return nil, ErrNoSecretKey
+	}
+	key := sha256.Sum256([]byte(secret))
+	return key[:], nil
+}
+
+func secretCipher() (cipher.AEAD, error) {
+	key, err := secretKey()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptSecret encrypts a secret with AES-GCM
+func encryptSecret(aead cipher.AEAD, plaintext string) (string, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %v", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptSecret reverses encryptSecret. Plaintext values are returned as
+// they are.
+func decryptSecret(aead cipher.AEAD, value string) (string, error) {
+	encoded, ok := strings.CutPrefix(value, encryptedPrefix)
+	if !ok {
+		return value, nil
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("malformed encrypted secret")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret, was the key changed? %v", err)
+	}
+	return string(plaintext), nil
+}
+
+// hasSecrets reports whether the settings hold any credentials
+func (s FetchSettings) hasSecrets() bool {
+	return len(s.Headers) > 0 || len(s.Cookies) > 0 || s.Password != "" || s.Token != ""
+}
+
+// mapSecrets applies fn to the header values, cookie values, password and
+// token of a copy of the settings
+func (s FetchSettings) mapSecrets(fn func(string) (string, error)) (FetchSettings, error) {
+	mapValues := func(values map[string]string) (map[string]string, error) {
+		if values == nil {
+			return nil, nil
+		}
+		mapped := make(map[string]string, len(values))
+		for name, value := range values {
+			v, err := fn(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", name, err)
+			}
+			mapped[name] = v
+		}
+		return mapped, nil
+	}
+
+	var err error
+	if s.Headers, err = mapValues(s.Headers); err != nil {
+		return s, fmt.Errorf("header %v", err)
+	}
+	if s.Cookies, err = mapValues(s.Cookies); err != nil {
+		return s, fmt.Errorf("cookie %v", err)
+	}
+	for _, secret := range []*string{&s.Password, &s.Token} {
+		if *secret == "" {
+			continue
+		}
+		if *secret, err = fn(*secret); err != nil {
+			return s, err
+		}
+	}
+	return s, nil
+}
+
+// Value stores the settings as JSON with the secrets encrypted. Secrets that
+// could not be decrypted when the settings were read are stored as they were,
+// unless they have been replaced.
+func (s FetchSettings) Value() (driver.Value, error) {
+	if s.hasSecrets() {
+		aead, err := secretCipher()
+		if err != nil {
+			return nil, err
+		}
+		s, err = s.mapSecrets(func(v string) (string, error) { return encryptSecret(aead, v) })
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt fetch settings: %v", err)
+		}
+	}
+	if s.sealed != nil {
+		s.restoreSealed()
+	}
+	s.SecretsUnavailable = false
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan reads settings stored by Value and decrypts their secrets. Secrets
+// that cannot be decrypted are left out and flagged with SecretsUnavailable,
+// so the project still loads.
+func (s *FetchSettings) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*s = FetchSettings{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported fetch settings type %T", value)
+	}
+
+	var stored FetchSettings
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return fmt.Errorf("failed to parse fetch settings: %v", err)
+	}
+	if stored.hasSecrets() {
+		stored.decryptSecrets()
+	}
+	*s = stored
+	return nil
+}
+
+// decryptSecrets decrypts the secrets of stored settings in place. Those
+// that cannot be decrypted are moved to sealed.
+func (s *FetchSettings) decryptSecrets() {
+	aead, keyErr := secretCipher()
+	var reason error
+	decrypt := func(value string) (string, bool) {
+		if !strings.HasPrefix(value, encryptedPrefix) {
+			return value, true
+		}
+		if keyErr != nil {
+			reason = keyErr
+			return "", false
+		}
+		plaintext, err := decryptSecret(aead, value)
+		if err != nil {
+			reason = err
+			return "", false
+		}
+		return plaintext, true
+	}
+
+	sealed := &FetchSettings{}
+	decryptValues := func(values map[string]string, sealedValues *map[string]string) {
+		for name, value := range values {
+			if plaintext, ok := decrypt(value); ok {
+				values[name] = plaintext
+				continue
+			}
+			if *sealedValues == nil {
+				*sealedValues = make(map[string]string)
+			}
+			(*sealedValues)[name] = value
+			delete(values, name)
+		}
+	}
+	decryptValues(s.Headers, &sealed.Headers)
+	decryptValues(s.Cookies, &sealed.Cookies)
+	for _, secret := range []struct{ value, sealed *string }{
+		{&s.Password, &sealed.Password},
+		{&s.Token, &sealed.Token},
+	} {
+		if *secret.value == "" {
+			continue
+		}
+		if plaintext, ok := decrypt(*secret.value); ok {
+			*secret.value = plaintext
+		} else {
+			*secret.sealed, *secret.value = *secret.value, ""
+		}
+	}
+
+	if reason != nil {
+		log.Printf("WARNING: fetch settings secrets are unavailable until they are entered again: %v", reason)
+		s.SecretsUnavailable = true
+		s.sealed = sealed
+	}
+}
+
+// restoreSealed puts back the secrets that could not be decrypted and have
+// not been replaced since
+func (s *FetchSettings) restoreSealed() {
+	restore := func(values map[string]string, sealedValues map[string]string) map[string]string {
+		if len(sealedValues) == 0 {
+			return values
+		}
+		restored := make(map[string]string, len(values)+len(sealedValues))
+		for name, value := range sealedValues {
+			restored[name] = value
+		}
+		for name, value := range values {
+			restored[name] = value
+		}
+		return restored
+	}
+	s.Headers = restore(s.Headers, s.sealed.Headers)
+	s.Cookies = restore(s.Cookies, s.sealed.Cookies)
+	if s.Password == "" {
+		s.Password = s.sealed.Password
+	}
+	if s.Token == "" {
+		s.Token = s.sealed.Token
+	}
+}
+
+// String describes the settings without their secrets, so logging a project
+// never writes credentials
+func (s FetchSettings) String() string {
+	names := func(values map[string]string) []string {
+		keys := make([]string, 0, len(values))
+		for name := range values {
+			keys = append(keys, name)
+		}
+		sort.Strings(keys)
+		return keys
+	}
+	return fmt.Sprintf("{TimeoutSeconds:%d MaxRedirects:%d MaxBodyMB:%d Headers:%v Cookies:%v AuthType:%s Username:%s Password:%t Token:%t SecretsUnavailable:%t}",
+		s.TimeoutSeconds, s.MaxRedirects, s.MaxBodyMB, names(s.Headers), names(s.Cookies),
+		s.AuthType, s.Username, s.Password != "", s.Token != "", s.SecretsUnavailable)
+}
+
+// GoString redacts the secrets for %#v like String does for %v
+func (s FetchSettings) GoString() string {
+	return "models.FetchSettings" + s.String()
+}
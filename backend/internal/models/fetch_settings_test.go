@@ -0,0 +1,141 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFetchSettingsEncryptedAtRest(t *testing.T) {
+	t.Setenv("FETCH_SETTINGS_KEY", "test key")
+	settings := FetchSettings{
+		TimeoutSeconds: 10,
+		Headers:        map[string]string{"X-Staging": "header-secret"},
+		Cookies:        map[string]string{"session": "cookie-secret"},
+		AuthType:       "basic",
+		Username:       "staging",
+		Password:       "password-secret",
+	}
+
+	value, err := settings.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := value.(string)
+	for _, secret := range []string{"header-secret", "cookie-secret", "password-secret"} {
+		if strings.Contains(stored, secret) {
+			t.Errorf("stored settings contain %q: %s", secret, stored)
+		}
+	}
+
+	var loaded FetchSettings
+	if err := loaded.Scan([]byte(stored)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, settings) {
+		t.Errorf("loaded %#v, want %#v", loaded, settings)
+	}
+}
+
+func TestFetchSettingsUnavailableSecrets(t *testing.T) {
+	t.Setenv("FETCH_SETTINGS_KEY", "test key")
+	settings := FetchSettings{
+		TimeoutSeconds: 10,
+		Headers:        map[string]string{"X-Staging": "header-secret"},
+		AuthType:       "bearer",
+		Token:          "token-secret",
+	}
+	value, err := settings.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := value.(string)
+
+	for name, key := range map[string]string{"rotated key": "other key", "unset key": ""} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("FETCH_SETTINGS_KEY", key)
+			t.Setenv("JWT_SECRET", "test key")
+			var loaded FetchSettings
+			if err := loaded.Scan(stored); err != nil {
+				t.Fatalf("scan failed: %v", err)
+			}
+			if !loaded.SecretsUnavailable {
+				t.Error("secrets are not flagged as unavailable")
+			}
+			if len(loaded.Headers) != 0 || loaded.Token != "" {
+				t.Errorf("undecryptable secrets were loaded: %v", loaded)
+			}
+			if loaded.TimeoutSeconds != 10 || loaded.AuthType != "bearer" {
+				t.Errorf("settings without secrets were lost: %v", loaded)
+			}
+
+			// Saving the project again keeps the stored secrets
+			resaved, err := loaded.Value()
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("FETCH_SETTINGS_KEY", "test key")
+			var restored FetchSettings
+			if err := restored.Scan(resaved); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(restored, settings) {
+				t.Errorf("restored %#v, want %#v", restored, settings)
+			}
+		})
+	}
+}
+
+func TestFetchSettingsReplaceUnavailableSecrets(t *testing.T) {
+	t.Setenv("FETCH_SETTINGS_KEY", "old key")
+	value, err := FetchSettings{AuthType: "bearer", Token: "old-token"}.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("FETCH_SETTINGS_KEY", "new key")
+	var loaded FetchSettings
+	if err := loaded.Scan(value); err != nil {
+		t.Fatal(err)
+	}
+	loaded.Token = "new-token"
+	resaved, err := loaded.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored FetchSettings
+	if err := restored.Scan(resaved); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Token != "new-token" || restored.SecretsUnavailable {
+		t.Errorf("restored %#v, want the new token", restored)
+	}
+}
+
+func TestFetchSettingsPlaintextRows(t *testing.T) {
+	t.Setenv("FETCH_SETTINGS_KEY", "test key")
+	var loaded FetchSettings
+	if err := loaded.Scan(`{"auth_type":"bearer","token":"legacy"}`); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Token != "legacy" {
+		t.Errorf("token = %q, want legacy", loaded.Token)
+	}
+}
+
+func TestFetchSettingsRedactedInLogs(t *testing.T) {
+	project := Project{FetchSettings: FetchSettings{
+		Headers:  map[string]string{"X-Staging": "header-secret"},
+		Password: "password-secret",
+		Token:    "token-secret",
+	}}
+	for _, format := range []string{"%v", "%+v", "%#v"} {
+		out := fmt.Sprintf(format, project)
+		for _, secret := range []string{"header-secret", "password-secret", "token-secret"} {
+			if strings.Contains(out, secret) {
+				t.Errorf("%s of a project contains %q", format, secret)
+			}
+		}
+	}
+}
@@ -16,32 +16,71 @@ type Project struct {
 	LastScan    time.Time `json:"last_scan"`
 	Score       float64   `json:"score"`
 	Status      string    `json:"status" gorm:"type:varchar(20);default:'active'"` // active, archived
+	// Standards profile compliance reports are generated against by default
+	DefaultProfile string `json:"default_profile" gorm:"type:varchar(40);not null;default:'wcag22-aa'"`
+	// How scans and reports of the project are scored: impact_weighted, pass_ratio or criteria
+	ScoringMethod string `json:"scoring_method" gorm:"type:varchar(20);not null;default:'impact_weighted'"`
+	// How the project's site is fetched. Holds credentials, so it is only
+	// exposed redacted through the fetch settings endpoints and its secrets
+	// are encrypted at rest.
+	FetchSettings FetchSettings `json:"-" gorm:"type:jsonb"`
+}
+
+// FetchSettings control how a project's site is fetched, e.g. to scan a
+// staging site behind a login. Headers, cookies and auth are only sent to
+// the host of the project URL. Zero values use the scanner's defaults.
+// Header values, cookie values, the password and the token are encrypted
+// when stored, and String leaves them out.
+type FetchSettings struct {
+	TimeoutSeconds int               `json:"timeout_seconds,omitempty"`
+	MaxRedirects   int               `json:"max_redirects,omitempty"`
+	MaxBodyMB      int               `json:"max_body_mb,omitempty"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	Cookies        map[string]string `json:"cookies,omitempty"`
+	AuthType       string            `json:"auth_type,omitempty"` // basic or bearer
+	Username       string            `json:"username,omitempty"`
+	Password       string            `json:"password,omitempty"`
+	Token          string            `json:"token,omitempty"`
+
+	// SecretsUnavailable reports that stored secrets could not be decrypted,
+	// because FETCH_SETTINGS_KEY is unset or was changed. They are left out
+	// of the settings but kept in the database until they are replaced.
+	SecretsUnavailable bool `json:"secrets_unavailable,omitempty"`
+
+	// sealed holds the stored form of the secrets that could not be decrypted
+	sealed *FetchSettings
 }
 
 type ProjectResponse struct {
-	ID          uuid.UUID `json:"id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Title       string    `json:"title"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	URL         string    `json:"url"`
-	UserID      uuid.UUID `json:"user_id"`
-	LastScan    time.Time `json:"last_scan"`
-	Score       float64   `json:"score"`
-	Status      string    `json:"status"`
+	ID             uuid.UUID `json:"id"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	Title          string    `json:"title"`
+	Name           string    `json:"name"`
+	Description    string    `json:"description"`
+	URL            string    `json:"url"`
+	UserID         uuid.UUID `json:"user_id"`
+	LastScan       time.Time `json:"last_scan"`
+	Score          float64   `json:"score"`
+	Status         string    `json:"status"`
+	DefaultProfile string    `json:"default_profile"`
+	ScoringMethod  string    `json:"scoring_method"`
 }
 
 type Scan struct {
 	Base
 	ProjectID  uuid.UUID            `json:"project_id" gorm:"type:uuid;not null"`
 	ScanType   string               `json:"scan_type" gorm:"type:varchar(20);not null"`
+	PageURL    string               `json:"page_url,omitempty"` // Page the scan covers when it is not the project URL
 	Status     string               `json:"status" gorm:"type:varchar(20);default:'pending'"`
 	Score      float64              `json:"score,omitempty"`
 	ResultJSON *string              `json:"result_json,omitempty" gorm:"type:jsonb"`
+	A11yTree   *string              `json:"-" gorm:"column:a11y_tree_json;type:jsonb"` // Accessibility tree of the scanned page
 	Summary    string               `json:"summary,omitempty"`
+	ParentID   *uuid.UUID           `json:"parent_id,omitempty" gorm:"type:uuid;index"` // Site-level scan this page scan belongs to
 	Project    Project              `json:"-" gorm:"foreignKey:ProjectID"`
 	Issues     []AccessibilityIssue `json:"issues,omitempty" gorm:"foreignKey:ScanID"`
+	Children   []Scan               `json:"children,omitempty" gorm:"foreignKey:ParentID"`
 }
 
 type AccessibilityIssue struct {
@@ -50,6 +89,10 @@ type AccessibilityIssue struct {
 	Severity         string    `json:"severity" gorm:"type:varchar(20);not null"`
 	Description      string    `json:"description" gorm:"not null"`
 	HTMLSnippet      string    `json:"html_snippet"`
+	Selector         string    `json:"selector"`
+	XPath            string    `json:"xpath"`
+	Line             int       `json:"line"`
+	Column           int       `json:"column"`
 	FixSuggestion    string    `json:"fix_suggestion"`
 	SimulatorEffects string    `json:"simulator_effects" gorm:"type:jsonb"`
 	Scan             Scan      `json:"-" gorm:"foreignKey:ScanID"`
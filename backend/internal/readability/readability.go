@@ -0,0 +1,255 @@
+// Package readability computes reading level metrics of plain text. Reading
+// ease uses the Flesch formula adapted to the text's language where one is
+// known; LIX, which only counts words and long words, covers the rest.
+package readability
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Metrics are the reading level metrics of a text. Formulas that do not
+// apply to the text's language are left out.
+type Metrics struct {
+	Words            int     `json:"words"`
+	Sentences        int     `json:"sentences"`
+	Syllables        int     `json:"syllables"`
+	LongWords        int     `json:"longWords"` // Words of more than six letters
+	WordsPerSentence float64 `json:"wordsPerSentence"`
+	SyllablesPerWord float64 `json:"syllablesPerWord"`
+	// ReadingEase is the Flesch reading ease, or its adaptation to the
+	// language named by Formula. Higher is easier, most text scores 0-100.
+	ReadingEase *float64 `json:"readingEase,omitempty"`
+	Formula     string   `json:"formula,omitempty"`
+	// Grade is the Flesch-Kincaid US school grade, for English only
+	Grade *float64 `json:"grade,omitempty"`
+	// LIX is the Läsbarhetsindex. Below 25 is very easy, 55 and up very
+	// difficult.
+	LIX   *float64 `json:"lix,omitempty"`
+	Level string   `json:"level"` // Difficulty band, e.g. "fairly difficult"
+}
+
+// ease is a Flesch style reading ease formula of words per sentence and
+// syllables per word
+type ease struct {
+	name    string
+	formula func(wps, spw float64) float64
+}
+
+// Reading ease formulas by primary language subtag
+var easeFormulas = map[string]ease{
+	"en": {"Flesch", func(wps, spw float64) float64 { return 206.835 - 1.015*wps - 84.6*spw }},
+	"de": {"Amstad", func(wps, spw float64) float64 { return 180 - wps - 58.5*spw }},
+	"fr": {"Kandel-Moles", func(wps, spw float64) float64 { return 207 - 1.015*wps - 73.6*spw }},
+	"es": {"Fernández Huerta", func(wps, spw float64) float64 { return 206.84 - 1.02*wps - 60*spw }},
+	"nl": {"Douma", func(wps, spw float64) float64 { return 206.835 - 0.93*wps - 77*spw }},
+}
+
+// Languages written without spaces between words, where word based metrics
+// do not apply
+var unspaced = map[string]bool{"zh": true, "ja": true, "ko": true, "th": true, "lo": true, "km": true, "my": true}
+
+// Primary returns the primary language subtag of a language tag
+func Primary(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
+
+// Analyze computes the metrics of a text in the given language. Text
+// without a language is treated as English; text in a language written
+// without spaces between words gets no metrics.
+func Analyze(text, lang string) Metrics {
+	primary := Primary(lang)
+	if primary == "" {
+		primary = "en"
+	}
+	var m Metrics
+	if unspaced[primary] {
+		return m
+	}
+
+	words := Words(text)
+	m.Words = len(words)
+	m.Sentences = Sentences(text)
+	if m.Words == 0 {
+		return m
+	}
+	for _, word := range words {
+		m.Syllables += Syllables(word, primary)
+		if len([]rune(word)) > 6 {
+			m.LongWords++
+		}
+	}
+
+	wps := float64(m.Words) / float64(m.Sentences)
+	spw := float64(m.Syllables) / float64(m.Words)
+	m.WordsPerSentence = round(wps)
+	m.SyllablesPerWord = round(spw)
+	lix := round(wps + float64(m.LongWords)*100/float64(m.Words))
+	m.LIX = &lix
+	if e, ok := easeFormulas[primary]; ok {
+		value := round(e.formula(wps, spw))
+		m.ReadingEase = &value
+		m.Formula = e.name
+	}
+	if primary == "en" {
+		grade := round(math.Max(0, 0.39*wps+11.8*spw-15.59))
+		m.Grade = &grade
+	}
+	m.Level = m.level()
+	return m
+}
+
+// Difficult reports whether a text needs more than lower secondary education
+// to read, the threshold of WCAG 3.1.5: above grade 9, a reading ease below
+// 50, or a LIX above 50
+func (m Metrics) Difficult() bool {
+	switch {
+	case m.Grade != nil:
+		return *m.Grade > 9
+	case m.ReadingEase != nil:
+		return *m.ReadingEase < 50
+	case m.LIX != nil:
+		return *m.LIX > 50
+	}
+	return false
+}
+
+// level returns the difficulty band of the reading ease, or of LIX when the
+// language has no reading ease formula
+func (m Metrics) level() string {
+	if m.ReadingEase != nil {
+		switch e := *m.ReadingEase; {
+		case e >= 90:
+			return "very easy"
+		case e >= 80:
+			return "easy"
+		case e >= 70:
+			return "fairly easy"
+		case e >= 60:
+			return "standard"
+		case e >= 50:
+			return "fairly difficult"
+		case e >= 30:
+			return "difficult"
+		}
+		return "very difficult"
+	}
+	if m.LIX != nil {
+		switch l := *m.LIX; {
+		case l < 25:
+			return "very easy"
+		case l < 35:
+			return "easy"
+		case l < 45:
+			return "standard"
+		case l < 55:
+			return "difficult"
+		}
+		return "very difficult"
+	}
+	return ""
+}
+
+// Words returns the words of a text. Apostrophes and hyphens inside a word
+// belong to it; tokens without letters, such as numbers, are not words.
+func Words(text string) []string {
+	tokens := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '’' && r != '-'
+	})
+	words := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		token = strings.Trim(token, "'’-")
+		if strings.IndexFunc(token, unicode.IsLetter) >= 0 {
+			words = append(words, token)
+		}
+	}
+	return words
+}
+
+// Sentences counts the sentences of a text: runs of terminal punctuation
+// followed by a space or the end of the text. Text after the last terminator
+// counts as a sentence too.
+func Sentences(text string) int {
+	runes := []rune(strings.TrimSpace(text))
+	count := 0
+	pending := false // Words since the last terminator
+	for i, r := range runes {
+		switch {
+		case isTerminator(r):
+			end := i+1 == len(runes) || unicode.IsSpace(runes[i+1]) || isClosing(runes[i+1])
+			if end && pending {
+				count++
+				pending = false
+			}
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			pending = true
+		}
+	}
+	if pending || count == 0 {
+		count++
+	}
+	return count
+}
+
+func isTerminator(r rune) bool {
+	return r == '.' || r == '!' || r == '?' || r == '…' || r == '。' || r == '！' || r == '？'
+}
+
+func isClosing(r rune) bool {
+	return r == '"' || r == '\'' || r == ')' || r == '”' || r == '’' || r == '»' || isTerminator(r)
+}
+
+// Syllables estimates the syllables of a word by counting groups of vowels,
+// with corrections for silent endings in English and French
+func Syllables(word, lang string) int {
+	runes := []rune(strings.ToLower(word))
+	count := 0
+	previous := false
+	for _, r := range runes {
+		vowel := strings.ContainsRune("aeiouyàáâãäåæèéêëìíîïòóôõöøùúûüýÿœ", r)
+		if vowel && !previous {
+			count++
+		}
+		previous = vowel
+	}
+
+	w := string(runes)
+	switch Primary(lang) {
+	case "en":
+		switch {
+		case strings.HasSuffix(w, "le") && len(runes) > 2 && !strings.ContainsRune("aeiouy", runes[len(runes)-3]):
+			// "table" keeps its final syllable
+		case hasSuffix(w, "ses", "xes", "zes", "ces", "ges", "ches", "shes", "ted", "ded"):
+			// "boxes" and "wanted" too
+		case hasSuffix(w, "e", "es", "ed"):
+			count--
+		}
+	case "fr":
+		if hasSuffix(w, "e", "es", "ent") {
+			count--
+		}
+	}
+	if count < 1 {
+		count = 1
+	}
+	return count
+}
+
+func hasSuffix(s string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// round rounds to one decimal
+func round(v float64) float64 {
+	return math.Round(v*10) / 10
+}
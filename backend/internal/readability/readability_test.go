@@ -0,0 +1,46 @@
+package readability
+
+import (
+	"fmt"
+	"testing"
+)
+
+// optional formats a metric that may be left out
+func optional(v *float64) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f", *v)
+}
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		text, lang                  string
+		words, sentences, syllables int
+		ease, grade, lix            string
+		level                       string
+	}{
+		{"The cat sat on the mat. It was happy.", "en", 9, 2, 10, "108.3", "0.0", "4.5", "very easy"},
+		{"Accessibility requirements demand considerable organizational commitment. Developers implement semantic structures.", "en",
+			10, 2, 38, "-119.7", "31.2", "95.0", "very difficult"},
+		{"Hello world", "", 2, 1, 3, "77.9", "2.9", "2.0", "fairly easy"},
+		{"Der Hund läuft schnell nach Hause.", "de", 6, 1, 7, "105.8", "-", "22.7", "very easy"},
+		{"Le chat mange une pomme rouge. Elle est belle.", "fr-CA", 9, 2, 9, "128.8", "-", "4.5", "very easy"},
+		{"Kissa istuu matolla.", "fi", 3, 1, 7, "-", "-", "36.3", "standard"},
+		{"これはペンです。", "ja", 0, 0, 0, "-", "-", "-", ""},
+	}
+	for _, tt := range tests {
+		m := Analyze(tt.text, tt.lang)
+		if m.Words != tt.words || m.Sentences != tt.sentences || m.Syllables != tt.syllables {
+			t.Errorf("Analyze(%q) counts %d words, %d sentences, %d syllables, want %d, %d, %d",
+				tt.text, m.Words, m.Sentences, m.Syllables, tt.words, tt.sentences, tt.syllables)
+		}
+		if ease, grade, lix := optional(m.ReadingEase), optional(m.Grade), optional(m.LIX); ease != tt.ease || grade != tt.grade || lix != tt.lix {
+			t.Errorf("Analyze(%q) reading ease %s, grade %s, LIX %s, want %s, %s, %s",
+				tt.text, ease, grade, lix, tt.ease, tt.grade, tt.lix)
+		}
+		if m.Level != tt.level {
+			t.Errorf("Analyze(%q) level = %q, want %q", tt.text, m.Level, tt.level)
+		}
+	}
+}
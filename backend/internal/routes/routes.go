@@ -15,6 +15,7 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB) {
 	scanHandler := handlers.NewScanHandler()
 	complianceHandler := handlers.NewComplianceHandler(db, services.NewScanner())
 	analyticsHandler := handlers.NewAnalyticsHandler()
+	rulesHandler := handlers.NewRulesHandler(services.DefaultRules())
 
 	// Public routes
 	public := r.Group("/api/public")
@@ -42,6 +43,11 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB) {
 		// Scan routes
 		api.GET("/scans", handlers.ListScans)
 
+		// Rule catalog and standards profiles
+		api.GET("/rules", rulesHandler.ListRules)
+		api.GET("/profiles", rulesHandler.ListProfiles)
+		api.GET("/profiles/:profileId", rulesHandler.GetProfile)
+
 		// Activity Log routes
 		api.GET("/activity", handlers.ListActivityLogs)
 
@@ -53,7 +59,12 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB) {
 			projects.GET("/:projectId", projectHandler.GetProject)
 			projects.PUT("/:projectId", projectHandler.UpdateProject)
 			projects.DELETE("/:projectId", projectHandler.DeleteProject)
+			projects.GET("/:projectId/fetch-settings", projectHandler.GetFetchSettings)
+			projects.PUT("/:projectId/fetch-settings", projectHandler.UpdateFetchSettings)
 			projects.POST("/:projectId/scan", projectHandler.RunScan)
+			projects.POST("/:projectId/scan/upload", projectHandler.ScanUpload)
+			projects.POST("/:projectId/crawl", projectHandler.RunCrawl)
+			projects.GET("/:projectId/scans/:scanId/a11y-tree", projectHandler.GetScanA11yTree)
 
 			// Compliance report routes for projects
 			projects.POST("/:projectId/compliance", complianceHandler.GenerateReport)
@@ -64,6 +75,16 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB) {
 		compliance := api.Group("/compliance")
 		{
 			compliance.GET("/:reportId", complianceHandler.GetReport)
+			compliance.GET("/:reportId/acr", complianceHandler.ExportACR)
+			compliance.PUT("/:reportId/acr/remarks", complianceHandler.UpdateACRRemarks)
+
+			// Manual audit checklist
+			compliance.GET("/:reportId/checklist", complianceHandler.GetChecklist)
+			compliance.PUT("/:reportId/checklist/:criterion", complianceHandler.UpdateCheck)
+			compliance.GET("/:reportId/checklist/:criterion/history", complianceHandler.GetCheckHistory)
+			compliance.POST("/:reportId/checklist/:criterion/evidence", complianceHandler.AddEvidence)
+			compliance.GET("/:reportId/checklist/:criterion/evidence/:evidenceId", complianceHandler.GetEvidence)
+			compliance.DELETE("/:reportId/checklist/:criterion/evidence/:evidenceId", complianceHandler.DeleteEvidence)
 		}
 	}
 }
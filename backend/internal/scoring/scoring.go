@@ -0,0 +1,238 @@
+// Package scoring turns the outcome of a scan or compliance report into a
+// 0-100 score. Every code path that reports a score goes through Compute, so
+// a project's scan, crawl and report scores always agree on the method.
+package scoring
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"tokubetsu/internal/models"
+	"tokubetsu/internal/wcag"
+)
+
+// Scoring methods
+const (
+	// ImpactWeighted starts at 100 and deducts for every failed rule by its
+	// impact. Passes do not raise the score, so pages with many elements are
+	// not rewarded for their size.
+	ImpactWeighted = "impact_weighted"
+	// PassRatio is the share of checked elements that passed
+	PassRatio = "pass_ratio"
+	// Criteria is the share of decided success criteria that passed
+	Criteria = "criteria"
+)
+
+// DefaultMethod is used when a project has not chosen a method
+const DefaultMethod = ImpactWeighted
+
+// Impact weights deducted by ImpactWeighted for a failed rule
+var impactWeights = map[string]float64{
+	"critical": 10,
+	"serious":  6,
+	"moderate": 3,
+	"minor":    1,
+}
+
+// Check is one element a rule passed or failed on. It counts once overall
+// and once for each level and principle of the criteria the rule tests.
+type Check struct {
+	Rule       string
+	Passed     bool
+	Impact     string   // Impact of a failed check
+	Levels     []string // Distinct WCAG levels of the rule's criteria
+	Principles []string
+}
+
+// Criterion is the outcome of one requirement of a standards profile
+type Criterion struct {
+	Status    string // A models criterion status
+	Manual    bool   // Decided in a manual audit
+	Level     string // Empty for additions of the standard
+	Principle string
+}
+
+// Input is what a scoring method scores
+type Input struct {
+	Checks   []Check
+	Criteria []Criterion
+}
+
+// Score is the result of a scoring method. Levels and principles without
+// anything to score are left out of the maps.
+type Score struct {
+	Method     string             `json:"method"`
+	Overall    float64            `json:"overall"`
+	Levels     map[string]float64 `json:"levels"`
+	Principles map[string]float64 `json:"principles"`
+}
+
+// Methods returns the scoring methods
+func Methods() []string {
+	return []string{ImpactWeighted, PassRatio, Criteria}
+}
+
+// Valid reports whether method is a scoring method
+func Valid(method string) bool {
+	_, ok := methods[method]
+	return ok
+}
+
+// method scores an input, or reports false when it has nothing to score
+type method func(in Input) (float64, bool)
+
+var methods = map[string]method{
+	ImpactWeighted: impactWeighted,
+	PassRatio:      passRatio,
+	Criteria:       criteria,
+}
+
+// Compute scores an input with the given method. The overall score of an
+// input with nothing to score is 100, since nothing failed.
+func Compute(name string, in Input) (Score, error) {
+	score, ok := methods[name]
+	if !ok {
+		return Score{}, fmt.Errorf("unknown scoring method %q", name)
+	}
+
+	result := Score{
+		Method:     name,
+		Overall:    100,
+		Levels:     make(map[string]float64),
+		Principles: make(map[string]float64),
+	}
+	if overall, ok := score(in); ok {
+		result.Overall = overall
+	}
+	for _, level := range []string{wcag.LevelA, wcag.LevelAA, wcag.LevelAAA} {
+		if s, ok := score(in.filter(level, "")); ok {
+			result.Levels[level] = s
+		}
+	}
+	for _, principle := range []string{wcag.PrinciplePerceivable, wcag.PrincipleOperable, wcag.PrincipleUnderstandable, wcag.PrincipleRobust} {
+		if s, ok := score(in.filter("", principle)); ok {
+			result.Principles[principle] = s
+		}
+	}
+	return result, nil
+}
+
+// filter returns the checks and criteria of the input at a level or of a
+// principle. An empty level or principle matches any.
+func (in Input) filter(level, principle string) Input {
+	matches := func(values []string, want string) bool {
+		return want == "" || slices.Contains(values, want)
+	}
+	var out Input
+	for _, check := range in.Checks {
+		if matches(check.Levels, level) && matches(check.Principles, principle) {
+			out.Checks = append(out.Checks, check)
+		}
+	}
+	for _, criterion := range in.Criteria {
+		if (level == "" || criterion.Level == level) && (principle == "" || criterion.Principle == principle) {
+			out.Criteria = append(out.Criteria, criterion)
+		}
+	}
+	return out
+}
+
+// impactWeighted deducts the impact weight of every failed rule from 100.
+// Each further element a rule fails on deducts a quarter of its weight, up to
+// twice the weight in total. A criterion failed in a manual audit deducts the
+// weight of a serious failure. For example a critical failure on one image
+// and a minor one on three links score 100 - 10 - (1 + 0.5) = 88.5.
+func impactWeighted(in Input) (float64, bool) {
+	failures := make(map[string]int)
+	impacts := make(map[string]string)
+	var rules []string
+	for _, check := range in.Checks {
+		if check.Passed {
+			continue
+		}
+		if failures[check.Rule] == 0 {
+			rules = append(rules, check.Rule)
+			impacts[check.Rule] = check.Impact
+		}
+		failures[check.Rule]++
+	}
+
+	deduction := 0.0
+	for _, rule := range rules {
+		weight, ok := impactWeights[impacts[rule]]
+		if !ok {
+			weight = impactWeights["moderate"]
+		}
+		deduction += math.Min(weight+weight/4*float64(failures[rule]-1), 2*weight)
+	}
+	decided := false
+	for _, criterion := range in.Criteria {
+		if criterion.Manual && criterion.Status == models.CriterionFailed {
+			deduction += impactWeights["serious"]
+		}
+		if automatedOutcome(criterion.Status) {
+			decided = true
+		}
+	}
+
+	if len(in.Checks) == 0 && !decided {
+		return 0, false
+	}
+	return math.Max(0, 100-deduction), true
+}
+
+// passRatio is the share of checks that passed. A criterion decided in a
+// manual audit counts as one check. For example 3 passes and 1 failure score
+// 75.
+func passRatio(in Input) (float64, bool) {
+	passed, total := 0, 0
+	for _, check := range in.Checks {
+		total++
+		if check.Passed {
+			passed++
+		}
+	}
+	for _, criterion := range in.Criteria {
+		if !criterion.Manual || (criterion.Status != models.CriterionPassed && criterion.Status != models.CriterionFailed) {
+			continue
+		}
+		total++
+		if criterion.Status == models.CriterionPassed {
+			passed++
+		}
+	}
+
+	if total == 0 {
+		return 0, false
+	}
+	return float64(passed) / float64(total) * 100, true
+}
+
+// criteria is the share of decided criteria that passed. Criteria whose
+// automated checks passed count as passed while they await review. Criteria
+// that do not apply or are still undecided do not count. For example 6 passed
+// and 2 failed criteria score 75.
+func criteria(in Input) (float64, bool) {
+	passed, decided := 0, 0
+	for _, criterion := range in.Criteria {
+		switch criterion.Status {
+		case models.CriterionPassed, models.CriterionNeedsReview:
+			passed++
+			decided++
+		case models.CriterionFailed:
+			decided++
+		}
+	}
+
+	if decided == 0 {
+		return 0, false
+	}
+	return float64(passed) / float64(decided) * 100, true
+}
+
+// automatedOutcome reports whether a criterion status is a pass or failure,
+// including passes that await review
+func automatedOutcome(status string) bool {
+	return status == models.CriterionPassed || status == models.CriterionFailed || status == models.CriterionNeedsReview
+}
@@ -0,0 +1,139 @@
+package scoring
+
+import (
+	"math"
+	"testing"
+
+	"tokubetsu/internal/models"
+	"tokubetsu/internal/wcag"
+)
+
+// fixture has automated checks at levels A and AA and two criteria decided
+// in a manual audit. Level AAA and Robust have nothing to score.
+var fixture = Input{
+	Checks: []Check{
+		{Rule: "image-alt", Passed: false, Impact: "critical", Levels: []string{wcag.LevelA}, Principles: []string{wcag.PrinciplePerceivable}},
+		{Rule: "image-alt", Passed: true, Levels: []string{wcag.LevelA}, Principles: []string{wcag.PrinciplePerceivable}},
+		{Rule: "link-name", Passed: false, Impact: "minor", Levels: []string{wcag.LevelA}, Principles: []string{wcag.PrincipleOperable}},
+		{Rule: "link-name", Passed: false, Impact: "minor", Levels: []string{wcag.LevelA}, Principles: []string{wcag.PrincipleOperable}},
+		{Rule: "link-name", Passed: false, Impact: "minor", Levels: []string{wcag.LevelA}, Principles: []string{wcag.PrincipleOperable}},
+		{Rule: "color-contrast", Passed: true, Levels: []string{wcag.LevelAA}, Principles: []string{wcag.PrinciplePerceivable}},
+		{Rule: "color-contrast", Passed: true, Levels: []string{wcag.LevelAA}, Principles: []string{wcag.PrinciplePerceivable}},
+	},
+	Criteria: []Criterion{
+		{Status: models.CriterionFailed, Level: wcag.LevelA, Principle: wcag.PrinciplePerceivable},
+		{Status: models.CriterionFailed, Level: wcag.LevelA, Principle: wcag.PrincipleOperable},
+		{Status: models.CriterionPassed, Level: wcag.LevelAA, Principle: wcag.PrinciplePerceivable},
+		{Status: models.CriterionFailed, Manual: true, Level: wcag.LevelAA, Principle: wcag.PrincipleUnderstandable},
+		{Status: models.CriterionPassed, Manual: true, Level: wcag.LevelA, Principle: wcag.PrincipleUnderstandable},
+		{Status: models.CriterionNotApplicable, Level: wcag.LevelA, Principle: wcag.PrincipleRobust},
+		{Status: models.CriterionNeedsManual, Level: wcag.LevelAAA, Principle: wcag.PrincipleRobust},
+	},
+}
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		method     string
+		overall    float64
+		levels     map[string]float64
+		principles map[string]float64
+	}{
+		{
+			// critical 10, minor on three links 1 + 0.5, serious manual failure 6
+			method:  ImpactWeighted,
+			overall: 82.5,
+			levels:  map[string]float64{wcag.LevelA: 88.5, wcag.LevelAA: 94},
+			principles: map[string]float64{
+				wcag.PrinciplePerceivable: 90, wcag.PrincipleOperable: 98.5, wcag.PrincipleUnderstandable: 94,
+			},
+		},
+		{
+			// 3 of 7 checks and 1 of 2 manual criteria passed
+			method:  PassRatio,
+			overall: 44.44,
+			levels:  map[string]float64{wcag.LevelA: 33.33, wcag.LevelAA: 66.67},
+			principles: map[string]float64{
+				wcag.PrinciplePerceivable: 75, wcag.PrincipleOperable: 0, wcag.PrincipleUnderstandable: 50,
+			},
+		},
+		{
+			// 2 of 5 decided criteria passed
+			method:  Criteria,
+			overall: 40,
+			levels:  map[string]float64{wcag.LevelA: 33.33, wcag.LevelAA: 50},
+			principles: map[string]float64{
+				wcag.PrinciplePerceivable: 50, wcag.PrincipleOperable: 0, wcag.PrincipleUnderstandable: 50,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			score, err := Compute(tt.method, fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !near(score.Overall, tt.overall) {
+				t.Errorf("overall = %.2f, want %.2f", score.Overall, tt.overall)
+			}
+			compareScores(t, "level", score.Levels, tt.levels)
+			compareScores(t, "principle", score.Principles, tt.principles)
+		})
+	}
+}
+
+func TestComputeNothingToScore(t *testing.T) {
+	for _, method := range Methods() {
+		score, err := Compute(method, Input{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if score.Overall != 100 || len(score.Levels) != 0 || len(score.Principles) != 0 {
+			t.Errorf("%s: empty input scored %+v, want overall 100 and no levels or principles", method, score)
+		}
+	}
+}
+
+func TestComputeUnknownMethod(t *testing.T) {
+	if _, err := Compute("average", fixture); err == nil {
+		t.Error("expected an error for an unknown method")
+	}
+}
+
+func TestComputeCheckOfSeveralCriteria(t *testing.T) {
+	// A link without a name fails 2.4.4 (Operable) and 4.1.2 (Robust)
+	in := Input{Checks: []Check{
+		{Rule: "link-name", Passed: false, Impact: "serious", Levels: []string{wcag.LevelA}, Principles: []string{wcag.PrincipleOperable, wcag.PrincipleRobust}},
+		{Rule: "image-alt", Passed: true, Levels: []string{wcag.LevelA}, Principles: []string{wcag.PrinciplePerceivable}},
+	}}
+	score, err := Compute(PassRatio, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !near(score.Overall, 50) {
+		t.Errorf("overall = %.2f, want 50 with the failure counted once", score.Overall)
+	}
+	compareScores(t, "level", score.Levels, map[string]float64{wcag.LevelA: 50})
+	compareScores(t, "principle", score.Principles, map[string]float64{
+		wcag.PrinciplePerceivable: 100, wcag.PrincipleOperable: 0, wcag.PrincipleRobust: 0,
+	})
+}
+
+func compareScores(t *testing.T, kind string, got, want map[string]float64) {
+	t.Helper()
+	for key, w := range want {
+		if g, ok := got[key]; !ok {
+			t.Errorf("%s %s has nothing to score, want %.2f", kind, key, w)
+		} else if !near(g, w) {
+			t.Errorf("%s %s = %.2f, want %.2f", kind, key, g, w)
+		}
+	}
+	for key, g := range got {
+		if _, ok := want[key]; !ok {
+			t.Errorf("%s %s = %.2f, want nothing to score", kind, key, g)
+		}
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 0.005
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Limits applied when scanning uploaded archives
+const (
+	MaxArchivePages     = 500
+	MaxArchiveEntrySize = 10 << 20 // 10 MiB uncompressed per page
+)
+
+// PageResult is the scan result of a single page of a multi-page scan
+type PageResult struct {
+	Path   string      `json:"path"`
+	URL    string      `json:"url"`
+	Result *ScanResult `json:"result,omitempty"`
+	Score  float64     `json:"score"` // Score of Result, filled in by CrawlSite
+	Error  string      `json:"error,omitempty"`
+}
+
+// ScanArchive scans every HTML file in a zip archive of a static site.
+// Each page is resolved against baseURL, which may be empty. A page that
+// fails to scan is reported with its error instead of failing the archive.
+func (s *Scanner) ScanArchive(r io.ReaderAt, size int64, baseURL string) ([]PageResult, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %v", err)
+	}
+
+	pages := make([]PageResult, 0)
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !isHTMLFile(file.Name) {
+			continue
+		}
+		if len(pages) >= MaxArchivePages {
+			return nil, fmt.Errorf("archive contains more than %d pages", MaxArchivePages)
+		}
+
+		page := PageResult{
+			Path: file.Name,
+			URL:  resolvePagePath(baseURL, file.Name),
+		}
+		if file.UncompressedSize64 > MaxArchiveEntrySize {
+			page.Error = fmt.Sprintf("page exceeds %d bytes", MaxArchiveEntrySize)
+			pages = append(pages, page)
+			continue
+		}
+
+		page.Result, err = s.scanArchiveFile(file, page.URL)
+		if err != nil {
+			page.Error = err.Error()
+		}
+		pages = append(pages, page)
+	}
+
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("archive contains no HTML files")
+	}
+	return pages, nil
+}
+
+func (s *Scanner) scanArchiveFile(file *zip.File, pageURL string) (*ScanResult, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %v", file.Name, err)
+	}
+	defer rc.Close()
+
+	// Guard against entries whose header understates their real size
+	return s.ScanHTML(io.LimitReader(rc, MaxArchiveEntrySize), pageURL)
+}
+
+func isHTMLFile(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	return ext == ".html" || ext == ".htm" || ext == ".xhtml"
+}
+
+// resolvePagePath builds the URL a file in a site archive will be served from
+func resolvePagePath(baseURL, name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if baseURL == "" {
+		return name
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return name
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+	return base.ResolveReference(&url.URL{Path: name}).String()
+}
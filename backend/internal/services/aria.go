@@ -0,0 +1,447 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"tokubetsu/internal/aria"
+	"tokubetsu/internal/dom"
+
+	"golang.org/x/net/html"
+)
+
+// checkARIARoles checks that role attributes contain a valid, non-abstract role
+func checkARIARoles(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if n.Type == html.ElementNode && strings.TrimSpace(dom.Attr(n, "role")) != "" {
+		if aria.ExplicitRole(n) == "" {
+			help := fmt.Sprintf("role=%q is not a valid ARIA role", dom.Attr(n, "role"))
+			for _, token := range strings.Fields(strings.ToLower(dom.Attr(n, "role"))) {
+				if spec, ok := aria.LookupRole(token); ok && spec.Abstract {
+					help = fmt.Sprintf("%q is an abstract ARIA role and must not be used in content", token)
+					break
+				}
+			}
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "aria-roles",
+				Impact:      "critical",
+				Description: "ARIA role is not valid",
+				Help:        help,
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/aria-roles",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		} else {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "aria-roles",
+				Description: "ARIA role is valid",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkARIARoles(ctx, c, result)
+	}
+}
+
+// ariaAttributes returns the names of an element's aria-* attributes
+func ariaAttributes(n *html.Node) []string {
+	var names []string
+	for _, attr := range n.Attr {
+		if strings.HasPrefix(attr.Key, "aria-") {
+			names = append(names, attr.Key)
+		}
+	}
+	return names
+}
+
+// checkARIAValidAttr checks that aria-* attribute names are defined by WAI-ARIA
+func checkARIAValidAttr(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if names := ariaAttributes(n); n.Type == html.ElementNode && len(names) > 0 {
+		var unknown []string
+		for _, name := range names {
+			if _, ok := aria.LookupAttribute(name); !ok {
+				unknown = append(unknown, name)
+			}
+		}
+
+		if len(unknown) > 0 {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "aria-valid-attr",
+				Impact:      "critical",
+				Description: "ARIA attribute name is not valid",
+				Help:        fmt.Sprintf("ARIA attributes must be spelled correctly. Unknown attributes: %s", strings.Join(unknown, ", ")),
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/aria-valid-attr",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		} else {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "aria-valid-attr",
+				Description: "ARIA attribute names are valid",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkARIAValidAttr(ctx, c, result)
+	}
+}
+
+// checkARIAValidAttrValue checks aria-* values against their type, and that
+// ID references point at elements in the document
+func checkARIAValidAttrValue(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if n.Type == html.ElementNode {
+		var invalid []string
+		var checked bool
+		for _, a := range n.Attr {
+			spec, ok := aria.LookupAttribute(a.Key)
+			if !ok {
+				continue
+			}
+			checked = true
+			if !spec.ValidValue(a.Val) || !ctx.resolvesIDRefs(spec, a.Val) {
+				invalid = append(invalid, fmt.Sprintf("%s=%q", a.Key, a.Val))
+			}
+		}
+
+		if len(invalid) > 0 {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "aria-valid-attr-value",
+				Impact:      "critical",
+				Description: "ARIA attribute value is not valid",
+				Help:        fmt.Sprintf("ARIA attributes must have valid values. Invalid: %s", strings.Join(invalid, ", ")),
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/aria-valid-attr-value",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		} else if checked {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "aria-valid-attr-value",
+				Description: "ARIA attribute values are valid",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkARIAValidAttrValue(ctx, c, result)
+	}
+}
+
+// resolvesIDRefs reports whether an ID reference attribute points at an
+// element. Lists are valid when at least one ID resolves. Empty values are
+// allowed.
+func (ctx *ScanContext) resolvesIDRefs(spec aria.Attribute, value string) bool {
+	if spec.Type != aria.TypeIDRef && spec.Type != aria.TypeIDRefList {
+		return true
+	}
+	ids := strings.Fields(value)
+	if len(ids) == 0 {
+		return true
+	}
+	if spec.Type == aria.TypeIDRef && len(ids) > 1 {
+		return false
+	}
+	for _, id := range ids {
+		if ctx.accname().Lookup(id) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// checkARIAAllowedAttr checks that an element's aria-* attributes are
+// supported by its role and not prohibited on it
+func checkARIAAllowedAttr(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if names := ariaAttributes(n); n.Type == html.ElementNode && len(names) > 0 {
+		role := aria.Role(n)
+		// Elements without a role mapping, such as password inputs, are not checked
+		if spec, ok := aria.LookupRole(role); ok {
+			var disallowed []string
+			for _, name := range names {
+				if _, known := aria.LookupAttribute(name); known && !spec.Allowed(name) {
+					disallowed = append(disallowed, name)
+				}
+			}
+
+			if len(disallowed) > 0 {
+				result.Violations = append(result.Violations, AccessibilityCheck{
+					ID:          "aria-allowed-attr",
+					Impact:      "critical",
+					Description: "ARIA attribute is not allowed on this role",
+					Help:        fmt.Sprintf("Elements with role %q must not use %s", role, strings.Join(disallowed, ", ")),
+					HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/aria-allowed-attr",
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			} else {
+				result.Passes = append(result.Passes, AccessibilityCheck{
+					ID:          "aria-allowed-attr",
+					Description: "ARIA attributes are allowed for the element's role",
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			}
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkARIAAllowedAttr(ctx, c, result)
+	}
+}
+
+// nativeState reports whether an element's native semantics already supply
+// a required state, as a checkbox input does for aria-checked
+func nativeState(n *html.Node, attr string) bool {
+	inputType := strings.ToLower(dom.Attr(n, "type"))
+	switch attr {
+	case "aria-checked":
+		return dom.IsElement(n, "input") && (inputType == "checkbox" || inputType == "radio")
+	case "aria-valuenow":
+		return (dom.IsElement(n, "input") && (inputType == "range" || inputType == "number")) || dom.IsElement(n, "meter", "progress")
+	case "aria-expanded":
+		return dom.IsElement(n, "select")
+	}
+	return false
+}
+
+// checkARIARequiredAttr checks that elements with an explicit role have the
+// states and properties the role requires
+func checkARIARequiredAttr(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if role := aria.ExplicitRole(n); role != "" {
+		spec, _ := aria.LookupRole(role)
+		if len(spec.Required) > 0 {
+			var missing []string
+			for _, attr := range spec.Required {
+				if !dom.HasAttr(n, attr) && !nativeState(n, attr) {
+					missing = append(missing, attr)
+				}
+			}
+
+			if len(missing) > 0 {
+				result.Violations = append(result.Violations, AccessibilityCheck{
+					ID:          "aria-required-attr",
+					Impact:      "critical",
+					Description: "Required ARIA attribute is missing",
+					Help:        fmt.Sprintf("Elements with role %q must have %s", role, strings.Join(missing, ", ")),
+					HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/aria-required-attr",
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			} else {
+				result.Passes = append(result.Passes, AccessibilityCheck{
+					ID:          "aria-required-attr",
+					Description: "Required ARIA attributes are present",
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			}
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkARIARequiredAttr(ctx, c, result)
+	}
+}
+
+// isIgnoredRole reports whether an element is transparent when working out
+// ownership: it has no role, the generic role or a presentational role
+func isIgnoredRole(n *html.Node) bool {
+	role := aria.Role(n)
+	return role == "" || role == "generic" || aria.IsPresentational(n)
+}
+
+// ownedElements returns the elements owned by n in the accessibility tree:
+// its nearest descendants with a role, plus the targets of aria-owns
+func (ctx *ScanContext) ownedElements(n *html.Node) []*html.Node {
+	var owned []*html.Node
+	var collect func(*html.Node)
+	collect = func(p *html.Node) {
+		for c := p.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode || ctx.Hidden(c) {
+				continue
+			}
+			if isIgnoredRole(c) {
+				collect(c)
+				continue
+			}
+			owned = append(owned, c)
+		}
+	}
+	collect(n)
+
+	for _, id := range strings.Fields(dom.Attr(n, "aria-owns")) {
+		if ref := ctx.accname().Lookup(id); ref != nil && !ctx.Hidden(ref) {
+			owned = append(owned, ref)
+		}
+	}
+	return owned
+}
+
+func containsRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// checkARIARequiredChildren checks that elements with an explicit role own
+// the child roles the role requires, and no others
+func checkARIARequiredChildren(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if role := aria.ExplicitRole(n); role != "" && !ctx.Hidden(n) {
+		spec, _ := aria.LookupRole(role)
+		// Busy elements are still being populated
+		if len(spec.RequiredOwned) > 0 && dom.Attr(n, "aria-busy") != "true" {
+			var found bool
+			var unexpected []string
+			for _, child := range ctx.ownedElements(n) {
+				childRole := aria.Role(child)
+				if containsRole(spec.RequiredOwned, childRole) {
+					found = true
+				} else {
+					unexpected = append(unexpected, childRole)
+				}
+			}
+
+			if !found || len(unexpected) > 0 {
+				help := fmt.Sprintf("Elements with role %q must contain %s", role, strings.Join(spec.RequiredOwned, " or "))
+				if len(unexpected) > 0 {
+					help += fmt.Sprintf(". Not allowed: %s", strings.Join(unexpected, ", "))
+				}
+				result.Violations = append(result.Violations, AccessibilityCheck{
+					ID:          "aria-required-children",
+					Impact:      "critical",
+					Description: "ARIA role is missing required child roles",
+					Help:        help,
+					HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/aria-required-children",
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			} else {
+				result.Passes = append(result.Passes, AccessibilityCheck{
+					ID:          "aria-required-children",
+					Description: "ARIA role contains its required child roles",
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			}
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkARIARequiredChildren(ctx, c, result)
+	}
+}
+
+// owner returns the element that owns n in the accessibility tree: the
+// element referencing it through aria-owns, or its nearest ancestor with a role
+func (ctx *ScanContext) owner(n *html.Node) *html.Node {
+	if id := dom.Attr(n, "id"); id != "" {
+		var owner *html.Node
+		dom.Walk(ctx.Doc, func(e *html.Node) bool {
+			if owner != nil {
+				return false
+			}
+			for _, ref := range strings.Fields(dom.Attr(e, "aria-owns")) {
+				if ref == id {
+					owner = e
+					return false
+				}
+			}
+			return true
+		})
+		if owner != nil {
+			return owner
+		}
+	}
+
+	for p := n.Parent; p != nil && p.Type == html.ElementNode; p = p.Parent {
+		if !isIgnoredRole(p) {
+			return p
+		}
+	}
+	return nil
+}
+
+// checkARIARequiredParent checks that elements with an explicit role are
+// owned by one of the roles the role requires
+func checkARIARequiredParent(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if role := aria.ExplicitRole(n); role != "" && !ctx.Hidden(n) {
+		spec, _ := aria.LookupRole(role)
+		if len(spec.RequiredContext) > 0 {
+			owner := ctx.owner(n)
+			if owner == nil || !containsRole(spec.RequiredContext, aria.Role(owner)) {
+				result.Violations = append(result.Violations, AccessibilityCheck{
+					ID:          "aria-required-parent",
+					Impact:      "critical",
+					Description: "ARIA role is missing its required parent role",
+					Help:        fmt.Sprintf("Elements with role %q must be contained by %s", role, strings.Join(spec.RequiredContext, " or ")),
+					HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/aria-required-parent",
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			} else {
+				result.Passes = append(result.Passes, AccessibilityCheck{
+					ID:          "aria-required-parent",
+					Description: "ARIA role is contained by its required parent role",
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			}
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkARIARequiredParent(ctx, c, result)
+	}
+}
+
+// checkARIAHiddenFocus checks that aria-hidden content contains no
+// keyboard-focusable elements
+func checkARIAHiddenFocus(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if n.Type == html.ElementNode && strings.EqualFold(strings.TrimSpace(dom.Attr(n, "aria-hidden")), "true") {
+		var focusable []*html.Node
+		dom.Walk(n, func(e *html.Node) bool {
+			if aria.Tabbable(e) && !ctx.Styles.Hidden(e) {
+				focusable = append(focusable, e)
+			}
+			return true
+		})
+
+		if len(focusable) > 0 {
+			nodes := []string{getNodeHTML(n)}
+			for _, e := range focusable {
+				nodes = append(nodes, getNodeHTML(e))
+			}
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "aria-hidden-focus",
+				Impact:      "serious",
+				Description: "aria-hidden element contains focusable elements",
+				Help:        fmt.Sprintf("ARIA hidden elements must not be focusable or contain focusable elements. Found %d focusable element(s)", len(focusable)),
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/aria-hidden-focus",
+				Nodes:       nodes,
+				Targets:     ctx.targets(append([]*html.Node{n}, focusable...)...),
+			})
+		} else {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "aria-hidden-focus",
+				Description: "aria-hidden element does not contain focusable elements",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+		// Nested aria-hidden elements are covered by this one
+		return
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkARIAHiddenFocus(ctx, c, result)
+	}
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"math"
+
+	"tokubetsu/internal/color"
+	"tokubetsu/internal/cvd"
+	"tokubetsu/internal/dom"
+)
+
+// ColorVisionFinding is a text and background color pair that meets the AA
+// contrast minimum, but not when seen with a color vision deficiency
+type ColorVisionFinding struct {
+	Foreground          string         `json:"foreground"`
+	Background          string         `json:"background"`
+	Contrast            float64        `json:"contrast"`
+	SimulatedForeground string         `json:"simulatedForeground"`
+	SimulatedBackground string         `json:"simulatedBackground"`
+	SimulatedContrast   float64        `json:"simulatedContrast"`
+	Required            float64        `json:"required"` // AA minimum for the text size
+	Targets             []dom.Location `json:"targets"`  // Elements with this pair
+}
+
+// ColorVision returns the text colors of the document that lose their
+// contrast under each simulated color vision deficiency, keyed by deficiency.
+// Elements sharing a color pair and text size are reported together. Text
+// that already fails contrast is reported by color-contrast instead.
+func (ctx *ScanContext) ColorVision() map[string][]ColorVisionFinding {
+	findings := make(map[string][]ColorVisionFinding)
+	for _, deficiency := range cvd.All() {
+		findings[deficiency] = make([]ColorVisionFinding, 0)
+	}
+
+	type pair struct {
+		foreground, background string
+		required               float64
+	}
+	for _, deficiency := range cvd.All() {
+		index := make(map[pair]int)
+		for _, text := range ctx.textColors() {
+			required := minContrastAA
+			if text.largeText() {
+				required = minContrastAALarge
+			}
+			contrast := text.contrast()
+			if contrast+contrastRatioEpsilon < required {
+				continue
+			}
+
+			key := pair{text.foreground.Hex(), text.background.Hex(), required}
+			if i, ok := index[key]; ok {
+				findings[deficiency][i].Targets = append(findings[deficiency][i].Targets, ctx.Locate(text.node))
+				continue
+			}
+
+			foreground := cvd.Simulate(text.foreground, deficiency)
+			background := cvd.Simulate(text.background, deficiency)
+			simulated := color.ContrastRatio(foreground, background)
+			if simulated+contrastRatioEpsilon >= required {
+				continue
+			}
+			index[key] = len(findings[deficiency])
+			findings[deficiency] = append(findings[deficiency], ColorVisionFinding{
+				Foreground:          key.foreground,
+				Background:          key.background,
+				Contrast:            roundRatio(contrast),
+				SimulatedForeground: foreground.Hex(),
+				SimulatedBackground: background.Hex(),
+				SimulatedContrast:   roundRatio(simulated),
+				Required:            required,
+				Targets:             ctx.targets(text.node),
+			})
+		}
+	}
+	return findings
+}
+
+// roundRatio rounds a contrast ratio to two decimals
+func roundRatio(ratio float64) float64 {
+	return math.Round(ratio*100) / 100
+}
@@ -2,10 +2,13 @@ package services
 
 import (
 	"fmt"
+	"slices"
+	"sort"
 	"time"
 	"tokubetsu/internal/models"
-
-	"github.com/google/uuid"
+	"tokubetsu/internal/scoring"
+	"tokubetsu/internal/standards"
+	"tokubetsu/internal/wcag"
 )
 
 // ComplianceService handles WCAG compliance checking and reporting
@@ -20,12 +23,22 @@ func NewComplianceService(scanner *Scanner) *ComplianceService {
 	}
 }
 
-// GenerateReport creates a detailed compliance report for a given URL
-func (s *ComplianceService) GenerateReport(projectID uuid.UUID, url string) (*models.ComplianceReport, error) {
+// GenerateReport creates a detailed compliance report for a project's URL
+// against the given standards profile, scored with the given method
+func (s *ComplianceService) GenerateReport(project *models.Project, profileID, method string) (*models.ComplianceReport, error) {
+	profile, ok := standards.Lookup(profileID)
+	if !ok {
+		return nil, fmt.Errorf("unknown standards profile %q", profileID)
+	}
+	if !scoring.Valid(method) {
+		return nil, fmt.Errorf("unknown scoring method %q", method)
+	}
+
 	// Run accessibility scan
-	scanResult, err := s.scanner.ScanURL(url)
+	url := project.URL
+	scanResult, err := s.scanner.WithFetcher(ProjectFetcher(project)).ScanURL(url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to scan URL: %v", err)
+		return nil, fmt.Errorf("failed to scan URL: %w", err)
 	}
 
 	fmt.Printf("Scan found %d violations\n", len(scanResult.Violations))
@@ -40,53 +53,17 @@ func (s *ComplianceService) GenerateReport(projectID uuid.UUID, url string) (*mo
 
 	// Initialize report with default values
 	report := &models.ComplianceReport{
-		ProjectID:           projectID,
-		URL:                 url,
-		GeneratedAt:         time.Now(),
-		OverallScore:        0,
-		LevelAScore:         0,
-		LevelAAScore:        0,
-		LevelAAAScore:       0,
-		PerceivableScore:    100.0,
-		OperableScore:       100.0,
-		UnderstandableScore: 100.0,
-		RobustScore:         100.0,
-	}
-
-	// Count passes for each level/principle
-	var (
-		levelAPasses, levelAAPasses, levelAAAPasses                           int
-		perceivablePasses, operablePasses, understandablePasses, robustPasses int
-	)
-	for _, pass := range scanResult.Passes {
-		if isLevelARule(pass.ID) {
-			levelAPasses++
-		}
-		if isLevelAARule(pass.ID) {
-			levelAAPasses++
-		}
-		if isLevelAAARule(pass.ID) {
-			levelAAAPasses++
-		}
-		if isPerceivableRule(pass.ID) {
-			perceivablePasses++
-		}
-		if isOperableRule(pass.ID) {
-			operablePasses++
-		}
-		if isUnderstandableRule(pass.ID) {
-			understandablePasses++
-		}
-		if isRobustRule(pass.ID) {
-			robustPasses++
-		}
+		ProjectID:        project.ID,
+		URL:              url,
+		Profile:          profile.ID,
+		ScoringMethod:    method,
+		PassCounts:       s.scanner.passCounts(scanResult),
+		GeneratedAt:      time.Now(),
+		OverallScore:     0,
+		ConformanceLevel: ConformanceNone,
 	}
 
-	// Process violations and categorize them
-	var (
-		levelAViolations, levelAAViolations, levelAAAViolations                               int
-		perceivableViolations, operableViolations, understandableViolations, robustViolations int
-	)
+	// Process violations and attach their success criteria
 	for _, violation := range scanResult.Violations {
 		compViolation := models.ComplianceViolation{
 			ReportID:    report.ID,
@@ -96,123 +73,100 @@ func (s *ComplianceService) GenerateReport(projectID uuid.UUID, url string) (*mo
 			Element:     violation.Nodes[0],
 			Suggestion:  violation.Help,
 		}
-		switch {
-		case isLevelARule(violation.ID):
-			levelAViolations++
-			compViolation.WCAGLevel = "A"
-		case isLevelAARule(violation.ID):
-			levelAAViolations++
-			compViolation.WCAGLevel = "AA"
-		case isLevelAAARule(violation.ID):
-			levelAAAViolations++
-			compViolation.WCAGLevel = "AAA"
+		if len(violation.Targets) > 0 {
+			compViolation.Selector = violation.Targets[0].Selector
+			compViolation.XPath = violation.Targets[0].XPath
+			compViolation.Line = violation.Targets[0].Line
+			compViolation.Column = violation.Targets[0].Column
 		}
-		switch {
-		case isPerceivableRule(violation.ID):
-			perceivableViolations++
-		case isOperableRule(violation.ID):
-			operableViolations++
-		case isUnderstandableRule(violation.ID):
-			understandableViolations++
-		case isRobustRule(violation.ID):
-			robustViolations++
+		meta, ok := s.scanner.Rules().Lookup(violation.ID)
+		if !ok {
+			return nil, fmt.Errorf("violation %q does not belong to a registered rule", violation.ID)
 		}
+		// Registered rules always map to known success criteria
+		criterion, _ := wcag.Lookup(meta.Criteria[0])
+		compViolation.Criterion = criterion.Number
+		compViolation.Criteria = meta.Criteria
+		compViolation.WCAGLevel = criterion.Level
 		report.Violations = append(report.Violations, compViolation)
 	}
 
-	// Calculate level scores
-	if levelAPasses+levelAViolations > 0 {
-		report.LevelAScore = float64(levelAPasses) / float64(levelAPasses+levelAViolations) * 100
-	}
-	if levelAAPasses+levelAAViolations > 0 {
-		report.LevelAAScore = float64(levelAAPasses) / float64(levelAAPasses+levelAAViolations) * 100
-	}
-	if levelAAAPasses+levelAAAViolations > 0 {
-		report.LevelAAAScore = float64(levelAAAPasses) / float64(levelAAAPasses+levelAAAViolations) * 100
-	}
-
-	// Calculate category scores
-	if perceivablePasses+perceivableViolations > 0 {
-		report.PerceivableScore = float64(perceivablePasses) / float64(perceivablePasses+perceivableViolations) * 100
-	}
-	if operablePasses+operableViolations > 0 {
-		report.OperableScore = float64(operablePasses) / float64(operablePasses+operableViolations) * 100
-	}
-	if understandablePasses+understandableViolations > 0 {
-		report.UnderstandableScore = float64(understandablePasses) / float64(understandablePasses+understandableViolations) * 100
-	}
-	if robustPasses+robustViolations > 0 {
-		report.RobustScore = float64(robustPasses) / float64(robustPasses+robustViolations) * 100
-	}
-
-	// Calculate Overall Score as an average of the four principle scores
-	report.OverallScore = (report.PerceivableScore + report.OperableScore + report.UnderstandableScore + report.RobustScore) / 4.0
-	// Ensure OverallScore is not NaN if all principle scores are 0 (e.g. no relevant rules triggered)
-	if report.PerceivableScore == 0 && report.OperableScore == 0 && report.UnderstandableScore == 0 && report.RobustScore == 0 {
-		report.OverallScore = 0 // Or handle as appropriate, e.g. if no rules, score is 100 or N/A
-	}
+	// Conformance and scores come from the criterion table
+	report.Results = s.scanner.Rules().evaluateCriteria(scanResult, profile)
+	report.Checklist = manualChecklist(report.Results)
+	ApplyManualResults(report)
+	PrepareReport(report)
 
 	return report, nil
 }
 
-// Helper functions to categorize rules
-func isLevelARule(ruleID string) bool {
-	levelARules := map[string]bool{
-		"image-alt":   true,
-		"button-name": true,
-		"label":       true,
-		"link-name":   true,
-	}
-	return levelARules[ruleID]
-}
-
-func isLevelAARule(ruleID string) bool {
-	levelAARules := map[string]bool{
-		"color-contrast":                      true,
-		"frame-title":                         true,
-		"landmark-complementary-is-top-level": true,
-	}
-	return levelAARules[ruleID]
-}
-
-func isLevelAAARule(ruleID string) bool {
-	levelAAARules := map[string]bool{
-		"target-size":       true,
-		"timing-adjustable": true,
+// GroupByCriterion groups violations under every requirement of the profile
+// their rule tests, in the order of the profile. Criteria outside the profile
+// are grouped on their own and marked out of scope.
+func GroupByCriterion(violations []models.ComplianceViolation, profile *standards.Profile) []models.CriterionGroup {
+	groups := make(map[string]*models.CriterionGroup)
+	group := func(key string, create func() *models.CriterionGroup) *models.CriterionGroup {
+		if groups[key] == nil {
+			groups[key] = create()
+			groups[key].Rules = []string{}
+		}
+		return groups[key]
 	}
-	return levelAAARules[ruleID]
-}
 
-func isPerceivableRule(ruleID string) bool {
-	perceivableRules := map[string]bool{
-		"image-alt":      true,
-		"color-contrast": true,
-		"video-caption":  true,
-	}
-	return perceivableRules[ruleID]
-}
+	for _, v := range violations {
+		criteria := v.Criteria
+		if len(criteria) == 0 && v.Criterion != "" {
+			criteria = []string{v.Criterion}
+		}
 
-func isOperableRule(ruleID string) bool {
-	operableRules := map[string]bool{
-		"button-name": true,
-		"link-name":   true,
-		"target-size": true,
-	}
-	return operableRules[ruleID]
-}
+		var matched []*models.CriterionGroup
+		for _, number := range criteria {
+			criterion, _ := wcag.Lookup(number)
+			requirement, inScope := profile.Requirement(number)
+			key := number
+			if inScope {
+				key = requirement.ID
+			}
+			matched = append(matched, group(key, func() *models.CriterionGroup {
+				return &models.CriterionGroup{
+					Requirement: key,
+					InScope:     inScope,
+					Criterion:   number,
+					Title:       criterion.Title,
+					Level:       criterion.Level,
+					Principle:   criterion.Principle,
+					URL:         criterion.URL,
+				}
+			}))
+		}
+		// Additions of the standard tested by the violation's rule
+		for _, requirement := range profile.Requirements {
+			if requirement.Criterion == "" && slices.Contains(requirement.Rules, v.RuleID) {
+				matched = append(matched, group(requirement.ID, func() *models.CriterionGroup {
+					return &models.CriterionGroup{
+						Requirement: requirement.ID,
+						InScope:     true,
+						Criterion:   requirement.ID,
+						Title:       requirement.Title,
+						Principle:   requirement.Principle,
+					}
+				}))
+			}
+		}
 
-func isUnderstandableRule(ruleID string) bool {
-	understandableRules := map[string]bool{
-		"label":          true,
-		"language-valid": true,
+		for _, g := range matched {
+			if !slices.Contains(g.Rules, v.RuleID) {
+				g.Rules = append(g.Rules, v.RuleID)
+			}
+			g.Count++
+		}
 	}
-	return understandableRules[ruleID]
-}
 
-func isRobustRule(ruleID string) bool {
-	robustRules := map[string]bool{
-		"aria-valid":    true,
-		"html-has-lang": true,
+	less := requirementOrder(profile)
+	result := make([]models.CriterionGroup, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, *g)
 	}
-	return robustRules[ruleID]
+	sort.Slice(result, func(i, j int) bool { return less(result[i].Requirement, result[j].Requirement) })
+	return result
 }
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"sort"
+
+	"tokubetsu/internal/models"
+	"tokubetsu/internal/standards"
+	"tokubetsu/internal/wcag"
+)
+
+// ConformanceNone is the conformance level of a report that does not meet Level A
+const ConformanceNone = "none"
+
+// evaluateCriteria builds the result table of every requirement of a
+// profile from the rules of the registry and the outcome of a scan
+func (r *RuleRegistry) evaluateCriteria(scan *ScanResult, profile *standards.Profile) []models.CriterionResult {
+	passes := make(map[string]int)
+	for _, pass := range scan.Passes {
+		passes[pass.ID]++
+	}
+	violations := make(map[string]int)
+	for _, violation := range scan.Violations {
+		violations[violation.ID]++
+	}
+
+	// Rules testing each criterion, and those of them that ran
+	mapped := make(map[string]int)
+	ran := make(map[string][]string)
+	enabled := make(map[string]bool)
+	for _, entry := range r.Catalog() {
+		enabled[entry.ID] = entry.Enabled
+		for _, number := range entry.RuleMeta.Criteria {
+			mapped[number]++
+			if entry.Enabled {
+				ran[number] = append(ran[number], entry.ID)
+			}
+		}
+	}
+
+	results := make([]models.CriterionResult, 0, len(profile.Requirements))
+	for _, requirement := range profile.Requirements {
+		result := models.CriterionResult{
+			Criterion:   requirement.Key(),
+			Requirement: requirement.ID,
+			Level:       requirement.Level,
+			Source:      models.SourceAutomated,
+			Rules:       []string{},
+		}
+		if requirement.Criterion != "" {
+			result.Automatable = mapped[requirement.Criterion] > 0
+			result.Rules = append(result.Rules, ran[requirement.Criterion]...)
+		} else {
+			// Additions of the standard name the rules that test them
+			for _, id := range requirement.Rules {
+				if ruleEnabled, registered := enabled[id]; registered {
+					result.Automatable = true
+					if ruleEnabled {
+						result.Rules = append(result.Rules, id)
+					}
+				}
+			}
+		}
+		for _, id := range result.Rules {
+			result.Passes += passes[id]
+			result.Violations += violations[id]
+		}
+
+		switch {
+		case !result.Automatable:
+			result.Status = models.CriterionNeedsManual
+		case len(result.Rules) == 0:
+			result.Status = models.CriterionNotTested
+		case result.Violations > 0:
+			result.Status = models.CriterionFailed
+		case result.Passes > 0:
+			// Rules find failures, but cannot confirm that alt text describes
+			// its image or that a link's text gives its purpose. A pass is
+			// only final once a reviewer has confirmed it.
+			result.Status = models.CriterionNeedsReview
+		default:
+			// The rules ran but found nothing they apply to
+			result.Status = models.CriterionNotApplicable
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// ConformanceLevel returns the highest WCAG level up to target at which every
+// criterion of that level and below passed or does not apply, or ConformanceNone
+func ConformanceLevel(results []models.CriterionResult, target string) string {
+	conformance := ConformanceNone
+	for _, level := range []string{LevelA, LevelAA, LevelAAA} {
+		for _, result := range results {
+			if result.Level != level {
+				continue
+			}
+			if !resolved(result) {
+				return conformance
+			}
+		}
+		conformance = level
+		if level == target {
+			break
+		}
+	}
+	return conformance
+}
+
+// resolved reports whether a result passed or does not apply
+func resolved(result models.CriterionResult) bool {
+	return result.Status == models.CriterionPassed || result.Status == models.CriterionNotApplicable
+}
+
+// manualChecklist lists the criteria automated testing could not decide,
+// including those whose automated checks passed and need review
+func manualChecklist(results []models.CriterionResult) []models.ManualCheck {
+	checklist := make([]models.ManualCheck, 0)
+	for _, result := range results {
+		switch result.Status {
+		case models.CriterionNeedsManual, models.CriterionNotTested, models.CriterionNeedsReview:
+		default:
+			continue
+		}
+		checklist = append(checklist, models.ManualCheck{
+			Criterion:       result.Criterion,
+			Requirement:     result.Requirement,
+			Level:           result.Level,
+			AutomatedStatus: result.Status,
+			Result:          models.ManualPending,
+		})
+	}
+	return checklist
+}
+
+// ManualResultAssociations are the associations of a report that
+// ApplyManualResults reads. Scores are recomputed from the violations, so a
+// report loaded without them would lose every automated failure.
+var ManualResultAssociations = []string{"Violations", "Results", "Checklist"}
+
+// ApplyManualResults merges the audited checklist of a report into its
+// criterion results and recomputes the conformance level and scores. The
+// report must have its ManualResultAssociations loaded.
+func ApplyManualResults(report *models.ComplianceReport) {
+	checks := make(map[string]models.ManualCheck, len(report.Checklist))
+	for _, check := range report.Checklist {
+		checks[check.Criterion] = check
+	}
+
+	for i := range report.Results {
+		result := &report.Results[i]
+		check, ok := checks[result.Criterion]
+		if !ok {
+			continue
+		}
+		if check.Result == models.ManualPending {
+			result.Status = check.AutomatedStatus
+			result.Source = models.SourceAutomated
+		} else {
+			result.Status = check.Result
+			result.Source = models.SourceManual
+		}
+	}
+
+	profile := standards.Resolve(report.Profile)
+	report.ConformanceLevel = ConformanceLevel(report.Results, profile.Level)
+	report.Conforms = true
+	for _, result := range report.Results {
+		if !resolved(result) {
+			report.Conforms = false
+		}
+	}
+	scoreReport(report, profile)
+}
+
+// PrepareReport fills in the parts of a stored report that are not persisted
+// as they are served: criterion results and checklist in the order of the
+// profile and violations grouped by requirement
+func PrepareReport(report *models.ComplianceReport) {
+	profile := standards.Resolve(report.Profile)
+	less := requirementOrder(profile)
+	sort.SliceStable(report.Results, func(i, j int) bool {
+		return less(report.Results[i].Criterion, report.Results[j].Criterion)
+	})
+	sort.SliceStable(report.Checklist, func(i, j int) bool {
+		return less(report.Checklist[i].Criterion, report.Checklist[j].Criterion)
+	})
+	report.Criteria = GroupByCriterion(report.Violations, profile)
+}
+
+// requirementOrder returns a less function ordering requirement keys and
+// clause numbers as the profile lists them, followed by other criteria in
+// specification order
+func requirementOrder(profile *standards.Profile) func(a, b string) bool {
+	position := make(map[string]int, 2*len(profile.Requirements))
+	for i, requirement := range profile.Requirements {
+		position[requirement.Key()] = i
+		position[requirement.ID] = i
+	}
+	return func(a, b string) bool {
+		i, aok := position[a]
+		j, bok := position[b]
+		switch {
+		case aok && bok:
+			return i < j
+		case aok != bok:
+			return aok
+		}
+		return wcag.Less(a, b)
+	}
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"testing"
+
+	"tokubetsu/internal/models"
+	"tokubetsu/internal/standards"
+)
+
+func TestAutomatedPassNeedsReview(t *testing.T) {
+	scan := &ScanResult{Passes: []AccessibilityCheck{
+		{ID: "image-alt", Nodes: []string{`<img alt="Logo">`}},
+		{ID: "link-name", Nodes: []string{`<a href="/">Home</a>`}},
+	}}
+	profile := standards.Resolve("wcag22-aa")
+	results := NewScanner().rules.evaluateCriteria(scan, profile)
+
+	var reviewed []models.CriterionResult
+	for _, result := range results {
+		switch result.Criterion {
+		case "1.1.1", "2.4.4":
+			if result.Status != models.CriterionNeedsReview {
+				t.Errorf("%s status = %s, want %s", result.Criterion, result.Status, models.CriterionNeedsReview)
+			}
+			reviewed = append(reviewed, result)
+		}
+	}
+	if len(reviewed) != 2 {
+		t.Fatalf("found %d of the criteria 1.1.1 and 2.4.4", len(reviewed))
+	}
+
+	// Only the two criteria, so conformance depends on them alone
+	report := &models.ComplianceReport{Profile: profile.ID, Results: reviewed}
+	report.Checklist = manualChecklist(report.Results)
+	if len(report.Checklist) != 2 {
+		t.Fatalf("checklist has %d items, want 1.1.1 and 2.4.4", len(report.Checklist))
+	}
+
+	ApplyManualResults(report)
+	if report.ConformanceLevel != ConformanceNone || report.Conforms {
+		t.Errorf("unreviewed passes conform at %q, want %q", report.ConformanceLevel, ConformanceNone)
+	}
+
+	for i := range report.Checklist {
+		report.Checklist[i].Result = models.ManualPassed
+	}
+	ApplyManualResults(report)
+	if report.ConformanceLevel != LevelAA || !report.Conforms {
+		t.Errorf("reviewed passes conform at %q, want %q", report.ConformanceLevel, LevelAA)
+	}
+
+	// Resetting a review restores the automated status
+	report.Checklist[0].Result = models.ManualPending
+	ApplyManualResults(report)
+	if report.Results[0].Status != models.CriterionNeedsReview || report.ConformanceLevel != ConformanceNone {
+		t.Errorf("pending review left %s at %q", report.Results[0].Status, report.ConformanceLevel)
+	}
+}
+
+// loadedFor returns a copy of a report with only the given associations, as
+// gorm loads it when the caller preloads them
+func loadedFor(report *models.ComplianceReport, associations []string) *models.ComplianceReport {
+	loaded := *report
+	want := make(map[string]bool)
+	for _, association := range associations {
+		want[association] = true
+	}
+	if !want["Violations"] {
+		loaded.Violations = nil
+	}
+	if !want["Results"] {
+		loaded.Results = nil
+	}
+	if !want["Checklist"] {
+		loaded.Checklist = nil
+	}
+	loaded.Results = append([]models.CriterionResult(nil), loaded.Results...)
+	loaded.Checklist = append([]models.ManualCheck(nil), loaded.Checklist...)
+	return &loaded
+}
+
+func TestNotesOnlyUpdateKeepsScores(t *testing.T) {
+	scanner := NewScanner()
+	profile := standards.Resolve("wcag22-aa")
+	for _, method := range []string{"impact_weighted", "pass_ratio", "criteria"} {
+		t.Run(method, func(t *testing.T) {
+			generated := fixtureReport(scanner, scoringFixture(), method, profile)
+			generated.Checklist = manualChecklist(generated.Results)
+			ApplyManualResults(generated)
+
+			// UpdateCheck loads the report with ManualResultAssociations
+			report := loadedFor(generated, ManualResultAssociations)
+			report.Checklist[0].Notes = "Checked with a screen reader"
+			ApplyManualResults(report)
+
+			if report.OverallScore != generated.OverallScore {
+				t.Errorf("overall score = %.2f after a notes update, want %.2f", report.OverallScore, generated.OverallScore)
+			}
+			if report.ConformanceLevel != generated.ConformanceLevel {
+				t.Errorf("conformance = %q after a notes update, want %q", report.ConformanceLevel, generated.ConformanceLevel)
+			}
+		})
+	}
+}
+
+func TestDuplicateIDsByVersion(t *testing.T) {
+	scan := &ScanResult{Violations: []AccessibilityCheck{
+		{ID: "duplicate-id", Impact: "minor", Nodes: []string{`<div id="main">`}},
+	}}
+	scanner := NewScanner()
+	statuses := func(profileID string) map[string]string {
+		byCriterion := make(map[string]string)
+		for _, result := range scanner.rules.evaluateCriteria(scan, standards.Resolve(profileID)) {
+			byCriterion[result.Criterion] = result.Status
+		}
+		return byCriterion
+	}
+
+	wcag21 := statuses("wcag21-aa")
+	if wcag21["4.1.1"] != models.CriterionFailed {
+		t.Errorf("wcag21-aa: 4.1.1 status = %q, want %q", wcag21["4.1.1"], models.CriterionFailed)
+	}
+	for profileID, byCriterion := range map[string]map[string]string{"wcag21-aa": wcag21, "wcag22-aa": statuses("wcag22-aa")} {
+		if byCriterion["4.1.2"] == models.CriterionFailed {
+			t.Errorf("%s: a duplicate id fails 4.1.2", profileID)
+		}
+	}
+
+	score, err := scanner.Score(scan, "impact_weighted", standards.Resolve("wcag22-aa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score.Overall != 100 {
+		t.Errorf("wcag22-aa: overall score = %.2f, want 100 for an informational duplicate id", score.Overall)
+	}
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"tokubetsu/internal/a11ytree"
+	"tokubetsu/internal/accname"
+	"tokubetsu/internal/aria"
+	"tokubetsu/internal/css"
+	"tokubetsu/internal/dom"
+
+	"golang.org/x/net/html"
+)
+
+// ScanContext carries the document and all mutable state of a single scan.
+// A new context is created for every scan and must not be shared.
+type ScanContext struct {
+	URL string
+	Doc *html.Node
+	// Styles computes the cascaded style of the document's elements
+	Styles *css.Resolver
+
+	locator *dom.Locator
+	names   *accname.Computer
+
+	// Heading levels seen so far, in document order
+	headingLevels []int
+	// Computed text colors, collected on first use
+	colors []textColor
+	// Text analysis, computed on first use
+	readability *Readability
+}
+
+func newScanContext(url string, doc *html.Node, source []byte, sheets []*css.Stylesheet) *ScanContext {
+	return &ScanContext{
+		URL:           url,
+		Doc:           doc,
+		Styles:        css.NewResolver(sheets...),
+		locator:       dom.NewLocator(doc, source),
+		headingLevels: make([]int, 0),
+	}
+}
+
+// Locate returns the selector, XPath and source position of an element
+func (ctx *ScanContext) Locate(n *html.Node) dom.Location {
+	return ctx.locator.Locate(n)
+}
+
+// targets returns the locators for a check's nodes
+func (ctx *ScanContext) targets(nodes ...*html.Node) []dom.Location {
+	locations := make([]dom.Location, 0, len(nodes))
+	for _, n := range nodes {
+		locations = append(locations, ctx.Locate(n))
+	}
+	return locations
+}
+
+// Hidden reports whether an element is excluded from the accessibility tree,
+// either because it is not rendered or because of aria-hidden
+func (ctx *ScanContext) Hidden(n *html.Node) bool {
+	// Image map areas are never rendered, but are exposed through their image
+	if dom.IsElement(n, "area") {
+		return aria.Hidden(n)
+	}
+	return ctx.Styles.Hidden(n) || aria.HiddenByARIA(n)
+}
+
+// Name returns the accessible name of an element
+func (ctx *ScanContext) Name(n *html.Node) string {
+	return ctx.accname().Name(n)
+}
+
+// Description returns the accessible description of an element
+func (ctx *ScanContext) Description(n *html.Node) string {
+	return ctx.accname().Description(n)
+}
+
+func (ctx *ScanContext) accname() *accname.Computer {
+	if ctx.names == nil {
+		ctx.names = accname.New(ctx.Doc, ctx.Hidden)
+	}
+	return ctx.names
+}
+
+// AccessibilityTree builds the accessibility tree of the document
+func (ctx *ScanContext) AccessibilityTree() *a11ytree.Node {
+	return a11ytree.Build(ctx.Doc, a11ytree.Options{
+		Hidden: ctx.Hidden,
+		Names:  ctx.accname(),
+		Selector: func(n *html.Node) string {
+			return ctx.Locate(n).Selector
+		},
+	})
+}
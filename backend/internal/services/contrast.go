@@ -0,0 +1,130 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"tokubetsu/internal/color"
+
+	"golang.org/x/net/html"
+)
+
+// WCAG 2.x large text thresholds in CSS pixels (18pt, or 14pt bold)
+const (
+	largeTextSize     = 24.0
+	largeBoldTextSize = 18.66
+	boldFontWeight    = 700
+)
+
+// Minimum contrast ratios for normal and large text
+const (
+	minContrastAA        = 4.5
+	minContrastAALarge   = 3.0
+	minContrastAAA       = 7.0
+	minContrastAAALarge  = 4.5
+	contrastRatioEpsilon = 1e-9
+)
+
+// textColor is the computed color pair of an element that renders text
+type textColor struct {
+	node       *html.Node
+	foreground color.RGBA // composited over background
+	background color.RGBA
+	fontSize   float64
+	fontWeight int
+}
+
+// largeText reports whether the text is large scale in the sense of WCAG 1.4.3
+func (t textColor) largeText() bool {
+	return t.fontSize >= largeTextSize || (t.fontSize >= largeBoldTextSize && t.fontWeight >= boldFontWeight)
+}
+
+func (t textColor) contrast() float64 {
+	return color.ContrastRatio(t.foreground, t.background)
+}
+
+// textColors returns the computed color pairs of every visible element with
+// its own text. Elements over background images are left out, since the
+// color behind their text is unknown.
+func (ctx *ScanContext) textColors() []textColor {
+	if ctx.colors != nil {
+		return ctx.colors
+	}
+
+	ctx.colors = make([]textColor, 0)
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		if n.Type == html.ElementNode && hasOwnText(n) && !ctx.Styles.Hidden(n) {
+			style := ctx.Styles.Style(n)
+			fg, fgOK := style.ForegroundColor()
+			bg, bgOK := ctx.Styles.Background(n)
+			if fgOK && bgOK {
+				ctx.colors = append(ctx.colors, textColor{
+					node:       n,
+					foreground: fg.Over(bg),
+					background: bg,
+					fontSize:   style.FontSize,
+					fontWeight: style.FontWeight,
+				})
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+	f(ctx.Doc)
+	return ctx.colors
+}
+
+// hasOwnText reports whether an element has non-whitespace text children
+func hasOwnText(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.TextNode && strings.TrimSpace(c.Data) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Color contrast check (Level AA, 1.4.3)
+func checkColorContrast(ctx *ScanContext, doc *html.Node, result *ScanResult) {
+	checkContrast(ctx, result, "color-contrast", LevelAA, minContrastAA, minContrastAALarge)
+}
+
+// Enhanced color contrast check (Level AAA, 1.4.6)
+func checkColorContrastEnhanced(ctx *ScanContext, doc *html.Node, result *ScanResult) {
+	checkContrast(ctx, result, "color-contrast-enhanced", LevelAAA, minContrastAAA, minContrastAAALarge)
+}
+
+func checkContrast(ctx *ScanContext, result *ScanResult, id, level string, minNormal, minLarge float64) {
+	for _, text := range ctx.textColors() {
+		n := text.node
+		required := minNormal
+		size := "normal"
+		if text.largeText() {
+			required = minLarge
+			size = "large"
+		}
+
+		contrast := text.contrast()
+		if contrast+contrastRatioEpsilon < required {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          id,
+				Impact:      "serious",
+				Description: "Text has insufficient color contrast",
+				Help: fmt.Sprintf("Foreground and background colors must have sufficient contrast (%s: %.1f:1 for %s text). Found %.2f:1 for %s on %s",
+					level, required, size, contrast, text.foreground.Hex(), text.background.Hex()),
+				HelpURL: "https://dequeuniversity.com/rules/axe/4.6/" + id,
+				Nodes:   []string{getNodeHTML(n)},
+				Targets: ctx.targets(n),
+			})
+		} else {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          id,
+				Description: "Text has sufficient color contrast",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+}
@@ -0,0 +1,399 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"tokubetsu/internal/scoring"
+	"tokubetsu/internal/standards"
+
+	"golang.org/x/net/html"
+)
+
+// Crawl defaults and limits
+const (
+	DefaultCrawlMaxDepth    = 2
+	DefaultCrawlMaxPages    = 50
+	MaxCrawlPages           = 500
+	defaultCrawlConcurrency = 4
+	maxSitemapFiles         = 10
+)
+
+// CrawlOptions controls which pages of a site are scanned
+type CrawlOptions struct {
+	// Link hops from the start URL. 0 follows no links, so only the start
+	// page and sitemap entries are scanned. Omitted uses DefaultCrawlMaxDepth.
+	MaxDepth    *int     `json:"max_depth"`
+	MaxPages    int      `json:"max_pages"`    // total pages scanned
+	Include     []string `json:"include"`      // regular expressions, a URL must match one of them if set
+	Exclude     []string `json:"exclude"`      // regular expressions, matching URLs are skipped
+	SkipSitemap bool     `json:"skip_sitemap"` // do not seed the crawl from sitemap.xml
+
+	// Scoring method and standards profile pages are scored with. They are
+	// set from the project, not the request.
+	ScoringMethod string `json:"-"`
+	Profile       string `json:"-"`
+}
+
+// SiteScanResult aggregates the page results of a crawl
+type SiteScanResult struct {
+	StartURL   string       `json:"start_url"`
+	Pages      []PageResult `json:"pages"`
+	Score      float64      `json:"score"`
+	Violations int          `json:"violations"`
+	Passes     int          `json:"passes"`
+}
+
+type crawlFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// Validate fills in defaults and checks the include and exclude patterns
+func (o *CrawlOptions) Validate() error {
+	if o.MaxDepth == nil {
+		depth := DefaultCrawlMaxDepth
+		o.MaxDepth = &depth
+	}
+	if *o.MaxDepth < 0 {
+		return fmt.Errorf("max_depth must not be negative")
+	}
+	if o.MaxPages <= 0 {
+		o.MaxPages = DefaultCrawlMaxPages
+	}
+	if o.MaxPages > MaxCrawlPages {
+		return fmt.Errorf("max_pages must not exceed %d", MaxCrawlPages)
+	}
+	if o.ScoringMethod == "" {
+		o.ScoringMethod = scoring.DefaultMethod
+	}
+	if !scoring.Valid(o.ScoringMethod) {
+		return fmt.Errorf("unknown scoring method %q", o.ScoringMethod)
+	}
+	_, err := o.filter()
+	return err
+}
+
+func (o *CrawlOptions) filter() (*crawlFilter, error) {
+	filter := &crawlFilter{}
+	for _, pattern := range o.Include {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include pattern %q: %v", pattern, err)
+		}
+		filter.include = append(filter.include, re)
+	}
+	for _, pattern := range o.Exclude {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %v", pattern, err)
+		}
+		filter.exclude = append(filter.exclude, re)
+	}
+	return filter, nil
+}
+
+func (f *crawlFilter) matches(u string) bool {
+	for _, re := range f.exclude {
+		if re.MatchString(u) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, re := range f.include {
+		if re.MatchString(u) {
+			return true
+		}
+	}
+	return false
+}
+
+type crawlTarget struct {
+	url   *url.URL
+	depth int
+}
+
+// CrawlSite scans a site starting at startURL. It follows same-origin links
+// and sitemap.xml entries, honours robots.txt, and stops at the configured
+// depth and page count. The start URL is always scanned.
+func (s *Scanner) CrawlSite(startURL string, opts CrawlOptions) (*SiteScanResult, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	filter, _ := opts.filter()
+
+	start, err := url.Parse(startURL)
+	if err != nil || start.Host == "" {
+		return nil, fmt.Errorf("invalid start URL: %s", startURL)
+	}
+	start.Fragment = ""
+
+	robots := s.fetchRobots(start)
+
+	seen := map[string]bool{start.String(): true}
+	level := []crawlTarget{{url: start, depth: 0}}
+	var sitemapSeeds []crawlTarget
+	if !opts.SkipSitemap {
+		for _, u := range s.fetchSitemapURLs(start, robots) {
+			// Sitemap entries count as one hop from the start page
+			if key := u.String(); !seen[key] && filter.matches(key) && robots.allowed(u) {
+				seen[key] = true
+				sitemapSeeds = append(sitemapSeeds, crawlTarget{url: u, depth: 1})
+			}
+		}
+	}
+
+	site := &SiteScanResult{StartURL: start.String(), Pages: make([]PageResult, 0)}
+	for len(level) > 0 && len(site.Pages) < opts.MaxPages {
+		if remaining := opts.MaxPages - len(site.Pages); len(level) > remaining {
+			level = level[:remaining]
+		}
+
+		pages, links := s.crawlLevel(level, seen)
+		site.Pages = append(site.Pages, pages...)
+
+		var next []crawlTarget
+		if level[0].depth == 0 {
+			next = append(next, sitemapSeeds...)
+		}
+		depth := level[0].depth + 1
+		if depth <= *opts.MaxDepth {
+			for _, link := range links {
+				key := link.String()
+				if seen[key] || !sameOrigin(start, link) || !filter.matches(key) || !robots.allowed(link) {
+					continue
+				}
+				seen[key] = true
+				next = append(next, crawlTarget{url: link, depth: depth})
+			}
+		}
+		level = next
+	}
+
+	profile := standards.Resolve(opts.Profile)
+	var totalScore float64
+	var scored int
+	for i := range site.Pages {
+		page := &site.Pages[i]
+		if page.Result == nil {
+			continue
+		}
+		score, err := s.Score(page.Result, opts.ScoringMethod, profile)
+		if err != nil {
+			return nil, err
+		}
+		page.Score = score.Overall
+		site.Violations += len(page.Result.Violations)
+		site.Passes += len(page.Result.Passes)
+		totalScore += page.Score
+		scored++
+	}
+	if scored == 0 {
+		return nil, fmt.Errorf("no page of %s could be scanned", start)
+	}
+	site.Score = totalScore / float64(scored)
+
+	return site, nil
+}
+
+// crawlLevel scans the pages of one crawl depth concurrently and returns the
+// results in input order together with the links found on them. The URLs
+// pages were redirected to are marked as seen, and a page redirected to one
+// that was already seen is dropped, so no page is scanned twice.
+func (s *Scanner) crawlLevel(targets []crawlTarget, seen map[string]bool) ([]PageResult, []*url.URL) {
+	pages := make([]PageResult, len(targets))
+	finals := make([]*url.URL, len(targets))
+	links := make([][]*url.URL, len(targets))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, defaultCrawlConcurrency)
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target crawlTarget) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			pages[i], finals[i], links[i] = s.crawlPage(target.url)
+		}(i, target)
+	}
+	wg.Wait()
+
+	var kept []PageResult
+	var all []*url.URL
+	for i, page := range pages {
+		if final := finals[i]; final != nil && final.String() != targets[i].url.String() {
+			if seen[final.String()] {
+				continue
+			}
+			seen[final.String()] = true
+		}
+		kept = append(kept, page)
+		all = append(all, links[i]...)
+	}
+	return kept, all
+}
+
+// crawlPage scans a page and returns its result, the URL it was fetched from
+// after redirects, and its links
+func (s *Scanner) crawlPage(u *url.URL) (PageResult, *url.URL, []*url.URL) {
+	page := PageResult{Path: u.Path, URL: u.String()}
+
+	fetched, err := s.fetch(u.String())
+	if err != nil {
+		page.Error = err.Error()
+		return page, nil, nil
+	}
+	if err := fetched.Check(); err != nil {
+		page.Error = err.Error()
+		return page, nil, nil
+	}
+	if mediaType, _, _ := mime.ParseMediaType(fetched.ContentType); mediaType != "" && mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		page.Error = fmt.Sprintf("not an HTML page (%s)", mediaType)
+		return page, nil, nil
+	}
+
+	doc, err := html.Parse(bytes.NewReader(fetched.Body))
+	if err != nil {
+		page.Error = fmt.Sprintf("failed to parse HTML: %v", err)
+		return page, nil, nil
+	}
+
+	// Resolve links and stylesheets against the page after redirects, as
+	// ScanURL does
+	base, err := url.Parse(fetched.URL)
+	if err != nil {
+		base = u
+	}
+	base.Fragment = ""
+	page.Result = s.scanDocument(doc, fetched.Body, base.String())
+	return page, base, extractLinks(doc, base)
+}
+
+// extractLinks returns the absolute http(s) URLs of the anchors in a document
+func extractLinks(doc *html.Node, base *url.URL) []*url.URL {
+	var links []*url.URL
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "base" {
+			for _, attr := range n.Attr {
+				if attr.Key == "href" {
+					if b, err := base.Parse(attr.Val); err == nil {
+						base = b
+					}
+				}
+			}
+		}
+		if n.Type == html.ElementNode && (n.Data == "a" || n.Data == "area") {
+			for _, attr := range n.Attr {
+				if attr.Key != "href" {
+					continue
+				}
+				link, err := base.Parse(strings.TrimSpace(attr.Val))
+				if err != nil || (link.Scheme != "http" && link.Scheme != "https") || !looksLikePage(link) {
+					continue
+				}
+				link.Fragment = ""
+				links = append(links, link)
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+	f(doc)
+	return links
+}
+
+// looksLikePage filters out links to obvious non-HTML resources
+func looksLikePage(u *url.URL) bool {
+	switch strings.ToLower(path.Ext(u.Path)) {
+	case ".pdf", ".zip", ".gz", ".png", ".jpg", ".jpeg", ".gif", ".svg", ".webp", ".ico",
+		".css", ".js", ".json", ".xml", ".txt", ".mp3", ".mp4", ".webm", ".woff", ".woff2",
+		".doc", ".docx", ".xls", ".xlsx", ".ppt", ".pptx":
+		return false
+	}
+	return true
+}
+
+func sameOrigin(a, b *url.URL) bool {
+	return a.Scheme == b.Scheme && strings.EqualFold(a.Host, b.Host)
+}
+
+// fetchRobots loads robots.txt. A missing file allows everything and a server
+// error disallows everything except the start page.
+func (s *Scanner) fetchRobots(start *url.URL) *robotsRules {
+	robotsURL := &url.URL{Scheme: start.Scheme, Host: start.Host, Path: "/robots.txt"}
+	fetched, err := s.fetch(robotsURL.String())
+	switch {
+	case err != nil || fetched.StatusCode >= 500:
+		return &robotsRules{disallowAll: true}
+	case fetched.StatusCode != http.StatusOK:
+		return &robotsRules{allowAll: true}
+	}
+	// Match the groups against the user agent the crawler actually sends
+	return parseRobots(fetched.Body, robotsToken(s.fetcher.UserAgent()))
+}
+
+type sitemapDocument struct {
+	XMLName  xml.Name
+	URLs     []sitemapLoc `xml:"url"`
+	Sitemaps []sitemapLoc `xml:"sitemap"`
+}
+
+type sitemapLoc struct {
+	Loc string `xml:"loc"`
+}
+
+// fetchSitemapURLs collects the same-origin page URLs listed in the site's
+// sitemaps, following sitemap indexes
+func (s *Scanner) fetchSitemapURLs(start *url.URL, robots *robotsRules) []*url.URL {
+	queue := append([]string{}, robots.sitemaps...)
+	if len(queue) == 0 {
+		queue = append(queue, (&url.URL{Scheme: start.Scheme, Host: start.Host, Path: "/sitemap.xml"}).String())
+	}
+
+	fetchedSitemaps := map[string]bool{}
+	var urls []*url.URL
+	for len(queue) > 0 && len(fetchedSitemaps) < maxSitemapFiles {
+		sitemapURL := queue[0]
+		queue = queue[1:]
+		if fetchedSitemaps[sitemapURL] {
+			continue
+		}
+		fetchedSitemaps[sitemapURL] = true
+
+		fetched, err := s.fetch(sitemapURL)
+		if err != nil || fetched.StatusCode != http.StatusOK {
+			continue
+		}
+		var doc sitemapDocument
+		if err := xml.Unmarshal(fetched.Body, &doc); err != nil {
+			continue
+		}
+		for _, sm := range doc.Sitemaps {
+			queue = append(queue, strings.TrimSpace(sm.Loc))
+		}
+		for _, entry := range doc.URLs {
+			u, err := url.Parse(strings.TrimSpace(entry.Loc))
+			if err != nil || !sameOrigin(start, u) {
+				continue
+			}
+			u.Fragment = ""
+			urls = append(urls, u)
+		}
+	}
+
+	sort.SliceStable(urls, func(i, j int) bool { return urls[i].String() < urls[j].String() })
+	return urls
+}
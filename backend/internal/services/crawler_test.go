@@ -0,0 +1,105 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCrawlMaxDepth(t *testing.T) {
+	// Every page links one level deeper, and the sitemap lists /sitemap-page
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			http.NotFound(w, r)
+		case "/sitemap.xml":
+			w.Header().Set("Content-Type", "application/xml")
+			fmt.Fprintf(w, `<urlset><url><loc>%s/sitemap-page</loc></url></urlset>`, server.URL)
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprintf(w, `<html lang="en"><title>Page</title><a href="%snext/">Next</a></html>`, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	depth := func(d int) *int { return &d }
+	tests := []struct {
+		name string
+		opts CrawlOptions
+		want int
+	}{
+		{"start page only", CrawlOptions{MaxDepth: depth(0), SkipSitemap: true}, 1},
+		{"start page and sitemap", CrawlOptions{MaxDepth: depth(0)}, 2},
+		{"one hop", CrawlOptions{MaxDepth: depth(1), SkipSitemap: true}, 2},
+		{"default depth", CrawlOptions{SkipSitemap: true}, DefaultCrawlMaxDepth + 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			site, err := NewScanner().CrawlSite(server.URL+"/", tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(site.Pages) != tt.want {
+				var paths []string
+				for _, page := range site.Pages {
+					paths = append(paths, page.Path)
+				}
+				t.Errorf("scanned %v, want %d pages", paths, tt.want)
+			}
+		})
+	}
+}
+
+func TestCrawlNegativeMaxDepth(t *testing.T) {
+	depth := -1
+	opts := CrawlOptions{MaxDepth: &depth}
+	if err := opts.Validate(); err == nil {
+		t.Error("expected an error for a negative max_depth")
+	}
+}
+
+func TestCrawlRedirectScannedOnce(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			http.NotFound(w, r)
+		case "/old":
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, `<html lang="en"><title>Home</title><a href="/old">Old</a></html>`)
+		case "/both":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, `<html lang="en"><title>Home</title><a href="/old">Old</a><a href="/new">New</a></html>`)
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, `<html lang="en"><title>Page</title><a href="/new">New</a></html>`)
+		}
+	}))
+	defer server.Close()
+
+	for _, tt := range []struct {
+		name  string
+		start string
+	}{
+		{"link to the target on the redirected page", "/"},
+		{"link to the target on the same level", "/both"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			site, err := NewScanner().CrawlSite(server.URL+tt.start, CrawlOptions{SkipSitemap: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var paths []string
+			for _, page := range site.Pages {
+				paths = append(paths, page.Path)
+			}
+			// The start page, and /new once whether reached through /old or not
+			if len(paths) != 2 {
+				t.Errorf("scanned %v, want the start page and /new once", paths)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"tokubetsu/internal/dom"
+
+	"golang.org/x/net/html"
+	"golang.org/x/text/language"
+)
+
+// Viewport zoom below this maximum scale prevents users from enlarging text
+const minViewportMaxScale = 2.0
+
+// Refresh delays of 20 hours or more are treated as not being a time limit
+const maxRefreshDelay = 72000
+
+// validLanguageTag reports whether a lang value is a well-formed BCP 47 tag
+// with a registered primary language
+func validLanguageTag(tag string) bool {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return false
+	}
+	_, err := language.Parse(tag)
+	return err == nil
+}
+
+// documentLang returns the lang or xml:lang attribute of the root element
+func documentLang(root *html.Node) (string, bool) {
+	if lang, ok := dom.LookupAttr(root, "lang"); ok {
+		return lang, true
+	}
+	return dom.LookupAttr(root, "xml:lang")
+}
+
+// checkHTMLLang checks that the page declares a valid language
+func checkHTMLLang(ctx *ScanContext, doc *html.Node, result *ScanResult) {
+	root := dom.Find(doc, "html")
+	if root == nil {
+		return
+	}
+
+	lang, ok := documentLang(root)
+	lang = strings.TrimSpace(lang)
+	switch {
+	case !ok || lang == "":
+		result.Violations = append(result.Violations, AccessibilityCheck{
+			ID:          "html-has-lang",
+			Impact:      "serious",
+			Description: "Page does not declare a language",
+			Help:        "The <html> element must have a lang attribute",
+			HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/html-has-lang",
+			Nodes:       []string{"<html>"},
+			Targets:     ctx.targets(root),
+		})
+	case !validLanguageTag(lang):
+		result.Violations = append(result.Violations, AccessibilityCheck{
+			ID:          "html-has-lang",
+			Impact:      "serious",
+			Description: "Page language is not a valid language tag",
+			Help:        fmt.Sprintf("The lang attribute of the <html> element must be a valid BCP 47 language tag, found %q", lang),
+			HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/html-lang-valid",
+			Nodes:       []string{fmt.Sprintf("<html lang=\"%s\">", lang)},
+			Targets:     ctx.targets(root),
+		})
+	default:
+		result.Passes = append(result.Passes, AccessibilityCheck{
+			ID:          "html-has-lang",
+			Description: fmt.Sprintf("Page declares a valid language (%s)", lang),
+			Nodes:       []string{fmt.Sprintf("<html lang=\"%s\">", lang)},
+			Targets:     ctx.targets(root),
+		})
+	}
+}
+
+// checkLanguageValid checks lang attributes on parts of the page
+func checkLanguageValid(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if n.Type == html.ElementNode && n.Data != "html" {
+		if lang := strings.TrimSpace(dom.Attr(n, "lang")); lang != "" {
+			if !validLanguageTag(lang) {
+				result.Violations = append(result.Violations, AccessibilityCheck{
+					ID:          "language-valid",
+					Impact:      "serious",
+					Description: "lang attribute is not a valid language tag",
+					Help:        fmt.Sprintf("lang attributes must be valid BCP 47 language tags, found %q", lang),
+					HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/valid-lang",
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			} else {
+				result.Passes = append(result.Passes, AccessibilityCheck{
+					ID:          "language-valid",
+					Description: "lang attribute is a valid language tag",
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			}
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkLanguageValid(ctx, c, result)
+	}
+}
+
+// checkDocumentTitle checks that the page has a non-empty title
+func checkDocumentTitle(ctx *ScanContext, doc *html.Node, result *ScanResult) {
+	title := dom.Find(doc, "title")
+	var text string
+	if title != nil {
+		for c := title.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.TextNode {
+				text += c.Data
+			}
+		}
+		text = strings.TrimSpace(text)
+	}
+
+	if text == "" {
+		target := title
+		if target == nil {
+			target = dom.Find(doc, "html")
+		}
+		result.Violations = append(result.Violations, AccessibilityCheck{
+			ID:          "document-title",
+			Impact:      "serious",
+			Description: "Page does not have a title",
+			Help:        "Documents must have a non-empty <title> element",
+			HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/document-title",
+			Nodes:       []string{"<title></title>"},
+			Targets:     ctx.targets(target),
+		})
+	} else {
+		result.Passes = append(result.Passes, AccessibilityCheck{
+			ID:          "document-title",
+			Description: "Page has a title",
+			Nodes:       []string{getNodeHTML(title)},
+			Targets:     ctx.targets(title),
+		})
+	}
+}
+
+// parseMetaContent splits a meta content value such as a viewport
+// declaration into lower-cased key/value pairs
+func parseMetaContent(content string) map[string]string {
+	values := make(map[string]string)
+	for _, part := range strings.FieldsFunc(content, func(r rune) bool { return r == ',' || r == ';' }) {
+		key, value, _ := strings.Cut(part, "=")
+		values[strings.ToLower(strings.TrimSpace(key))] = strings.ToLower(strings.TrimSpace(value))
+	}
+	return values
+}
+
+// checkMetaViewport checks that the viewport does not prevent zooming
+func checkMetaViewport(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if dom.IsElement(n, "meta") && strings.EqualFold(dom.Attr(n, "name"), "viewport") {
+		values := parseMetaContent(dom.Attr(n, "content"))
+
+		var problems []string
+		if scalable, ok := values["user-scalable"]; ok {
+			if scalable == "no" || scalable == "0" {
+				problems = append(problems, "user-scalable="+scalable)
+			}
+		}
+		if maxScale, ok := values["maximum-scale"]; ok {
+			if v, err := strconv.ParseFloat(maxScale, 64); err == nil && v >= 0 && v < minViewportMaxScale {
+				problems = append(problems, "maximum-scale="+maxScale)
+			}
+		}
+
+		if len(problems) > 0 {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "meta-viewport",
+				Impact:      "critical",
+				Description: "Viewport prevents zooming",
+				Help:        fmt.Sprintf("Zooming and scaling must not be disabled. Found %s", strings.Join(problems, ", ")),
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/meta-viewport",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		} else {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "meta-viewport",
+				Description: "Viewport allows zooming",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkMetaViewport(ctx, c, result)
+	}
+}
+
+// refreshDelay parses the delay of a meta refresh content value, e.g. "5; url=/next"
+func refreshDelay(content string) (int, bool) {
+	content = strings.TrimSpace(content)
+	end := 0
+	for end < len(content) && ((content[end] >= '0' && content[end] <= '9') || content[end] == '.') {
+		end++
+	}
+	if end == 0 {
+		return 0, false
+	}
+	delay, err := strconv.ParseFloat(content[:end], 64)
+	if err != nil {
+		return 0, false
+	}
+	return int(delay), true
+}
+
+// checkMetaRefresh checks that the page is not refreshed or redirected
+// after a delay the user cannot control
+func checkMetaRefresh(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if dom.IsElement(n, "meta") && strings.EqualFold(dom.Attr(n, "http-equiv"), "refresh") {
+		if delay, ok := refreshDelay(dom.Attr(n, "content")); ok {
+			// Immediate redirects and very long delays are not time limits
+			if delay > 0 && delay < maxRefreshDelay {
+				result.Violations = append(result.Violations, AccessibilityCheck{
+					ID:          "meta-refresh",
+					Impact:      "critical",
+					Description: "Page refreshes or redirects after a delay",
+					Help:        fmt.Sprintf("Timed refresh must not be used. The page refreshes after %d seconds", delay),
+					HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/meta-refresh",
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			} else {
+				result.Passes = append(result.Passes, AccessibilityCheck{
+					ID:          "meta-refresh",
+					Description: "Meta refresh does not impose a time limit",
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			}
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkMetaRefresh(ctx, c, result)
+	}
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"net/url"
+	"time"
+
+	"tokubetsu/internal/fetch"
+	"tokubetsu/internal/models"
+)
+
+// ProjectFetcher creates a fetch client with a project's fetch settings.
+// Its credentials are only sent to the host of the project URL.
+func ProjectFetcher(project *models.Project) *fetch.Client {
+	settings := project.FetchSettings
+	config := fetch.Config{
+		Timeout:      time.Duration(settings.TimeoutSeconds) * time.Second,
+		MaxRedirects: settings.MaxRedirects,
+		MaxBodySize:  int64(settings.MaxBodyMB) << 20,
+	}
+
+	u, err := url.Parse(project.URL)
+	if err != nil || u.Host == "" {
+		return fetch.New(config)
+	}
+	config.Credentials = &fetch.Credentials{
+		Host:    u.Host,
+		Headers: settings.Headers,
+		Cookies: settings.Cookies,
+	}
+	if settings.AuthType != "" {
+		config.Credentials.Auth = &fetch.Auth{
+			Type:     settings.AuthType,
+			Username: settings.Username,
+			Password: settings.Password,
+			Token:    settings.Token,
+		}
+	}
+	return fetch.New(config)
+}
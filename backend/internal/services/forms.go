@@ -0,0 +1,518 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"tokubetsu/internal/aria"
+	"tokubetsu/internal/dom"
+
+	"golang.org/x/net/html"
+)
+
+// Autocomplete field names of the HTML standard. Contact fields may be
+// preceded by a home, work, mobile, fax or pager token.
+var (
+	autocompleteFields = tokenSet(
+		"name", "honorific-prefix", "given-name", "additional-name", "family-name", "honorific-suffix",
+		"nickname", "username", "new-password", "current-password", "one-time-code", "organization-title",
+		"organization", "street-address", "address-line1", "address-line2", "address-line3",
+		"address-level4", "address-level3", "address-level2", "address-level1", "country", "country-name",
+		"postal-code", "cc-name", "cc-given-name", "cc-additional-name", "cc-family-name", "cc-number",
+		"cc-exp", "cc-exp-month", "cc-exp-year", "cc-csc", "cc-type", "transaction-currency",
+		"transaction-amount", "language", "bday", "bday-day", "bday-month", "bday-year", "sex", "url", "photo",
+	)
+	autocompleteContactFields = tokenSet(
+		"tel", "tel-country-code", "tel-national", "tel-area-code", "tel-local", "tel-local-prefix",
+		"tel-local-suffix", "tel-extension", "email", "impp",
+	)
+	autocompleteContactKinds = tokenSet("home", "work", "mobile", "fax", "pager")
+)
+
+// Field names and ids that suggest a field collects personal data, with the
+// autocomplete token expected for them. More specific patterns come first.
+var personalDataFields = []struct {
+	pattern *regexp.Regexp
+	token   string
+}{
+	{regexp.MustCompile(`e-?mail`), "email"},
+	{regexp.MustCompile(`phone|mobile|^tel$|telephone`), "tel"},
+	{regexp.MustCompile(`first-?name|given-?name|^fname$|forename`), "given-name"},
+	{regexp.MustCompile(`last-?name|family-?name|surname|^lname$`), "family-name"},
+	{regexp.MustCompile(`user-?name|login`), "username"},
+	{regexp.MustCompile(`full-?name|^name$|your-?name`), "name"},
+	{regexp.MustCompile(`card-?number|cc-?num`), "cc-number"},
+	{regexp.MustCompile(`post-?code|postal|^zip`), "postal-code"},
+	{regexp.MustCompile(`street|address`), "street-address"},
+	{regexp.MustCompile(`^city$|town`), "address-level2"},
+	{regexp.MustCompile(`country`), "country-name"},
+	{regexp.MustCompile(`birth|^dob$`), "bday"},
+	{regexp.MustCompile(`company|organi[sz]ation`), "organization"},
+}
+
+// Text that marks a field as required in its label
+var requiredMarker = regexp.MustCompile(`\*|\brequired\b|\bmandatory\b`)
+
+// Class and id tokens of elements that show validation errors
+var errorMarker = regexp.MustCompile(`(?i)error|invalid-feedback|validation-message`)
+
+func tokenSet(tokens ...string) map[string]bool {
+	set := make(map[string]bool, len(tokens))
+	for _, token := range tokens {
+		set[token] = true
+	}
+	return set
+}
+
+// isFormField reports whether an element is a form field users fill in
+func isFormField(n *html.Node) bool {
+	if dom.IsElement(n, "select", "textarea") {
+		return true
+	}
+	if !dom.IsElement(n, "input") {
+		return false
+	}
+	switch strings.ToLower(dom.Attr(n, "type")) {
+	case "hidden", "button", "submit", "reset", "image":
+		return false
+	}
+	return true
+}
+
+// validAutocomplete checks an autocomplete value against the grammar of the
+// HTML standard: [section-*] [shipping|billing] [contact kind] field [webauthn]
+func validAutocomplete(value string) error {
+	tokens := strings.Fields(strings.ToLower(value))
+	if len(tokens) == 1 && (tokens[0] == "on" || tokens[0] == "off") {
+		return nil
+	}
+	if len(tokens) > 0 && tokens[len(tokens)-1] == "webauthn" {
+		tokens = tokens[:len(tokens)-1]
+	}
+	if len(tokens) > 0 && strings.HasPrefix(tokens[0], "section-") {
+		tokens = tokens[1:]
+	}
+	if len(tokens) > 0 && (tokens[0] == "shipping" || tokens[0] == "billing") {
+		tokens = tokens[1:]
+	}
+	switch {
+	case len(tokens) == 0:
+		return fmt.Errorf("%q has no field name", value)
+	case len(tokens) == 1 && (autocompleteFields[tokens[0]] || autocompleteContactFields[tokens[0]]):
+		return nil
+	case len(tokens) == 2 && autocompleteContactKinds[tokens[0]] && autocompleteContactFields[tokens[1]]:
+		return nil
+	case len(tokens) == 2 && autocompleteContactKinds[tokens[0]]:
+		return fmt.Errorf("%q can only precede a contact field such as tel or email", tokens[0])
+	}
+	return fmt.Errorf("%q is not a valid autocomplete value", value)
+}
+
+// personalDataToken returns the autocomplete token a field that appears to
+// collect personal data should have, or "" when it does not appear to
+func personalDataToken(n *html.Node) string {
+	switch strings.ToLower(dom.Attr(n, "type")) {
+	case "email":
+		return "email"
+	case "tel":
+		return "tel"
+	case "password", "checkbox", "radio", "file", "range", "color", "search":
+		return ""
+	}
+	for _, attr := range []string{"name", "id"} {
+		key := strings.ToLower(strings.ReplaceAll(dom.Attr(n, attr), "_", "-"))
+		if key == "" {
+			continue
+		}
+		for _, field := range personalDataFields {
+			if field.pattern.MatchString(key) {
+				return field.token
+			}
+		}
+	}
+	return ""
+}
+
+// checkAutocomplete checks that autocomplete values are valid and that
+// fields collecting personal data identify their purpose (1.3.5)
+func checkAutocomplete(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if isFormField(n) && !ctx.Hidden(n) && !aria.Disabled(n) {
+		value, ok := dom.LookupAttr(n, "autocomplete")
+		value = strings.TrimSpace(value)
+		expected := personalDataToken(n)
+		purpose := ok && value != "" && !strings.EqualFold(value, "on") && !strings.EqualFold(value, "off")
+
+		switch {
+		case ok && value != "" && validAutocomplete(value) != nil:
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "autocomplete-valid",
+				Impact:      "serious",
+				Description: "autocomplete attribute is not valid",
+				Help:        fmt.Sprintf("The autocomplete attribute must use tokens of the HTML standard: %v", validAutocomplete(value)),
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/autocomplete-valid",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		case expected != "" && !purpose:
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "autocomplete-valid",
+				Impact:      "serious",
+				Description: "Personal data field does not identify its purpose",
+				Help:        fmt.Sprintf("Fields that collect information about the user must identify it with autocomplete, e.g. autocomplete=%q", expected),
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/autocomplete-valid",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		case purpose:
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "autocomplete-valid",
+				Description: fmt.Sprintf("Field identifies its purpose (%s)", value),
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkAutocomplete(ctx, c, result)
+	}
+}
+
+// groupContainer returns the element that groups a set of radio buttons or
+// checkboxes and names the group: a fieldset with a legend or an element with
+// role group or radiogroup and an accessible name
+func (ctx *ScanContext) groupContainer(n *html.Node) *html.Node {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if dom.IsElement(p, "fieldset") && aria.ExplicitRole(p) == "" {
+			if legend := dom.Find(p, "legend"); legend != nil && dom.Text(legend) != "" {
+				return p
+			}
+		}
+		if role := aria.ExplicitRole(p); (role == "group" || role == "radiogroup") && ctx.Name(p) != "" {
+			return p
+		}
+		if dom.IsElement(p, "form", "body") {
+			break
+		}
+	}
+	return nil
+}
+
+// checkFieldGroups checks that radio buttons and checkboxes sharing a name
+// are grouped with a fieldset and legend or a named group
+func checkFieldGroups(ctx *ScanContext, doc *html.Node, result *ScanResult) {
+	type key struct {
+		form       *html.Node
+		kind, name string
+	}
+	groups := make(map[key][]*html.Node)
+	var order []key
+	dom.Walk(doc, func(n *html.Node) bool {
+		if n.Type == html.ElementNode && ctx.Hidden(n) {
+			return false
+		}
+		if !dom.IsElement(n, "input") {
+			return true
+		}
+		kind := strings.ToLower(dom.Attr(n, "type"))
+		name := dom.Attr(n, "name")
+		if (kind != "radio" && kind != "checkbox") || name == "" {
+			return true
+		}
+		k := key{dom.Closest(n, "form"), kind, name}
+		if groups[k] == nil {
+			order = append(order, k)
+		}
+		groups[k] = append(groups[k], n)
+		return true
+	})
+
+	for _, k := range order {
+		inputs := groups[k]
+		if len(inputs) < 2 {
+			continue
+		}
+		container := ctx.groupContainer(inputs[0])
+		for _, input := range inputs[1:] {
+			if ctx.groupContainer(input) != container {
+				container = nil
+			}
+		}
+
+		kind := "radio buttons"
+		if k.kind == "checkbox" {
+			kind = "checkboxes"
+		}
+		if container == nil {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "radio-checkbox-group",
+				Impact:      "moderate",
+				Description: fmt.Sprintf("Related %s are not grouped", kind),
+				Help:        fmt.Sprintf("Group the %d %s named %q in a <fieldset> with a <legend>, or an element with role=\"group\" and a label", len(inputs), kind, k.name),
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/radiogroup",
+				Nodes:       []string{getNodeHTML(inputs[0])},
+				Targets:     ctx.targets(inputs[0]),
+			})
+		} else {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "radio-checkbox-group",
+				Description: fmt.Sprintf("Related %s are grouped", kind),
+				Nodes:       []string{getNodeHTML(container)},
+				Targets:     ctx.targets(container),
+			})
+		}
+	}
+}
+
+// normalizeLabel lowercases text and reduces it to letters and digits
+// separated by single spaces
+func normalizeLabel(text string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// checkLabelInName checks that controls named from their content keep their
+// visible text in an accessible name set by aria-label or aria-labelledby,
+// so speech users can activate them by what they see (2.5.3)
+func checkLabelInName(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if n.Type == html.ElementNode && !ctx.Hidden(n) &&
+		(dom.HasAttr(n, "aria-label") || dom.HasAttr(n, "aria-labelledby")) &&
+		aria.NameFromContent(aria.Role(n)) {
+		visible := normalizeLabel(dom.Text(n))
+		name := normalizeLabel(ctx.Name(n))
+		if visible != "" && name != "" {
+			if strings.Contains(" "+name+" ", " "+visible+" ") {
+				result.Passes = append(result.Passes, AccessibilityCheck{
+					ID:          "label-content-name-mismatch",
+					Description: "Accessible name contains the visible text",
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			} else {
+				result.Violations = append(result.Violations, AccessibilityCheck{
+					ID:          "label-content-name-mismatch",
+					Impact:      "serious",
+					Description: "Accessible name does not contain the visible text",
+					Help:        fmt.Sprintf("The accessible name %q must include the visible text %q", ctx.Name(n), dom.Text(n)),
+					HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/label-content-name-mismatch",
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			}
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkLabelInName(ctx, c, result)
+	}
+}
+
+// hasLabel reports whether a form field is labelled by something other than
+// its placeholder
+func (ctx *ScanContext) hasLabel(n *html.Node) bool {
+	if strings.TrimSpace(dom.Attr(n, "aria-label")) != "" || strings.TrimSpace(dom.Attr(n, "title")) != "" {
+		return true
+	}
+	for _, id := range strings.Fields(dom.Attr(n, "aria-labelledby")) {
+		if ref := ctx.accname().Lookup(id); ref != nil && dom.Text(ref) != "" {
+			return true
+		}
+	}
+	for _, label := range ctx.accname().Labels(n) {
+		if dom.Text(label) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPlaceholderLabel checks that fields with a placeholder also have a
+// label, since the placeholder disappears once the user types
+func checkPlaceholderLabel(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if isFormField(n) && !ctx.Hidden(n) && strings.TrimSpace(dom.Attr(n, "placeholder")) != "" {
+		if ctx.hasLabel(n) {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "placeholder-label",
+				Description: "Field with a placeholder also has a label",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		} else {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "placeholder-label",
+				Impact:      "serious",
+				Description: "Field is only labelled by its placeholder",
+				Help:        "Placeholders disappear when users type and are often low contrast. Add a visible <label> as well",
+				HelpURL:     "https://www.w3.org/WAI/tutorials/forms/instructions/#placeholder-text",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkPlaceholderLabel(ctx, c, result)
+	}
+}
+
+// isRequired reports whether a field is programmatically required
+func isRequired(n *html.Node) bool {
+	return dom.HasAttr(n, "required") || strings.EqualFold(strings.TrimSpace(dom.Attr(n, "aria-required")), "true")
+}
+
+// labelText returns the text of the labels of a field and the elements its
+// aria-labelledby refers to
+func (ctx *ScanContext) labelText(n *html.Node) string {
+	var parts []string
+	for _, label := range ctx.accname().Labels(n) {
+		parts = append(parts, dom.Text(label))
+	}
+	for _, id := range strings.Fields(dom.Attr(n, "aria-labelledby")) {
+		if ref := ctx.accname().Lookup(id); ref != nil {
+			parts = append(parts, dom.Text(ref))
+		}
+	}
+	parts = append(parts, dom.Attr(n, "aria-label"))
+	return strings.Join(parts, " ")
+}
+
+// checkRequiredFields checks that fields whose label marks them as required
+// are also required programmatically
+func checkRequiredFields(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if isFormField(n) && !ctx.Hidden(n) {
+		marked := requiredMarker.MatchString(strings.ToLower(ctx.labelText(n)))
+		switch {
+		case marked && !isRequired(n):
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "required-field",
+				Impact:      "serious",
+				Description: "Field is marked as required but not required programmatically",
+				Help:        "Fields whose label marks them as required must have the required or aria-required=\"true\" attribute",
+				HelpURL:     "https://www.w3.org/WAI/tutorials/forms/validation/#required-input",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		case isRequired(n):
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "required-field",
+				Description: "Required field is required programmatically",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkRequiredFields(ctx, c, result)
+	}
+}
+
+// isErrorMessage reports whether an element appears to show a validation error
+func (ctx *ScanContext) isErrorMessage(n *html.Node) bool {
+	if n.Type != html.ElementNode || ctx.Hidden(n) || dom.Text(n) == "" || isFormField(n) {
+		return false
+	}
+	if aria.ExplicitRole(n) == "alert" {
+		return true
+	}
+	return errorMarker.MatchString(dom.Attr(n, "class")) || errorMarker.MatchString(dom.Attr(n, "id"))
+}
+
+// nearbyErrors returns the error messages among the siblings of a field,
+// and of its label when the field sits inside one
+func (ctx *ScanContext) nearbyErrors(n *html.Node) []*html.Node {
+	parent := n.Parent
+	if parent != nil && dom.IsElement(parent, "label") {
+		parent = parent.Parent
+	}
+	if parent == nil {
+		return nil
+	}
+	var errors []*html.Node
+	for c := parent.FirstChild; c != nil; c = c.NextSibling {
+		if c != n && ctx.isErrorMessage(c) {
+			errors = append(errors, c)
+		}
+	}
+	return errors
+}
+
+// checkErrorMessages checks that invalid fields, and fields shown next to an
+// error message, refer to the message with aria-describedby or
+// aria-errormessage so screen readers announce it with the field
+func checkErrorMessages(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if isFormField(n) && !ctx.Hidden(n) {
+		invalid := dom.HasAttr(n, "aria-invalid") && !strings.EqualFold(strings.TrimSpace(dom.Attr(n, "aria-invalid")), "false")
+		nearby := ctx.nearbyErrors(n)
+
+		if invalid || len(nearby) > 0 {
+			associated := false
+			for _, attr := range []string{"aria-describedby", "aria-errormessage"} {
+				for _, id := range strings.Fields(dom.Attr(n, attr)) {
+					if ref := ctx.accname().Lookup(id); ref != nil && dom.Text(ref) != "" {
+						associated = true
+					}
+				}
+			}
+
+			if associated {
+				result.Passes = append(result.Passes, AccessibilityCheck{
+					ID:          "error-message",
+					Description: "Field refers to its error message",
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			} else {
+				help := "Invalid fields must refer to their error message with aria-describedby or aria-errormessage"
+				if len(nearby) > 0 {
+					help = fmt.Sprintf("Refer to the error message %q from the field with aria-describedby or aria-errormessage", dom.Text(nearby[0]))
+				}
+				result.Violations = append(result.Violations, AccessibilityCheck{
+					ID:          "error-message",
+					Impact:      "serious",
+					Description: "Error message is not associated with its field",
+					Help:        help,
+					HelpURL:     "https://www.w3.org/WAI/tutorials/forms/notifications/#on-focus-change",
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			}
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkErrorMessages(ctx, c, result)
+	}
+}
+
+// checkButtonNames checks that buttons have an accessible name. Input
+// buttons are covered by input-button-name.
+func checkButtonNames(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if n.Type == html.ElementNode && !dom.IsElement(n, "input") && aria.Role(n) == "button" && !ctx.Hidden(n) {
+		if ctx.Name(n) == "" {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "button-name",
+				Impact:      "critical",
+				Description: "Button does not have discernible text",
+				Help:        "Buttons must have text content, aria-label or aria-labelledby",
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/button-name",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		} else {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "button-name",
+				Description: "Button has discernible text",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkButtonNames(ctx, c, result)
+	}
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"tokubetsu/internal/aria"
+	"tokubetsu/internal/dom"
+
+	"golang.org/x/net/html"
+)
+
+// TabStop is one element in the sequential keyboard navigation order
+type TabStop struct {
+	Position int          `json:"position"` // 1-based position in the tab order
+	TabIndex int          `json:"tabIndex"`
+	Element  string       `json:"element"`
+	Role     string       `json:"role,omitempty"`
+	Name     string       `json:"name,omitempty"`
+	Target   dom.Location `json:"target"`
+}
+
+// inert reports whether an element is inside an inert subtree
+func inert(n *html.Node) bool {
+	for e := n; e != nil; e = e.Parent {
+		if e.Type == html.ElementNode && dom.HasAttr(e, "inert") {
+			return true
+		}
+	}
+	return false
+}
+
+// tabbableElements returns the elements a keyboard user can tab to, in
+// document order. Only the checked radio button of a group, or the first
+// when none is checked, takes part in the tab order.
+func (ctx *ScanContext) tabbableElements() []*html.Node {
+	var elements []*html.Node
+	radios := make(map[string]*html.Node)
+	dom.Walk(ctx.Doc, func(n *html.Node) bool {
+		if n.Type != html.ElementNode {
+			return true
+		}
+		if ctx.Styles.Hidden(n) || inert(n) {
+			return false
+		}
+		if !aria.Tabbable(n) {
+			return true
+		}
+
+		if dom.IsElement(n, "input") && strings.EqualFold(dom.Attr(n, "type"), "radio") && dom.Attr(n, "name") != "" {
+			group := dom.Attr(n, "name")
+			if first, seen := radios[group]; seen {
+				if dom.HasAttr(n, "checked") && !dom.HasAttr(first, "checked") {
+					for i, e := range elements {
+						if e == first {
+							elements[i] = n
+						}
+					}
+					radios[group] = n
+				}
+				return true
+			}
+			radios[group] = n
+		}
+
+		elements = append(elements, n)
+		return true
+	})
+	return elements
+}
+
+// TabOrder returns the sequential focus navigation order of the document:
+// elements with a positive tabindex in ascending order, then the remaining
+// focusable elements in document order
+func (ctx *ScanContext) TabOrder() []TabStop {
+	elements := ctx.tabbableElements()
+	sort.SliceStable(elements, func(i, j int) bool {
+		a, _ := aria.TabIndex(elements[i])
+		b, _ := aria.TabIndex(elements[j])
+		if a > 0 && b > 0 {
+			return a < b
+		}
+		return a > 0 && b <= 0
+	})
+
+	order := make([]TabStop, 0, len(elements))
+	for i, n := range elements {
+		index, _ := aria.TabIndex(n)
+		order = append(order, TabStop{
+			Position: i + 1,
+			TabIndex: index,
+			Element:  n.Data,
+			Role:     aria.Role(n),
+			Name:     ctx.Name(n),
+			Target:   ctx.Locate(n),
+		})
+	}
+	return order
+}
+
+// checkTabIndex checks that tabindex values do not override the natural tab order
+func checkTabIndex(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if index, ok := aria.TabIndex(n); ok && n.Type == html.ElementNode {
+		if index > 0 {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "tabindex",
+				Impact:      "serious",
+				Description: "Element has a positive tabindex",
+				Help:        fmt.Sprintf("tabindex=\"%d\" moves the element ahead of the natural tab order. Use tabindex=\"0\" or -1 and order the markup instead", index),
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/tabindex",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		} else {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "tabindex",
+				Description: "Element does not have a positive tabindex",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkTabIndex(ctx, c, result)
+	}
+}
+
+// nativelyInteractive reports whether an element handles keyboard
+// activation by itself
+func nativelyInteractive(n *html.Node) bool {
+	switch n.Data {
+	case "a", "area":
+		return dom.HasAttr(n, "href")
+	case "button", "input", "select", "textarea", "summary", "option", "label":
+		return true
+	case "html", "body":
+		// Handlers on the document root are usually event delegation
+		return true
+	}
+	return false
+}
+
+// checkClickHandlers checks that elements with click handlers can be
+// reached and identified by keyboard users
+func checkClickHandlers(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if dom.HasAttr(n, "onclick") && n.Type == html.ElementNode && !nativelyInteractive(n) && !ctx.Hidden(n) {
+		var problems []string
+		if !aria.Focusable(n) {
+			problems = append(problems, "is not focusable")
+		}
+		if aria.ExplicitRole(n) == "" {
+			problems = append(problems, "has no role")
+		}
+
+		if len(problems) > 0 {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "click-handler-keyboard",
+				Impact:      "serious",
+				Description: "Element with a click handler is not keyboard accessible",
+				Help:        fmt.Sprintf("Elements with click handlers must be focusable and have a role, or be replaced by a button or link. This <%s> %s", n.Data, strings.Join(problems, " and ")),
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/focus-order-semantics",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		} else {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "click-handler-keyboard",
+				Description: "Element with a click handler is focusable and has a role",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkClickHandlers(ctx, c, result)
+	}
+}
+
+// checkAccessKeys checks that accesskey values are unique in the document
+func checkAccessKeys(ctx *ScanContext, doc *html.Node, result *ScanResult) {
+	keys := make(map[string][]*html.Node)
+	var order []string
+	dom.Walk(doc, func(n *html.Node) bool {
+		if key := strings.ToLower(strings.TrimSpace(dom.Attr(n, "accesskey"))); key != "" && n.Type == html.ElementNode {
+			if _, seen := keys[key]; !seen {
+				order = append(order, key)
+			}
+			keys[key] = append(keys[key], n)
+		}
+		return true
+	})
+
+	for _, key := range order {
+		elements := keys[key]
+		if len(elements) > 1 {
+			nodes := make([]string, 0, len(elements))
+			for _, e := range elements {
+				nodes = append(nodes, getNodeHTML(e))
+			}
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "accesskeys",
+				Impact:      "serious",
+				Description: "accesskey value is not unique",
+				Help:        fmt.Sprintf("accesskey %q is used by %d elements; each access key must be unique", key, len(elements)),
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/accesskeys",
+				Nodes:       nodes,
+				Targets:     ctx.targets(elements...),
+			})
+		} else {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "accesskeys",
+				Description: "accesskey value is unique",
+				Nodes:       []string{getNodeHTML(elements[0])},
+				Targets:     ctx.targets(elements[0]),
+			})
+		}
+	}
+}
+
+// isSkipLink reports whether a link jumps to an element on the same page
+func (ctx *ScanContext) isSkipLink(n *html.Node) bool {
+	href := strings.TrimSpace(dom.Attr(n, "href"))
+	if !strings.HasPrefix(href, "#") || len(href) < 2 {
+		return false
+	}
+	target := href[1:]
+	if ctx.accname().Lookup(target) != nil {
+		return true
+	}
+	// Legacy named anchors are valid targets too
+	var found bool
+	dom.Walk(ctx.Doc, func(e *html.Node) bool {
+		if found {
+			return false
+		}
+		if dom.IsElement(e, "a") && dom.Attr(e, "name") == target {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// checkSkipLink checks that a page with repeated navigation lets keyboard
+// users skip past it: a same-page link must come before the first
+// navigation block in the tab order, or be the first link inside it
+func checkSkipLink(ctx *ScanContext, doc *html.Node, result *ScanResult) {
+	var nav *html.Node
+	dom.Walk(doc, func(n *html.Node) bool {
+		if nav != nil {
+			return false
+		}
+		if n.Type == html.ElementNode && aria.Role(n) == "navigation" && !ctx.Hidden(n) {
+			nav = n
+			return false
+		}
+		return true
+	})
+	if nav == nil {
+		return
+	}
+
+	var skip *html.Node
+	for _, n := range ctx.tabbableElements() {
+		if contains(nav, n) {
+			// A skip link is often the first link of the navigation itself
+			if dom.IsElement(n, "a") && ctx.isSkipLink(n) {
+				skip = n
+			}
+			break
+		}
+		if dom.IsElement(n, "a") && ctx.isSkipLink(n) {
+			skip = n
+			break
+		}
+	}
+
+	if skip == nil {
+		result.Violations = append(result.Violations, AccessibilityCheck{
+			ID:          "skip-link",
+			Impact:      "serious",
+			Description: "Page has no skip link before its navigation",
+			Help:        "Provide a link before repeated navigation that jumps to the main content, e.g. <a href=\"#main\">Skip to content</a>",
+			HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/bypass",
+			Nodes:       []string{getNodeHTML(nav)},
+			Targets:     ctx.targets(nav),
+		})
+	} else {
+		result.Passes = append(result.Passes, AccessibilityCheck{
+			ID:          "skip-link",
+			Description: "Page has a skip link before its navigation",
+			Nodes:       []string{getNodeHTML(skip)},
+			Targets:     ctx.targets(skip),
+		})
+	}
+}
+
+// contains reports whether n is ancestor or one of its descendants
+func contains(ancestor, n *html.Node) bool {
+	for p := n; p != nil; p = p.Parent {
+		if p == ancestor {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSkipLink(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		pass bool
+	}{
+		{"before navigation", `<a href="#main">Skip to content</a><nav><a href="/">Home</a></nav>`, true},
+		{"first link of navigation", `<nav><a href="#main">Skip to content</a><a href="/">Home</a></nav>`, true},
+		{"inside banner before navigation", `<header><a href="/">Logo</a><a href="#main">Skip</a><nav><a href="/a">A</a></nav></header>`, true},
+		{"later link of navigation", `<nav><a href="/">Home</a><a href="#main">Skip to content</a></nav>`, false},
+		{"missing target", `<nav><a href="#content">Skip to content</a><a href="/">Home</a></nav>`, false},
+		{"none", `<nav><a href="/">Home</a></nav>`, false},
+	}
+	for _, tt := range tests {
+		page := `<html lang="en"><body>` + tt.body + `<main id="main"><p>Content</p></main></body></html>`
+		result, err := NewScanner().ScanHTML(strings.NewReader(page), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		passed, flagged := false, false
+		for _, p := range result.Passes {
+			passed = passed || p.ID == "skip-link"
+		}
+		for _, v := range result.Violations {
+			flagged = flagged || v.ID == "skip-link"
+		}
+		if passed != tt.pass || flagged == tt.pass {
+			t.Errorf("%s: skip-link passed = %t, violated = %t, want pass %t", tt.name, passed, flagged, tt.pass)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"tokubetsu/internal/aria"
+	"tokubetsu/internal/dom"
+
+	"golang.org/x/net/html"
+)
+
+// checkVideoCaptions checks that videos have a captions track
+func checkVideoCaptions(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if dom.IsElement(n, "video") && !ctx.Hidden(n) {
+		var hasCaptions bool
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if dom.IsElement(c, "track") && strings.EqualFold(dom.Attr(c, "kind"), "captions") {
+				hasCaptions = true
+				break
+			}
+		}
+
+		if !hasCaptions {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "video-caption",
+				Impact:      "critical",
+				Description: "Video does not have captions",
+				Help:        "Video elements must have a <track kind=\"captions\"> for their audio content",
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/video-caption",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		} else {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "video-caption",
+				Description: "Video has a captions track",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkVideoCaptions(ctx, c, result)
+	}
+}
+
+// mentionsTranscript reports whether an element's text or link target
+// refers to a transcript
+func mentionsTranscript(n *html.Node) bool {
+	return strings.Contains(strings.ToLower(dom.Text(n)), "transcript") ||
+		strings.Contains(strings.ToLower(dom.Attr(n, "href")), "transcript")
+}
+
+// hasTranscript looks for a transcript of an audio element: content it is
+// described by, or a link mentioning a transcript next to it
+func (ctx *ScanContext) hasTranscript(n *html.Node) bool {
+	for _, id := range strings.Fields(dom.Attr(n, "aria-describedby") + " " + dom.Attr(n, "aria-details")) {
+		if ref := ctx.accname().Lookup(id); ref != nil && dom.Text(ref) != "" {
+			return true
+		}
+	}
+
+	// Search the audio element's container, up to two levels up
+	container := n.Parent
+	for i := 0; i < 2 && container != nil && !dom.IsElement(container, "body"); i++ {
+		var found bool
+		dom.Walk(container, func(e *html.Node) bool {
+			if found {
+				return false
+			}
+			if dom.IsElement(e, "a") && mentionsTranscript(e) {
+				found = true
+			}
+			return !found
+		})
+		if found {
+			return true
+		}
+		container = container.Parent
+	}
+	return false
+}
+
+// checkAudioTranscript checks that audio content has a transcript
+func checkAudioTranscript(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if dom.IsElement(n, "audio") && !ctx.Hidden(n) {
+		if !ctx.hasTranscript(n) {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "audio-transcript",
+				Impact:      "critical",
+				Description: "Audio does not have a transcript",
+				Help:        "Provide a text transcript for audio content and link to it next to the audio element",
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/audio-caption",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		} else {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "audio-transcript",
+				Description: "Audio has a transcript",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkAudioTranscript(ctx, c, result)
+	}
+}
+
+// checkAutoplay checks that media which plays automatically can be stopped
+func checkAutoplay(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if dom.IsElement(n, "audio", "video") && dom.HasAttr(n, "autoplay") {
+		// Muted media makes no sound that could interfere with screen readers
+		if !dom.HasAttr(n, "controls") && !dom.HasAttr(n, "muted") {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "no-autoplay-audio",
+				Impact:      "moderate",
+				Description: "Media plays automatically without controls",
+				Help:        fmt.Sprintf("Autoplaying <%s> elements must be muted or provide controls to pause or stop them", n.Data),
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/no-autoplay-audio",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		} else {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "no-autoplay-audio",
+				Description: "Autoplaying media is muted or has controls",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkAutoplay(ctx, c, result)
+	}
+}
+
+// checkFrameTitles checks that frames have an accessible name
+func checkFrameTitles(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if dom.IsElement(n, "iframe", "frame") && !ctx.Hidden(n) {
+		if ctx.Name(n) == "" {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "frame-title",
+				Impact:      "serious",
+				Description: "Frame does not have a title",
+				Help:        fmt.Sprintf("<%s> elements must have a title attribute that describes their content", n.Data),
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/frame-title",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		} else {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "frame-title",
+				Description: "Frame has a title",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkFrameTitles(ctx, c, result)
+	}
+}
+
+// checkObjectAlt checks that embedded objects have a text alternative
+func checkObjectAlt(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if dom.IsElement(n, "object", "embed") && !ctx.Hidden(n) && !aria.IsPresentational(n) {
+		alt := ctx.Name(n)
+		// An object's fallback content is its text alternative
+		if alt == "" && n.Data == "object" {
+			alt = dom.Text(n)
+		}
+
+		if alt == "" {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "object-alt",
+				Impact:      "serious",
+				Description: "Embedded content does not have alternative text",
+				Help:        fmt.Sprintf("<%s> elements must have a text alternative: fallback content, aria-label, aria-labelledby or title", n.Data),
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/object-alt",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		} else {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "object-alt",
+				Description: "Embedded content has alternative text",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkObjectAlt(ctx, c, result)
+	}
+}
+
+// checkAreaAlt checks that image map areas have alternative text
+func checkAreaAlt(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if dom.IsElement(n, "area") && dom.HasAttr(n, "href") && !ctx.Hidden(n) {
+		if ctx.Name(n) == "" {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "area-alt",
+				Impact:      "critical",
+				Description: "Image map area does not have alternative text",
+				Help:        "Active <area> elements must have an alt attribute describing the link destination",
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/area-alt",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		} else {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "area-alt",
+				Description: "Image map area has alternative text",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkAreaAlt(ctx, c, result)
+	}
+}
+
+// checkInputImageAlt checks that image buttons have alternative text
+func checkInputImageAlt(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if dom.IsElement(n, "input") && strings.EqualFold(dom.Attr(n, "type"), "image") && !ctx.Hidden(n) {
+		if ctx.Name(n) == "" {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "input-image-alt",
+				Impact:      "critical",
+				Description: "Image button does not have alternative text",
+				Help:        "<input type=\"image\"> elements must have an alt attribute describing their action",
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/input-image-alt",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		} else {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "input-image-alt",
+				Description: "Image button has alternative text",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkInputImageAlt(ctx, c, result)
+	}
+}
+
+// inNamedControl reports whether an element sits inside a link, button or
+// other control whose descendants are presentational, so it needs no name
+// of its own
+func inNamedControl(n *html.Node) bool {
+	for p := n.Parent; p != nil && p.Type == html.ElementNode; p = p.Parent {
+		role := aria.Role(p)
+		if role == "link" || aria.ChildrenPresentational(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSVGAlt checks that graphics exposed to assistive technologies have
+// an accessible name
+func checkSVGAlt(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if dom.IsElement(n, "svg") && n.Namespace == "svg" {
+		if !ctx.Hidden(n) && !aria.IsPresentational(n) && !inNamedControl(n) {
+			if ctx.Name(n) == "" {
+				result.Violations = append(result.Violations, AccessibilityCheck{
+					ID:          "svg-img-alt",
+					Impact:      "serious",
+					Description: "SVG graphic does not have an accessible name",
+					Help:        "Provide a <title> child, aria-label or aria-labelledby for meaningful SVGs, or hide decorative ones with aria-hidden=\"true\"",
+					HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/svg-img-alt",
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			} else {
+				result.Passes = append(result.Passes, AccessibilityCheck{
+					ID:          "svg-img-alt",
+					Description: "SVG graphic has an accessible name",
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			}
+		}
+		// Nested svg elements are part of the outer graphic
+		return
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkSVGAlt(ctx, c, result)
+	}
+}
+
+// checkBlink checks for blinking text, which cannot be paused
+func checkBlink(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if dom.IsElement(n, "blink") {
+		result.Violations = append(result.Violations, AccessibilityCheck{
+			ID:          "blink",
+			Impact:      "serious",
+			Description: "Page uses the <blink> element",
+			Help:        "<blink> elements are deprecated and must not be used; blinking content cannot be paused by the user",
+			HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/blink",
+			Nodes:       []string{getNodeHTML(n)},
+			Targets:     ctx.targets(n),
+		})
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkBlink(ctx, c, result)
+	}
+}
+
+// checkMarquee checks for scrolling marquee text, which cannot be paused
+func checkMarquee(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if dom.IsElement(n, "marquee") {
+		result.Violations = append(result.Violations, AccessibilityCheck{
+			ID:          "marquee",
+			Impact:      "serious",
+			Description: "Page uses the <marquee> element",
+			Help:        "<marquee> elements are deprecated and must not be used; moving content cannot be paused by the user",
+			HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/marquee",
+			Nodes:       []string{getNodeHTML(n)},
+			Targets:     ctx.targets(n),
+		})
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkMarquee(ctx, c, result)
+	}
+}
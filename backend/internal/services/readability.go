@@ -0,0 +1,472 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"tokubetsu/internal/aria"
+	"tokubetsu/internal/dom"
+	"tokubetsu/internal/readability"
+
+	"golang.org/x/net/html"
+)
+
+// Sections with fewer words are too short for a meaningful reading level
+const minReadingWords = 100
+
+// Readability is the text analysis of a page. Only visible text in the
+// page's language is analyzed; navigation, form controls and code are not
+// prose and are left out.
+type Readability struct {
+	Lang     string               `json:"lang"`
+	Metrics  readability.Metrics  `json:"metrics"`
+	Sections []ReadabilitySection `json:"sections"`
+	// Abbreviations used without an expansion anywhere on the page
+	Abbreviations []string `json:"abbreviations"`
+	// Links whose text does not describe where they lead
+	AmbiguousLinks []AmbiguousLink `json:"ambiguousLinks"`
+
+	abbreviations []abbreviationUse
+}
+
+// ReadabilitySection is the text from one heading to the next. Text before
+// the first heading forms a section without a heading.
+type ReadabilitySection struct {
+	Heading string              `json:"heading,omitempty"`
+	Level   int                 `json:"level,omitempty"`
+	Target  *dom.Location       `json:"target,omitempty"`
+	Metrics readability.Metrics `json:"metrics"`
+
+	node *html.Node
+}
+
+// AmbiguousLink is a link text that does not describe the link's purpose on
+// its own: generic text, or the same text leading to different places
+type AmbiguousLink struct {
+	Text    string         `json:"text"`
+	Reason  string         `json:"reason"` // "generic" or "different-destinations"
+	Targets []dom.Location `json:"targets"`
+}
+
+// abbreviationUse is the first use of an abbreviation on a page
+type abbreviationUse struct {
+	text     string
+	node     *html.Node
+	expanded bool
+}
+
+// Link texts that say nothing about the link's destination
+var genericLinkText = tokenSet(
+	"click here", "click", "here", "this", "this link", "link", "more", "read more", "learn more",
+	"find out more", "see more", "more info", "more information", "details", "continue", "go", "download",
+	"hier", "mehr", "weiterlesen", "ici", "en savoir plus", "lire la suite", "aquí", "más", "leer más",
+	"meer", "lees meer",
+)
+
+// Elements whose text is not prose
+var nonProseElements = tokenSet(
+	"head", "script", "style", "noscript", "template", "svg", "math", "nav",
+	"button", "select", "option", "textarea", "code", "pre", "kbd", "samp",
+)
+
+// Elements that flow with the surrounding text rather than start a new
+// block, including void and replaced elements such as an icon in a sentence
+var inlineElements = tokenSet(
+	"a", "abbr", "acronym", "b", "bdi", "bdo", "cite", "data", "del", "dfn", "em", "i", "ins", "label",
+	"mark", "q", "s", "small", "span", "strong", "sub", "sup", "time", "u", "var",
+	"br", "wbr", "img", "input", "picture", "source", "embed", "canvas", "meter", "progress",
+)
+
+// Candidate abbreviations: two or more capitals, optionally with digits and a
+// plural s, e.g. WCAG, HTML5 or APIs
+var abbreviationPattern = regexp.MustCompile(`\b([A-Z][A-Z0-9]{1,6})s?\b`)
+
+var romanNumeral = regexp.MustCompile(`^[IVXLCDM]+$`)
+
+// commonAbbreviations are understood by most readers without an expansion,
+// or are ordinary words in capitals
+var commonAbbreviations = tokenSet(
+	"AM", "PM", "OK", "ID", "TV", "PC", "CD", "DVD", "USB", "GPS", "ATM", "PIN", "FAQ", "DIY", "ASAP", "FYI",
+	"US", "USA", "UK", "EU", "UN", "NATO", "NASA", "CEO", "HR", "IT", "AI",
+	"PDF", "URL", "WWW", "HTML", "CSS", "HTTP", "HTTPS", "API", "SMS", "VAT",
+)
+
+// singular strips the plural s of an abbreviation such as APIs, but not the
+// final letter of one such as CSS
+func singular(abbreviation string) string {
+	stem, ok := strings.CutSuffix(abbreviation, "s")
+	if !ok || len(stem) < 2 || strings.ToUpper(stem) != stem {
+		return abbreviation
+	}
+	return stem
+}
+
+// Readability analyzes the visible text of the document
+func (ctx *ScanContext) Readability() *Readability {
+	if ctx.readability != nil {
+		return ctx.readability
+	}
+
+	r := &Readability{Abbreviations: make([]string, 0), AmbiguousLinks: make([]AmbiguousLink, 0)}
+	if root := dom.Find(ctx.Doc, "html"); root != nil {
+		lang, _ := documentLang(root)
+		r.Lang = strings.TrimSpace(lang)
+	}
+
+	t := &textCollector{ctx: ctx, lang: readability.Primary(r.Lang), expanded: make(map[string]bool), seen: make(map[string]bool)}
+	t.sections = []*collectedSection{{}}
+	t.collect(ctx.Doc)
+	t.flush()
+
+	r.Sections = make([]ReadabilitySection, 0, len(t.sections))
+	var page strings.Builder
+	for _, collected := range t.sections {
+		text := collected.text.String()
+		section := collected.section
+		section.Metrics = readability.Analyze(text, r.Lang)
+		if section.Metrics.Words == 0 {
+			continue
+		}
+		page.WriteString(text)
+		r.Sections = append(r.Sections, section)
+	}
+	r.Metrics = readability.Analyze(page.String(), r.Lang)
+
+	all := t.all.String()
+	for _, use := range t.abbreviations {
+		use.expanded = t.expanded[use.text] ||
+			strings.Contains(all, "("+use.text+")") || strings.Contains(all, use.text+" (")
+		if !use.expanded {
+			r.Abbreviations = append(r.Abbreviations, use.text)
+		}
+		r.abbreviations = append(r.abbreviations, use)
+	}
+
+	for _, group := range ctx.linkGroups() {
+		switch {
+		case genericLinkText[group.key]:
+			r.AmbiguousLinks = append(r.AmbiguousLinks, AmbiguousLink{Text: group.text, Reason: "generic", Targets: ctx.targets(group.links...)})
+		case len(group.destinations) > 1:
+			r.AmbiguousLinks = append(r.AmbiguousLinks, AmbiguousLink{Text: group.text, Reason: "different-destinations", Targets: ctx.targets(group.links...)})
+		}
+	}
+
+	ctx.readability = r
+	return r
+}
+
+// textCollector gathers the prose of a document into sections of sentences
+type textCollector struct {
+	ctx      *ScanContext
+	lang     string // Primary language of the page
+	sections []*collectedSection
+	block    strings.Builder
+	all      strings.Builder // All visible text, for finding expansions
+
+	abbreviations []abbreviationUse
+	expanded      map[string]bool
+	seen          map[string]bool
+}
+
+// collectedSection is a section and its text so far
+type collectedSection struct {
+	section ReadabilitySection
+	text    strings.Builder
+}
+
+func (t *textCollector) collect(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		t.block.WriteString(n.Data)
+		t.all.WriteString(n.Data + " ")
+		t.findAbbreviations(n.Data, n.Parent)
+		return
+	case html.ElementNode:
+		if nonProseElements[n.Data] || t.ctx.Styles.Hidden(n) || aria.ExplicitRole(n) == "navigation" {
+			return
+		}
+		// Text in another language is measured against the wrong formula
+		if lang, ok := dom.LookupAttr(n, "lang"); ok && t.lang != "" && readability.Primary(lang) != t.lang {
+			return
+		}
+		if level := headingLevel(n); level > 0 {
+			t.flush()
+			location := t.ctx.Locate(n)
+			t.sections = append(t.sections, &collectedSection{
+				section: ReadabilitySection{Heading: dom.Text(n), Level: level, Target: &location, node: n},
+			})
+			t.all.WriteString(dom.Text(n) + " ")
+			t.findAbbreviations(dom.Text(n), n)
+			return
+		}
+		// A line break separates words but not sentences
+		if n.Data == "br" {
+			t.block.WriteString(" ")
+		}
+		if dom.IsElement(n, "abbr", "acronym", "dfn") && (strings.TrimSpace(dom.Attr(n, "title")) != "" || n.Data == "dfn") {
+			t.expanded[singular(dom.Text(n))] = true
+			t.expanded[dom.Text(n)] = true
+		}
+	}
+
+	block := n.Type == html.ElementNode && !inlineElements[n.Data]
+	if block {
+		t.flush()
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		t.collect(c)
+	}
+	if block {
+		t.flush()
+	}
+}
+
+// flush ends the current block of text. A block without terminal
+// punctuation, such as a list item, counts as a sentence.
+func (t *textCollector) flush() {
+	text := strings.Join(strings.Fields(t.block.String()), " ")
+	t.block.Reset()
+	if text == "" {
+		return
+	}
+	if last := []rune(text)[len([]rune(text))-1]; !strings.ContainsRune(".!?…:;。！？", last) {
+		text += "."
+	}
+	t.sections[len(t.sections)-1].text.WriteString(text + " ")
+}
+
+// findAbbreviations records the abbreviations used in text for the first
+// time. Text mostly in capitals is shouting rather than abbreviations.
+func (t *textCollector) findAbbreviations(text string, parent *html.Node) {
+	letters, capitals := 0, 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				capitals++
+			}
+		}
+	}
+	if letters == 0 || (letters > 8 && capitals*2 > letters) {
+		return
+	}
+	for _, match := range abbreviationPattern.FindAllStringSubmatch(text, -1) {
+		abbreviation := match[1]
+		upper := 0
+		for _, r := range abbreviation {
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+		if upper < 2 || romanNumeral.MatchString(abbreviation) || commonAbbreviations[abbreviation] || t.seen[abbreviation] {
+			continue
+		}
+		t.seen[abbreviation] = true
+		t.abbreviations = append(t.abbreviations, abbreviationUse{text: abbreviation, node: parent})
+	}
+}
+
+// linkGroup is the links of a page that share the same text
+type linkGroup struct {
+	key          string // Normalized link text
+	text         string
+	links        []*html.Node
+	destinations []string
+}
+
+// linkDestination returns the URL a link leads to, resolved against the page
+func (ctx *ScanContext) linkDestination(n *html.Node) string {
+	href := strings.TrimSpace(dom.Attr(n, "href"))
+	base, err := url.Parse(ctx.URL)
+	if err != nil {
+		return href
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	return base.ResolveReference(ref).String()
+}
+
+// linkGroups groups the visible links of the document by their accessible
+// name, in document order
+func (ctx *ScanContext) linkGroups() []*linkGroup {
+	var groups []*linkGroup
+	byKey := make(map[string]*linkGroup)
+	dom.Walk(ctx.Doc, func(n *html.Node) bool {
+		if n.Type == html.ElementNode && ctx.Hidden(n) {
+			return false
+		}
+		if !isLink(n) {
+			return true
+		}
+		name := ctx.Name(n)
+		key := normalizeLabel(name)
+		if key == "" {
+			return true
+		}
+		group, ok := byKey[key]
+		if !ok {
+			group = &linkGroup{key: key, text: name}
+			byKey[key] = group
+			groups = append(groups, group)
+		}
+		group.links = append(group.links, n)
+		if dom.HasAttr(n, "href") {
+			destination := ctx.linkDestination(n)
+			known := false
+			for _, d := range group.destinations {
+				known = known || d == destination
+			}
+			if !known {
+				group.destinations = append(group.destinations, destination)
+			}
+		}
+		return true
+	})
+	return groups
+}
+
+// describeLevel describes the reading level of metrics for a violation
+func describeLevel(m readability.Metrics) string {
+	switch {
+	case m.Grade != nil:
+		return fmt.Sprintf("grade %.1f", *m.Grade)
+	case m.ReadingEase != nil:
+		return fmt.Sprintf("a %s reading ease of %.1f", m.Formula, *m.ReadingEase)
+	case m.LIX != nil:
+		return fmt.Sprintf("a LIX of %.1f", *m.LIX)
+	}
+	return m.Level
+}
+
+// checkReadingLevel checks that each section of text can be read with lower
+// secondary education (3.1.5). The whole page is checked when no section is
+// long enough on its own.
+func checkReadingLevel(ctx *ScanContext, doc *html.Node, result *ScanResult) {
+	r := ctx.Readability()
+	type part struct {
+		node    *html.Node
+		label   string
+		metrics readability.Metrics
+	}
+	var parts []part
+	for _, section := range r.Sections {
+		if section.Metrics.Words >= minReadingWords && section.node != nil {
+			parts = append(parts, part{section.node, fmt.Sprintf("Section %q", section.Heading), section.Metrics})
+		}
+	}
+	if len(parts) == 0 && r.Metrics.Words >= minReadingWords {
+		if body := dom.Find(doc, "body"); body != nil {
+			parts = append(parts, part{body, "Page text", r.Metrics})
+		}
+	}
+
+	for _, p := range parts {
+		if p.metrics.Difficult() {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "reading-level",
+				Impact:      "moderate",
+				Description: "Text requires more than lower secondary reading ability",
+				Help:        fmt.Sprintf("%s reads at %s (%s). Use shorter sentences and simpler words, or provide a plain-language summary", p.label, describeLevel(p.metrics), p.metrics.Level),
+				HelpURL:     "https://www.w3.org/WAI/WCAG22/Understanding/reading-level.html",
+				Nodes:       []string{getNodeHTML(p.node)},
+				Targets:     ctx.targets(p.node),
+			})
+		} else {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "reading-level",
+				Description: fmt.Sprintf("%s reads at %s", p.label, describeLevel(p.metrics)),
+				Nodes:       []string{getNodeHTML(p.node)},
+				Targets:     ctx.targets(p.node),
+			})
+		}
+	}
+}
+
+// checkAbbreviations checks that abbreviations are expanded where they are
+// first used, with <abbr title>, <dfn> or the expansion in parentheses (3.1.4)
+func checkAbbreviations(ctx *ScanContext, doc *html.Node, result *ScanResult) {
+	for _, use := range ctx.Readability().abbreviations {
+		if use.expanded {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "abbreviations",
+				Description: fmt.Sprintf("Abbreviation %s is expanded", use.text),
+				Nodes:       []string{getNodeHTML(use.node)},
+				Targets:     ctx.targets(use.node),
+			})
+			continue
+		}
+		result.Violations = append(result.Violations, AccessibilityCheck{
+			ID:          "abbreviations",
+			Impact:      "minor",
+			Description: "Abbreviation is not expanded",
+			Help:        fmt.Sprintf("Expand %s where it is first used, e.g. with <abbr title=\"...\">%s</abbr> or the full form in parentheses", use.text, use.text),
+			HelpURL:     "https://www.w3.org/WAI/WCAG22/Understanding/abbreviations.html",
+			Nodes:       []string{getNodeHTML(use.node)},
+			Targets:     ctx.targets(use.node),
+		})
+	}
+}
+
+// checkGenericLinks checks that link text describes the link's purpose on
+// its own rather than saying "click here" or "read more"
+func checkGenericLinks(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if isLink(n) && !ctx.Hidden(n) {
+		if name := ctx.Name(n); name != "" {
+			if genericLinkText[normalizeLabel(name)] {
+				result.Violations = append(result.Violations, AccessibilityCheck{
+					ID:          "link-text-generic",
+					Impact:      "moderate",
+					Description: "Link text does not describe the link's purpose",
+					Help:        fmt.Sprintf("Replace %q with text that says where the link leads, or extend the accessible name with aria-label or visually hidden text", name),
+					HelpURL:     "https://www.w3.org/WAI/WCAG22/Understanding/link-purpose-link-only.html",
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			} else {
+				result.Passes = append(result.Passes, AccessibilityCheck{
+					ID:          "link-text-generic",
+					Description: "Link text describes the link's purpose",
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			}
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkGenericLinks(ctx, c, result)
+	}
+}
+
+// checkIdenticalLinks checks that links with the same text lead to the same
+// place. Generic texts are reported by link-text-generic instead.
+func checkIdenticalLinks(ctx *ScanContext, doc *html.Node, result *ScanResult) {
+	for _, group := range ctx.linkGroups() {
+		if len(group.links) < 2 || genericLinkText[group.key] {
+			continue
+		}
+		if len(group.destinations) > 1 {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "identical-links-same-purpose",
+				Impact:      "minor",
+				Description: "Links with the same text lead to different places",
+				Help:        fmt.Sprintf("%d links named %q lead to %d different URLs. Give each link text that tells them apart", len(group.links), group.text, len(group.destinations)),
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/identical-links-same-purpose",
+				Nodes:       []string{getNodeHTML(group.links[0])},
+				Targets:     ctx.targets(group.links[0]),
+			})
+		} else {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "identical-links-same-purpose",
+				Description: "Links with the same text lead to the same place",
+				Nodes:       []string{getNodeHTML(group.links[0])},
+				Targets:     ctx.targets(group.links[0]),
+			})
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAbbreviations(t *testing.T) {
+	page := `<html lang="en"><body>
+<p>This HTML page is OK to read in the USA at 9 AM.</p>
+<p>It follows <abbr title="Cascading Style Sheets">CSS</abbr> rules, but CS is not expanded.</p>
+<p>Our <abbr title="Application Programming Interfaces">APIs</abbr> call the API, and the WCAG applies.</p>
+<p>Chapter IV covers the WCAG again.</p>
+</body></html>`
+
+	result, err := NewScanner().ScanHTML(strings.NewReader(page), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"CS", "WCAG"}
+	if got := result.Readability.Abbreviations; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpanded abbreviations = %v, want %v", got, want)
+	}
+}
+
+func TestSingular(t *testing.T) {
+	for abbreviation, want := range map[string]string{
+		"APIs": "API",
+		"URLs": "URL",
+		"CSS":  "CSS",
+		"iOS":  "iOS",
+		"Ps":   "Ps",
+	} {
+		if got := singular(abbreviation); got != want {
+			t.Errorf("singular(%q) = %q, want %q", abbreviation, got, want)
+		}
+	}
+}
+
+func TestReadabilityInlineElements(t *testing.T) {
+	page := `<html lang="en"><body>
+<p>Press the <img src="star.png" alt="star"> icon to save the page<br>and
+the <input type="checkbox" aria-label="public"> box to share it with your team.</p>
+</body></html>`
+
+	result, err := NewScanner().ScanHTML(strings.NewReader(page), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	metrics := result.Readability.Metrics
+	if metrics.Words != 16 || metrics.Sentences != 1 {
+		t.Errorf("page has %d words in %d sentences, want 16 in 1", metrics.Words, metrics.Sentences)
+	}
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// robotsToken returns the product token robots.txt groups are matched
+// against: the product name a User-Agent header starts with, e.g.
+// "TokubetsuScanner" for "TokubetsuScanner/1.0 (+accessibility scan)"
+func robotsToken(userAgent string) string {
+	token, _, _ := strings.Cut(strings.TrimSpace(userAgent), "/")
+	token, _, _ = strings.Cut(token, " ")
+	return strings.ToLower(token)
+}
+
+// robotsRules holds the robots.txt rules that apply to the crawler
+type robotsRules struct {
+	allowAll    bool
+	disallowAll bool
+	rules       []robotsRule
+	sitemaps    []string
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+// parseRobots parses a robots.txt body and keeps the rules of the groups
+// whose user-agent is the crawler's product token, or of the "*" groups when
+// none is. Tokens match exactly and case-insensitively, and the rules of
+// several groups for the same agent are merged, as described in RFC 9309.
+func parseRobots(body []byte, token string) *robotsRules {
+	type group struct {
+		agents []string
+		rules  []robotsRule
+	}
+
+	var groups []*group
+	var current *group
+	lastWasAgent := false
+	robots := &robotsRules{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// Consecutive user-agent lines share one group
+			if current == nil || !lastWasAgent {
+				current = &group{}
+				groups = append(groups, current)
+			}
+			current.agents = append(current.agents, robotsToken(value))
+			lastWasAgent = true
+		case "allow", "disallow":
+			lastWasAgent = false
+			if current == nil || value == "" {
+				continue
+			}
+			current.rules = append(current.rules, robotsRule{allow: key == "allow", pattern: value})
+		case "sitemap":
+			robots.sitemaps = append(robots.sitemaps, value)
+		default:
+			lastWasAgent = false
+		}
+	}
+
+	var matched, wildcard []*group
+	for _, g := range groups {
+		switch {
+		case slices.Contains(g.agents, token):
+			matched = append(matched, g)
+		case slices.Contains(g.agents, "*"):
+			wildcard = append(wildcard, g)
+		}
+	}
+	if len(matched) == 0 {
+		matched = wildcard
+	}
+	if len(matched) == 0 {
+		robots.allowAll = true
+		return robots
+	}
+	for _, g := range matched {
+		robots.rules = append(robots.rules, g.rules...)
+	}
+	return robots
+}
+
+// allowed reports whether the crawler may fetch the given URL. The longest
+// matching rule wins and allow wins a tie, as described in RFC 9309.
+func (r *robotsRules) allowed(u *url.URL) bool {
+	if r == nil || r.allowAll {
+		return true
+	}
+	if r.disallowAll {
+		return false
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+
+	allowed := true
+	longest := -1
+	for _, rule := range r.rules {
+		if !robotsPatternMatches(rule.pattern, path) {
+			continue
+		}
+		if len(rule.pattern) > longest || (len(rule.pattern) == longest && rule.allow) {
+			longest = len(rule.pattern)
+			allowed = rule.allow
+		}
+	}
+	return allowed
+}
+
+// robotsPatternMatches matches a robots.txt path pattern supporting the "*"
+// wildcard and the "$" end anchor
+func robotsPatternMatches(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	for i, part := range parts[1:] {
+		last := i == len(parts)-2
+		if last && anchored {
+			return strings.HasSuffix(rest, part)
+		}
+		idx := strings.Index(rest, part)
+		if idx < 0 {
+			return false
+		}
+		rest = rest[idx+len(part):]
+	}
+	return !anchored || rest == ""
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"net/url"
+	"testing"
+
+	"tokubetsu/internal/fetch"
+)
+
+func TestRobotsTokenMatchesUserAgent(t *testing.T) {
+	token := robotsToken(fetch.New(fetch.Config{}).UserAgent())
+	if token != "tokubetsuscanner" {
+		t.Fatalf("robots token = %q, want tokubetsuscanner", token)
+	}
+
+	robots := parseRobots([]byte(`
+User-agent: *
+Disallow: /
+
+User-agent: TokubetsuScanner
+Disallow: /private/
+`), token)
+
+	for path, want := range map[string]bool{
+		"/":             true,
+		"/about":        true,
+		"/private/page": false,
+	} {
+		u, _ := url.Parse("https://example.com" + path)
+		if got := robots.allowed(u); got != want {
+			t.Errorf("allowed(%s) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestRobotsGroupMatching(t *testing.T) {
+	tests := []struct {
+		name    string
+		robots  string
+		allowed map[string]bool
+	}{
+		{
+			name: "longer agent containing the token",
+			robots: `
+User-agent: TokubetsuScannerPro
+Disallow: /
+
+User-agent: *
+Disallow: /private/
+`,
+			allowed: map[string]bool{"/": true, "/private/": false},
+		},
+		{
+			name: "shorter agent contained in the token",
+			robots: `
+User-agent: Scanner
+Disallow: /
+
+User-agent: *
+Disallow: /private/
+`,
+			allowed: map[string]bool{"/": true, "/private/": false},
+		},
+		{
+			name: "case and version",
+			robots: `
+User-agent: tokubetsuSCANNER/2.0
+Disallow: /admin/
+`,
+			allowed: map[string]bool{"/": true, "/admin/": false},
+		},
+		{
+			name: "repeated groups are merged",
+			robots: `
+User-agent: TokubetsuScanner
+Disallow: /admin/
+
+User-agent: *
+Disallow: /
+
+User-agent: TokubetsuScanner
+Disallow: /drafts/
+Allow: /drafts/public/
+`,
+			allowed: map[string]bool{"/": true, "/admin/": false, "/drafts/x": false, "/drafts/public/x": true},
+		},
+		{
+			name: "repeated wildcard groups are merged",
+			robots: `
+User-agent: *
+Disallow: /admin/
+
+User-agent: OtherBot
+Disallow: /
+
+User-agent: *
+Disallow: /drafts/
+`,
+			allowed: map[string]bool{"/": true, "/admin/": false, "/drafts/": false},
+		},
+	}
+	for _, tt := range tests {
+		robots := parseRobots([]byte(tt.robots), "tokubetsuscanner")
+		for path, want := range tt.allowed {
+			u, _ := url.Parse("https://example.com" + path)
+			if got := robots.allowed(u); got != want {
+				t.Errorf("%s: allowed(%s) = %v, want %v", tt.name, path, got, want)
+			}
+		}
+	}
+}
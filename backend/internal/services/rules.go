@@ -0,0 +1,838 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+
+	"tokubetsu/internal/wcag"
+
+	"golang.org/x/net/html"
+)
+
+// WCAG conformance levels
+const (
+	LevelA   = wcag.LevelA
+	LevelAA  = wcag.LevelAA
+	LevelAAA = wcag.LevelAAA
+)
+
+// WCAG principles
+const (
+	PrinciplePerceivable    = wcag.PrinciplePerceivable
+	PrincipleOperable       = wcag.PrincipleOperable
+	PrincipleUnderstandable = wcag.PrincipleUnderstandable
+	PrincipleRobust         = wcag.PrincipleRobust
+)
+
+// RuleMeta describes a scanner rule independently of its implementation
+type RuleMeta struct {
+	ID        string   `json:"id"`
+	Version   string   `json:"version"`
+	Criteria  []string `json:"criteria"`  // WCAG 2.2 success criteria, e.g. "1.1.1"; the first is the primary one
+	Level     string   `json:"level"`     // A, AA or AAA
+	Principle string   `json:"principle"` // perceivable, operable, understandable or robust
+	Impact    string   `json:"impact"`    // default impact reported for violations
+	HelpURL   string   `json:"helpUrl"`
+}
+
+// Rule is a single accessibility check run against a parsed document.
+// Every result a rule appends must use the rule's own ID. Rules are shared
+// between concurrent scans, so any per-scan state belongs on the ScanContext.
+type Rule interface {
+	Meta() RuleMeta
+	Check(ctx *ScanContext, result *ScanResult)
+}
+
+type funcRule struct {
+	meta  RuleMeta
+	check func(ctx *ScanContext, doc *html.Node, result *ScanResult)
+}
+
+func (r *funcRule) Meta() RuleMeta { return r.meta }
+
+func (r *funcRule) Check(ctx *ScanContext, result *ScanResult) { r.check(ctx, ctx.Doc, result) }
+
+// NewRule builds a Rule from its metadata and a check function that is
+// called with the document root
+func NewRule(meta RuleMeta, check func(ctx *ScanContext, doc *html.Node, result *ScanResult)) Rule {
+	return &funcRule{meta: meta, check: check}
+}
+
+// RuleRegistry holds the ordered set of rules a Scanner runs
+type RuleRegistry struct {
+	mu       sync.RWMutex
+	rules    []Rule
+	index    map[string]Rule
+	disabled map[string]bool
+}
+
+// NewRuleRegistry creates an empty registry
+func NewRuleRegistry() *RuleRegistry {
+	return &RuleRegistry{
+		index:    make(map[string]Rule),
+		disabled: make(map[string]bool),
+	}
+}
+
+// Register adds a rule to the end of the registry. Rule IDs must be unique
+// and every rule must map to at least one WCAG success criterion.
+func (r *RuleRegistry) Register(rule Rule) error {
+	meta := rule.Meta()
+	if meta.ID == "" {
+		return fmt.Errorf("rule has no ID")
+	}
+	if len(meta.Criteria) == 0 {
+		return fmt.Errorf("rule %q has no WCAG success criteria", meta.ID)
+	}
+	for _, number := range meta.Criteria {
+		if _, ok := wcag.Lookup(number); !ok {
+			return fmt.Errorf("rule %q maps to unknown success criterion %q", meta.ID, number)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.index[meta.ID]; exists {
+		return fmt.Errorf("rule %q is already registered", meta.ID)
+	}
+	r.rules = append(r.rules, rule)
+	r.index[meta.ID] = rule
+	return nil
+}
+
+// Disable stops a registered rule from running. It reports whether the rule exists.
+func (r *RuleRegistry) Disable(id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.index[id]; !exists {
+		return false
+	}
+	r.disabled[id] = true
+	return true
+}
+
+// Enable re-enables a previously disabled rule. It reports whether the rule exists.
+func (r *RuleRegistry) Enable(id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.index[id]; !exists {
+		return false
+	}
+	delete(r.disabled, id)
+	return true
+}
+
+// Rules returns the enabled rules in registration order
+func (r *RuleRegistry) Rules() []Rule {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rules := make([]Rule, 0, len(r.rules))
+	for _, rule := range r.rules {
+		if !r.disabled[rule.Meta().ID] {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// Lookup returns the metadata of a registered rule, enabled or not
+func (r *RuleRegistry) Lookup(id string) (RuleMeta, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rule, exists := r.index[id]
+	if !exists {
+		return RuleMeta{}, false
+	}
+	return rule.Meta(), true
+}
+
+// Metas returns the metadata of every registered rule in registration order
+func (r *RuleRegistry) Metas() []RuleMeta {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	metas := make([]RuleMeta, 0, len(r.rules))
+	for _, rule := range r.rules {
+		metas = append(metas, rule.Meta())
+	}
+	return metas
+}
+
+// CatalogEntry describes a registered rule together with the success
+// criteria it tests
+type CatalogEntry struct {
+	RuleMeta
+	Criteria []wcag.Criterion `json:"criteria"`
+	Enabled  bool             `json:"enabled"`
+}
+
+// Catalog returns every registered rule in registration order
+func (r *RuleRegistry) Catalog() []CatalogEntry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := make([]CatalogEntry, 0, len(r.rules))
+	for _, rule := range r.rules {
+		meta := rule.Meta()
+		entry := CatalogEntry{RuleMeta: meta, Enabled: !r.disabled[meta.ID]}
+		for _, number := range meta.Criteria {
+			criterion, _ := wcag.Lookup(number)
+			entry.Criteria = append(entry.Criteria, criterion)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// DefaultRules returns a new registry populated with the built-in rules.
+// Each call returns an independent registry, so callers may disable or add
+// rules without affecting other scanners.
+func DefaultRules() *RuleRegistry {
+	registry := NewRuleRegistry()
+	for _, rule := range builtinRules() {
+		if err := registry.Register(rule); err != nil {
+			panic(err)
+		}
+	}
+	return registry
+}
+
+func builtinRules() []Rule {
+	return []Rule{
+		NewRule(RuleMeta{
+			ID:        "image-alt",
+			Version:   "1.0",
+			Criteria:  []string{"1.1.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "critical",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/image-alt",
+		}, checkImages),
+		NewRule(RuleMeta{
+			ID:        "heading-order",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "moderate",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/heading-order",
+		}, checkHeadings),
+		NewRule(RuleMeta{
+			ID:        "label",
+			Version:   "1.0",
+			Criteria:  []string{"3.3.2", "4.1.2"},
+			Level:     LevelA,
+			Principle: PrincipleUnderstandable,
+			Impact:    "critical",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/label",
+		}, checkForms),
+		NewRule(RuleMeta{
+			ID:        "input-button-name",
+			Version:   "1.0",
+			Criteria:  []string{"4.1.2"},
+			Level:     LevelA,
+			Principle: PrincipleRobust,
+			Impact:    "critical",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/input-button-name",
+		}, checkInputButtons),
+		NewRule(RuleMeta{
+			ID:        "button-name",
+			Version:   "1.0",
+			Criteria:  []string{"4.1.2"},
+			Level:     LevelA,
+			Principle: PrincipleRobust,
+			Impact:    "critical",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/button-name",
+		}, checkButtonNames),
+		NewRule(RuleMeta{
+			ID:        "autocomplete-valid",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.5"},
+			Level:     LevelAA,
+			Principle: PrinciplePerceivable,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/autocomplete-valid",
+		}, checkAutocomplete),
+		NewRule(RuleMeta{
+			ID:        "radio-checkbox-group",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1", "3.3.2"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "moderate",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/radiogroup",
+		}, checkFieldGroups),
+		NewRule(RuleMeta{
+			ID:        "label-content-name-mismatch",
+			Version:   "1.0",
+			Criteria:  []string{"2.5.3"},
+			Level:     LevelA,
+			Principle: PrincipleOperable,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/label-content-name-mismatch",
+		}, checkLabelInName),
+		NewRule(RuleMeta{
+			ID:        "placeholder-label",
+			Version:   "1.0",
+			Criteria:  []string{"3.3.2"},
+			Level:     LevelA,
+			Principle: PrincipleUnderstandable,
+			Impact:    "serious",
+			HelpURL:   "https://www.w3.org/WAI/tutorials/forms/instructions/#placeholder-text",
+		}, checkPlaceholderLabel),
+		NewRule(RuleMeta{
+			ID:        "required-field",
+			Version:   "1.0",
+			Criteria:  []string{"3.3.2", "1.3.1"},
+			Level:     LevelA,
+			Principle: PrincipleUnderstandable,
+			Impact:    "serious",
+			HelpURL:   "https://www.w3.org/WAI/tutorials/forms/validation/#required-input",
+		}, checkRequiredFields),
+		NewRule(RuleMeta{
+			ID:        "error-message",
+			Version:   "1.0",
+			Criteria:  []string{"3.3.1", "4.1.2"},
+			Level:     LevelA,
+			Principle: PrincipleUnderstandable,
+			Impact:    "serious",
+			HelpURL:   "https://www.w3.org/WAI/tutorials/forms/notifications/#on-focus-change",
+		}, checkErrorMessages),
+		NewRule(RuleMeta{
+			ID:        "link-name",
+			Version:   "1.0",
+			Criteria:  []string{"2.4.4", "4.1.2"},
+			Level:     LevelA,
+			Principle: PrincipleOperable,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/link-name",
+		}, checkLinks),
+		NewRule(RuleMeta{
+			ID:        "link-text-generic",
+			Version:   "1.0",
+			Criteria:  []string{"2.4.9"},
+			Level:     LevelAAA,
+			Principle: PrincipleOperable,
+			Impact:    "moderate",
+			HelpURL:   "https://www.w3.org/WAI/WCAG22/Understanding/link-purpose-link-only.html",
+		}, checkGenericLinks),
+		NewRule(RuleMeta{
+			ID:        "identical-links-same-purpose",
+			Version:   "1.0",
+			Criteria:  []string{"2.4.9"},
+			Level:     LevelAAA,
+			Principle: PrincipleOperable,
+			Impact:    "minor",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/identical-links-same-purpose",
+		}, checkIdenticalLinks),
+		NewRule(RuleMeta{
+			ID:        "reading-level",
+			Version:   "1.0",
+			Criteria:  []string{"3.1.5"},
+			Level:     LevelAAA,
+			Principle: PrincipleUnderstandable,
+			Impact:    "moderate",
+			HelpURL:   "https://www.w3.org/WAI/WCAG22/Understanding/reading-level.html",
+		}, checkReadingLevel),
+		NewRule(RuleMeta{
+			ID:        "abbreviations",
+			Version:   "1.0",
+			Criteria:  []string{"3.1.4"},
+			Level:     LevelAAA,
+			Principle: PrincipleUnderstandable,
+			Impact:    "minor",
+			HelpURL:   "https://www.w3.org/WAI/WCAG22/Understanding/abbreviations.html",
+		}, checkAbbreviations),
+		NewRule(RuleMeta{
+			ID:        "aria-roles",
+			Version:   "1.0",
+			Criteria:  []string{"4.1.2"},
+			Level:     LevelA,
+			Principle: PrincipleRobust,
+			Impact:    "critical",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/aria-roles",
+		}, checkARIARoles),
+		NewRule(RuleMeta{
+			ID:        "aria-valid-attr",
+			Version:   "1.0",
+			Criteria:  []string{"4.1.2"},
+			Level:     LevelA,
+			Principle: PrincipleRobust,
+			Impact:    "critical",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/aria-valid-attr",
+		}, checkARIAValidAttr),
+		NewRule(RuleMeta{
+			ID:        "aria-valid-attr-value",
+			Version:   "1.0",
+			Criteria:  []string{"4.1.2"},
+			Level:     LevelA,
+			Principle: PrincipleRobust,
+			Impact:    "critical",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/aria-valid-attr-value",
+		}, checkARIAValidAttrValue),
+		NewRule(RuleMeta{
+			ID:        "aria-allowed-attr",
+			Version:   "1.0",
+			Criteria:  []string{"4.1.2"},
+			Level:     LevelA,
+			Principle: PrincipleRobust,
+			Impact:    "critical",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/aria-allowed-attr",
+		}, checkARIAAllowedAttr),
+		NewRule(RuleMeta{
+			ID:        "aria-required-attr",
+			Version:   "1.0",
+			Criteria:  []string{"4.1.2"},
+			Level:     LevelA,
+			Principle: PrincipleRobust,
+			Impact:    "critical",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/aria-required-attr",
+		}, checkARIARequiredAttr),
+		NewRule(RuleMeta{
+			ID:        "aria-required-children",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "critical",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/aria-required-children",
+		}, checkARIARequiredChildren),
+		NewRule(RuleMeta{
+			ID:        "aria-required-parent",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "critical",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/aria-required-parent",
+		}, checkARIARequiredParent),
+		NewRule(RuleMeta{
+			ID:        "aria-hidden-focus",
+			Version:   "1.0",
+			Criteria:  []string{"4.1.2"},
+			Level:     LevelA,
+			Principle: PrincipleRobust,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/aria-hidden-focus",
+		}, checkARIAHiddenFocus),
+		NewRule(RuleMeta{
+			ID:        "landmark-one-main",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1", "2.4.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "moderate",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/landmark-one-main",
+		}, checkLandmarkOneMain),
+		NewRule(RuleMeta{
+			ID:        "landmark-no-duplicate-main",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "moderate",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/landmark-no-duplicate-main",
+		}, checkNoDuplicateLandmark("landmark-no-duplicate-main", "main")),
+		NewRule(RuleMeta{
+			ID:        "landmark-no-duplicate-banner",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "moderate",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/landmark-no-duplicate-banner",
+		}, checkNoDuplicateLandmark("landmark-no-duplicate-banner", "banner")),
+		NewRule(RuleMeta{
+			ID:        "landmark-no-duplicate-contentinfo",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "moderate",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/landmark-no-duplicate-contentinfo",
+		}, checkNoDuplicateLandmark("landmark-no-duplicate-contentinfo", "contentinfo")),
+		NewRule(RuleMeta{
+			ID:        "landmark-main-is-top-level",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "moderate",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/landmark-main-is-top-level",
+		}, checkTopLevelLandmark("landmark-main-is-top-level", "main")),
+		NewRule(RuleMeta{
+			ID:        "landmark-complementary-is-top-level",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "moderate",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/landmark-complementary-is-top-level",
+		}, checkTopLevelLandmark("landmark-complementary-is-top-level", "complementary")),
+		NewRule(RuleMeta{
+			ID:        "landmark-banner-is-top-level",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "moderate",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/landmark-banner-is-top-level",
+		}, checkTopLevelLandmark("landmark-banner-is-top-level", "banner")),
+		NewRule(RuleMeta{
+			ID:        "landmark-contentinfo-is-top-level",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "moderate",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/landmark-contentinfo-is-top-level",
+		}, checkTopLevelLandmark("landmark-contentinfo-is-top-level", "contentinfo")),
+		NewRule(RuleMeta{
+			ID:        "landmark-unique",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1", "2.4.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "moderate",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/landmark-unique",
+		}, checkLandmarkUnique),
+		NewRule(RuleMeta{
+			ID:        "region",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1", "2.4.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "moderate",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/region",
+		}, checkRegion),
+		NewRule(RuleMeta{
+			ID:        "duplicate-id",
+			Version:   "1.0",
+			Criteria:  []string{"4.1.1"},
+			Level:     LevelA,
+			Principle: PrincipleRobust,
+			Impact:    "minor",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/duplicate-id",
+		}, checkDuplicateIDs("duplicate-id", false)),
+		NewRule(RuleMeta{
+			ID:        "duplicate-id-aria",
+			Version:   "1.0",
+			Criteria:  []string{"4.1.2", "1.3.1", "4.1.1"},
+			Level:     LevelA,
+			Principle: PrincipleRobust,
+			Impact:    "critical",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/duplicate-id-aria",
+		}, checkDuplicateIDs("duplicate-id-aria", true)),
+		NewRule(RuleMeta{
+			ID:        "empty-heading",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1", "2.4.6"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "minor",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/empty-heading",
+		}, checkEmptyHeading),
+		NewRule(RuleMeta{
+			ID:        "page-has-heading-one",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1", "2.4.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "moderate",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/page-has-heading-one",
+		}, checkPageHasHeadingOne),
+		NewRule(RuleMeta{
+			ID:        "list",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/list",
+		}, checkList),
+		NewRule(RuleMeta{
+			ID:        "listitem",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/listitem",
+		}, checkListItem),
+		NewRule(RuleMeta{
+			ID:        "color-contrast",
+			Version:   "1.0",
+			Criteria:  []string{"1.4.3"},
+			Level:     LevelAA,
+			Principle: PrinciplePerceivable,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/color-contrast",
+		}, checkColorContrast),
+		NewRule(RuleMeta{
+			ID:        "color-contrast-enhanced",
+			Version:   "1.0",
+			Criteria:  []string{"1.4.6"},
+			Level:     LevelAAA,
+			Principle: PrinciplePerceivable,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/color-contrast-enhanced",
+		}, checkColorContrastEnhanced),
+		NewRule(RuleMeta{
+			ID:        "target-size",
+			Version:   "1.0",
+			Criteria:  []string{"2.5.8"},
+			Level:     LevelAA,
+			Principle: PrincipleOperable,
+			Impact:    "minor",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/target-size",
+		}, checkTargetSize),
+		NewRule(RuleMeta{
+			ID:        "html-has-lang",
+			Version:   "1.0",
+			Criteria:  []string{"3.1.1"},
+			Level:     LevelA,
+			Principle: PrincipleUnderstandable,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/html-has-lang",
+		}, checkHTMLLang),
+		NewRule(RuleMeta{
+			ID:        "language-valid",
+			Version:   "1.0",
+			Criteria:  []string{"3.1.2"},
+			Level:     LevelAA,
+			Principle: PrincipleUnderstandable,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/valid-lang",
+		}, checkLanguageValid),
+		NewRule(RuleMeta{
+			ID:        "document-title",
+			Version:   "1.0",
+			Criteria:  []string{"2.4.2"},
+			Level:     LevelA,
+			Principle: PrincipleOperable,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/document-title",
+		}, checkDocumentTitle),
+		NewRule(RuleMeta{
+			ID:        "meta-viewport",
+			Version:   "1.0",
+			Criteria:  []string{"1.4.4"},
+			Level:     LevelAA,
+			Principle: PrinciplePerceivable,
+			Impact:    "critical",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/meta-viewport",
+		}, checkMetaViewport),
+		NewRule(RuleMeta{
+			ID:        "meta-refresh",
+			Version:   "1.0",
+			Criteria:  []string{"2.2.1"},
+			Level:     LevelA,
+			Principle: PrincipleOperable,
+			Impact:    "critical",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/meta-refresh",
+		}, checkMetaRefresh),
+		NewRule(RuleMeta{
+			ID:        "td-headers-attr",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/td-headers-attr",
+		}, checkTableHeadersAttr),
+		NewRule(RuleMeta{
+			ID:        "th-has-data-cells",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/th-has-data-cells",
+		}, checkTableHeaderData),
+		NewRule(RuleMeta{
+			ID:        "table-caption",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "moderate",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/table-fake-caption",
+		}, checkTableCaption),
+		NewRule(RuleMeta{
+			ID:        "scope-attr-valid",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "moderate",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/scope-attr-valid",
+		}, checkScopeAttr),
+		NewRule(RuleMeta{
+			ID:        "layout-table-th",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/layout-table",
+		}, checkLayoutTableHeaders),
+		NewRule(RuleMeta{
+			ID:        "table-nested",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "moderate",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/layout-table",
+		}, checkNestedTables),
+		NewRule(RuleMeta{
+			ID:        "table-presentation-role",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/presentation-role-conflict",
+		}, checkPresentationTables),
+		NewRule(RuleMeta{
+			ID:        "table-complex-headers",
+			Version:   "1.0",
+			Criteria:  []string{"1.3.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/td-has-header",
+		}, checkComplexTables),
+		NewRule(RuleMeta{
+			ID:        "tabindex",
+			Version:   "1.0",
+			Criteria:  []string{"2.4.3"},
+			Level:     LevelA,
+			Principle: PrincipleOperable,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/tabindex",
+		}, checkTabIndex),
+		NewRule(RuleMeta{
+			ID:        "click-handler-keyboard",
+			Version:   "1.0",
+			Criteria:  []string{"2.1.1"},
+			Level:     LevelA,
+			Principle: PrincipleOperable,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/focus-order-semantics",
+		}, checkClickHandlers),
+		NewRule(RuleMeta{
+			ID:        "accesskeys",
+			Version:   "1.0",
+			Criteria:  []string{"2.1.1"},
+			Level:     LevelA,
+			Principle: PrincipleOperable,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/accesskeys",
+		}, checkAccessKeys),
+		NewRule(RuleMeta{
+			ID:        "skip-link",
+			Version:   "1.0",
+			Criteria:  []string{"2.4.1"},
+			Level:     LevelA,
+			Principle: PrincipleOperable,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/bypass",
+		}, checkSkipLink),
+		NewRule(RuleMeta{
+			ID:        "video-caption",
+			Version:   "1.0",
+			Criteria:  []string{"1.2.2"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "critical",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/video-caption",
+		}, checkVideoCaptions),
+		NewRule(RuleMeta{
+			ID:        "audio-transcript",
+			Version:   "1.0",
+			Criteria:  []string{"1.2.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "critical",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/audio-caption",
+		}, checkAudioTranscript),
+		NewRule(RuleMeta{
+			ID:        "no-autoplay-audio",
+			Version:   "1.0",
+			Criteria:  []string{"1.4.2"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "moderate",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/no-autoplay-audio",
+		}, checkAutoplay),
+		NewRule(RuleMeta{
+			ID:        "frame-title",
+			Version:   "1.0",
+			Criteria:  []string{"4.1.2"},
+			Level:     LevelA,
+			Principle: PrincipleRobust,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/frame-title",
+		}, checkFrameTitles),
+		NewRule(RuleMeta{
+			ID:        "object-alt",
+			Version:   "1.0",
+			Criteria:  []string{"1.1.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/object-alt",
+		}, checkObjectAlt),
+		NewRule(RuleMeta{
+			ID:        "area-alt",
+			Version:   "1.0",
+			Criteria:  []string{"1.1.1", "2.4.4"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "critical",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/area-alt",
+		}, checkAreaAlt),
+		NewRule(RuleMeta{
+			ID:        "input-image-alt",
+			Version:   "1.0",
+			Criteria:  []string{"1.1.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "critical",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/input-image-alt",
+		}, checkInputImageAlt),
+		NewRule(RuleMeta{
+			ID:        "svg-img-alt",
+			Version:   "1.0",
+			Criteria:  []string{"1.1.1"},
+			Level:     LevelA,
+			Principle: PrinciplePerceivable,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/svg-img-alt",
+		}, checkSVGAlt),
+		NewRule(RuleMeta{
+			ID:        "blink",
+			Version:   "1.0",
+			Criteria:  []string{"2.2.2"},
+			Level:     LevelA,
+			Principle: PrincipleOperable,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/blink",
+		}, checkBlink),
+		NewRule(RuleMeta{
+			ID:        "marquee",
+			Version:   "1.0",
+			Criteria:  []string{"2.2.2"},
+			Level:     LevelA,
+			Principle: PrincipleOperable,
+			Impact:    "serious",
+			HelpURL:   "https://dequeuniversity.com/rules/axe/4.6/marquee",
+		}, checkMarquee),
+	}
+}
@@ -1,95 +1,155 @@
 package services
 
 import (
+	"bytes"
 	"fmt"
 	"io"
-	"net/http"
-	"regexp"
 	"strconv"
 	"strings"
 
+	"tokubetsu/internal/a11ytree"
+	"tokubetsu/internal/aria"
+	"tokubetsu/internal/dom"
+	"tokubetsu/internal/fetch"
+
 	"golang.org/x/net/html"
 )
 
 type AccessibilityCheck struct {
-	ID          string   `json:"id"`
-	Impact      string   `json:"impact"`
-	Description string   `json:"description"`
-	Help        string   `json:"help"`
-	HelpURL     string   `json:"helpUrl"`
-	Nodes       []string `json:"nodes"`
+	ID          string         `json:"id"`
+	Impact      string         `json:"impact"`
+	Description string         `json:"description"`
+	Help        string         `json:"help"`
+	HelpURL     string         `json:"helpUrl"`
+	Nodes       []string       `json:"nodes"`
+	Targets     []dom.Location `json:"targets"` // locators of Nodes, index for index
 }
 
 type ScanResult struct {
 	Passes     []AccessibilityCheck `json:"passes"`
 	Violations []AccessibilityCheck `json:"violations"`
+	// Tree is the accessibility tree of the scanned page. It is large, so it
+	// is not part of the serialized result and is stored separately.
+	Tree *a11ytree.Node `json:"-"`
+	// TabOrder is the sequence of elements a keyboard user tabs through
+	TabOrder []TabStop `json:"tabOrder"`
+	// Readability is the reading level analysis of the page's text
+	Readability *Readability `json:"readability"`
+	// ColorVision lists the text colors that lose contrast under each color
+	// vision deficiency
+	ColorVision map[string][]ColorVisionFinding `json:"colorVision"`
 }
 
 type Scanner struct {
-	client *http.Client
+	fetcher *fetch.Client
+	rules   *RuleRegistry
 }
 
-// Track global heading hierarchy
-var headingLevels []int
-
 func NewScanner() *Scanner {
+	return NewScannerWithRules(DefaultRules())
+}
+
+// NewScannerWithRules creates a scanner that runs the rules of the given registry
+func NewScannerWithRules(rules *RuleRegistry) *Scanner {
 	return &Scanner{
-		client: &http.Client{},
+		fetcher: fetch.New(fetch.Config{}),
+		rules:   rules,
 	}
 }
 
+// WithFetcher returns a copy of the scanner that fetches pages and their
+// resources with the given client, e.g. one with a project's credentials
+func (s *Scanner) WithFetcher(fetcher *fetch.Client) *Scanner {
+	scanner := *s
+	scanner.fetcher = fetcher
+	return &scanner
+}
+
+// Rules returns the registry of rules the scanner runs
+func (s *Scanner) Rules() *RuleRegistry {
+	return s.rules
+}
+
+// ScanURL fetches and scans a page. A response outside 2xx fails the scan
+// with a *fetch.StatusError rather than scanning the error page.
 func (s *Scanner) ScanURL(url string) (*ScanResult, error) {
-	// Fetch the page
-	resp, err := s.client.Get(url)
+	page, err := s.fetch(url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch URL: %v", err)
+		return nil, err
+	}
+	if err := page.Check(); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	// Read the response body
-	body, err := io.ReadAll(resp.Body)
+	// Resolve against the final URL, since relative links and stylesheets
+	// are relative to the page after redirects
+	return s.scanSource(page.Body, page.URL)
+}
+
+// ScanHTML scans an HTML document read from r. baseURL is the address the
+// document is (or will be) served from and may be empty.
+func (s *Scanner) ScanHTML(r io.Reader, baseURL string) (*ScanResult, error) {
+	// Keep the source so violations can point at their line and column
+	source, err := io.ReadAll(r)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
+		return nil, fmt.Errorf("failed to read HTML: %v", err)
 	}
 
+	return s.scanSource(source, baseURL)
+}
+
+func (s *Scanner) scanSource(source []byte, baseURL string) (*ScanResult, error) {
 	// Parse HTML
-	doc, err := html.Parse(strings.NewReader(string(body)))
+	doc, err := html.Parse(bytes.NewReader(source))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse HTML: %v", err)
 	}
 
+	return s.scanDocument(doc, source, baseURL), nil
+}
+
+// fetch downloads a page or resource with the scanner's fetch client
+func (s *Scanner) fetch(url string) (*fetch.Response, error) {
+	page, err := s.fetcher.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+	return page, nil
+}
+
+// scanDocument runs every enabled rule against a document parsed from source
+func (s *Scanner) scanDocument(doc *html.Node, source []byte, baseURL string) *ScanResult {
 	result := &ScanResult{
 		Passes:     make([]AccessibilityCheck, 0),
 		Violations: make([]AccessibilityCheck, 0),
 	}
 
-	// Reset heading levels for each scan
-	headingLevels = make([]int, 0)
+	// Every scan gets its own context so concurrent scans never share state
+	ctx := newScanContext(baseURL, doc, source, s.loadStylesheets(doc, baseURL))
 
 	// Perform accessibility checks
-	s.checkImages(doc, result)
-	s.checkHeadings(doc, result)
-	s.checkForms(doc, result)
-	s.checkLinks(doc, result)
-	s.checkARIA(doc, result)
-	s.checkLandmarks(doc, result)
-	s.checkColorContrast(doc, result)
-	s.checkTargetSize(doc, result)
-
-	return result, nil
-}
+	for _, rule := range s.rules.Rules() {
+		rule.Check(ctx, result)
+	}
 
-func (s *Scanner) checkImages(n *html.Node, result *ScanResult) {
-	if n.Type == html.ElementNode && n.Data == "img" {
-		var alt string
-		for _, attr := range n.Attr {
-			if attr.Key == "alt" {
-				alt = attr.Val
-				break
-			}
-		}
+	result.Tree = ctx.AccessibilityTree()
+	result.TabOrder = ctx.TabOrder()
+	result.Readability = ctx.Readability()
+	result.ColorVision = ctx.ColorVision()
+
+	return result
+}
 
-		if alt == "" {
+func checkImages(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if n.Type == html.ElementNode && n.Data == "img" && !ctx.Hidden(n) {
+		if aria.IsPresentational(n) {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "image-alt",
+				Description: "Image is marked as decorative",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		} else if ctx.Name(n) == "" {
 			result.Violations = append(result.Violations, AccessibilityCheck{
 				ID:          "image-alt",
 				Impact:      "critical",
@@ -97,22 +157,24 @@ func (s *Scanner) checkImages(n *html.Node, result *ScanResult) {
 				Help:        "Images must have alternate text",
 				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/image-alt",
 				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
 			})
 		} else {
 			result.Passes = append(result.Passes, AccessibilityCheck{
 				ID:          "image-alt",
 				Description: "Image has appropriate alt text",
 				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
 			})
 		}
 	}
 
 	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		s.checkImages(c, result)
+		checkImages(ctx, c, result)
 	}
 }
 
-func (s *Scanner) checkHeadings(n *html.Node, result *ScanResult) {
+func checkHeadings(ctx *ScanContext, n *html.Node, result *ScanResult) {
 	// Only match h1-h6 elements specifically, not html, head, etc.
 	if n.Type == html.ElementNode && len(n.Data) == 2 && n.Data[0] == 'h' {
 		level := n.Data[1:]
@@ -130,8 +192,8 @@ func (s *Scanner) checkHeadings(n *html.Node, result *ScanResult) {
 			headingText = strings.TrimSpace(headingText)
 
 			// Check for global heading hierarchy issues
-			if len(headingLevels) > 0 {
-				lastLevel := headingLevels[len(headingLevels)-1]
+			if len(ctx.headingLevels) > 0 {
+				lastLevel := ctx.headingLevels[len(ctx.headingLevels)-1]
 
 				// Headings should only increase by one level at a time
 				if currentLevel > lastLevel+1 {
@@ -142,12 +204,14 @@ func (s *Scanner) checkHeadings(n *html.Node, result *ScanResult) {
 						Help:        fmt.Sprintf("Heading levels should not be skipped. Found h%d after h%d", currentLevel, lastLevel),
 						HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/heading-order",
 						Nodes:       []string{fmt.Sprintf("<%s>%s</%s>", n.Data, headingText, n.Data)},
+						Targets:     ctx.targets(n),
 					})
 				} else {
 					result.Passes = append(result.Passes, AccessibilityCheck{
 						ID:          "heading-order",
 						Description: "Heading has valid level",
 						Nodes:       []string{getNodeHTML(n)},
+						Targets:     ctx.targets(n),
 					})
 				}
 			} else if currentLevel != 1 {
@@ -159,68 +223,39 @@ func (s *Scanner) checkHeadings(n *html.Node, result *ScanResult) {
 					Help:        fmt.Sprintf("The first heading on the page should be h1, found h%d", currentLevel),
 					HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/heading-order",
 					Nodes:       []string{fmt.Sprintf("<%s>%s</%s>", n.Data, headingText, n.Data)},
+					Targets:     ctx.targets(n),
 				})
 			} else {
 				result.Passes = append(result.Passes, AccessibilityCheck{
 					ID:          "heading-order",
 					Description: "Heading has valid level",
 					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
 				})
 			}
 
 			// Add current level to our heading hierarchy
-			headingLevels = append(headingLevels, currentLevel)
+			ctx.headingLevels = append(ctx.headingLevels, currentLevel)
 		}
 	}
 
 	// Process children
 	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		s.checkHeadings(c, result)
+		checkHeadings(ctx, c, result)
 	}
 }
 
-func (s *Scanner) checkForms(n *html.Node, result *ScanResult) {
-	if n.Type == html.ElementNode && (n.Data == "input" || n.Data == "select" || n.Data == "textarea") {
-		var hasLabel, hasAriaLabel bool
-		var id, name, type_, placeholder string
-
-		for _, attr := range n.Attr {
-			switch attr.Key {
-			case "id":
-				id = attr.Val
-			case "name":
-				name = attr.Val
-			case "type":
-				type_ = attr.Val
-			case "placeholder":
-				placeholder = attr.Val
-			case "aria-label", "aria-labelledby":
-				hasAriaLabel = true
-			}
-		}
-
-		if id != "" {
-			// Check for associated label
-			parent := n.Parent
-			for parent != nil {
-				for c := parent.FirstChild; c != nil; c = c.NextSibling {
-					if c.Type == html.ElementNode && c.Data == "label" {
-						for _, attr := range c.Attr {
-							if attr.Key == "for" && attr.Val == id {
-								hasLabel = true
-								break
-							}
-						}
-					}
-				}
-				parent = parent.Parent
-			}
-		}
+func checkForms(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if n.Type == html.ElementNode && (n.Data == "input" || n.Data == "select" || n.Data == "textarea") && !ctx.Hidden(n) {
+		id := dom.Attr(n, "id")
+		name := dom.Attr(n, "name")
+		type_ := strings.ToLower(dom.Attr(n, "type"))
+		placeholder := dom.Attr(n, "placeholder")
 
-		// Check if the input is hidden or a button type (these don't need visible labels)
+		// Hidden inputs have no label, and buttons are named by their value
 		isExempt := type_ == "hidden" || type_ == "button" || type_ == "submit" || type_ == "reset" || type_ == "image"
 
-		if !hasLabel && !hasAriaLabel && !isExempt {
+		if !isExempt && ctx.Name(n) == "" {
 			// Construct helpful description of the element
 			elementDesc := n.Data
 			if type_ != "" {
@@ -243,41 +278,57 @@ func (s *Scanner) checkForms(n *html.Node, result *ScanResult) {
 				Help:        fmt.Sprintf("Form elements must have labels. Element: <%s>", elementDesc),
 				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/label",
 				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
 			})
-		} else {
+		} else if !isExempt {
 			result.Passes = append(result.Passes, AccessibilityCheck{
 				ID:          "label",
 				Description: "Form element has proper labeling",
 				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
 			})
 		}
 	}
 
 	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		s.checkForms(c, result)
+		checkForms(ctx, c, result)
 	}
 }
 
-func (s *Scanner) checkLinks(n *html.Node, result *ScanResult) {
-	if n.Type == html.ElementNode && n.Data == "a" {
-		var hasText bool
-		var text string
-
-		// Check for text content
-		var f func(*html.Node)
-		f = func(n *html.Node) {
-			if n.Type == html.TextNode {
-				text += strings.TrimSpace(n.Data)
-			}
-			for c := n.FirstChild; c != nil; c = c.NextSibling {
-				f(c)
+// checkInputButtons checks that input buttons have a value or other accessible name
+func checkInputButtons(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if dom.IsElement(n, "input") && !ctx.Hidden(n) {
+		switch strings.ToLower(dom.Attr(n, "type")) {
+		case "button", "submit", "reset":
+			if ctx.Name(n) == "" {
+				result.Violations = append(result.Violations, AccessibilityCheck{
+					ID:          "input-button-name",
+					Impact:      "critical",
+					Description: "Input button does not have discernible text",
+					Help:        "Input buttons must have a value, aria-label or aria-labelledby",
+					HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/input-button-name",
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			} else {
+				result.Passes = append(result.Passes, AccessibilityCheck{
+					ID:          "input-button-name",
+					Description: "Input button has discernible text",
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
 			}
 		}
-		f(n)
+	}
 
-		hasText = text != ""
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkInputButtons(ctx, c, result)
+	}
+}
 
-		if !hasText {
+func checkLinks(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if isLink(n) && !ctx.Hidden(n) {
+		if ctx.Name(n) == "" {
 			result.Violations = append(result.Violations, AccessibilityCheck{
 				ID:          "link-name",
 				Impact:      "serious",
@@ -285,83 +336,29 @@ func (s *Scanner) checkLinks(n *html.Node, result *ScanResult) {
 				Help:        "Links must have discernible text",
 				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/link-name",
 				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
 			})
 		} else {
 			result.Passes = append(result.Passes, AccessibilityCheck{
 				ID:          "link-name",
 				Description: "Link has descriptive text",
 				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
 			})
 		}
 	}
 
 	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		s.checkLinks(c, result)
+		checkLinks(ctx, c, result)
 	}
 }
 
-func (s *Scanner) checkARIA(n *html.Node, result *ScanResult) {
-	if n.Type == html.ElementNode {
-		var hasInvalidARIA bool
-		var ariaAttrs []string
-
-		for _, attr := range n.Attr {
-			if strings.HasPrefix(attr.Key, "aria-") {
-				ariaAttrs = append(ariaAttrs, attr.Key)
-				// Basic validation - could be expanded
-				if attr.Val == "" {
-					hasInvalidARIA = true
-				}
-			}
-		}
-
-		if hasInvalidARIA {
-			result.Violations = append(result.Violations, AccessibilityCheck{
-				ID:          "aria-valid",
-				Impact:      "serious",
-				Description: "ARIA attribute is invalid",
-				Help:        "ARIA attributes must be valid",
-				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/aria-valid-attr",
-				Nodes:       []string{getNodeHTML(n)},
-			})
-		} else if len(ariaAttrs) > 0 {
-			result.Passes = append(result.Passes, AccessibilityCheck{
-				ID:          "aria-valid",
-				Description: "ARIA attributes are valid",
-				Nodes:       []string{getNodeHTML(n)},
-			})
-		}
-	}
-
-	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		s.checkARIA(c, result)
-	}
-}
-
-func (s *Scanner) checkLandmarks(n *html.Node, result *ScanResult) {
-	if n.Type == html.ElementNode {
-		landmarks := map[string]bool{
-			"main":    true,
-			"nav":     true,
-			"header":  true,
-			"footer":  true,
-			"article": true,
-			"aside":   true,
-			"section": true,
-		}
-
-		if landmarks[n.Data] {
-			result.Passes = append(result.Passes, AccessibilityCheck{
-				ID:          "landmark",
-				Description: fmt.Sprintf("Page has proper %s landmark", n.Data),
-				Nodes:       []string{getNodeHTML(n)},
-			})
-		}
-	}
-
-	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		s.checkLandmarks(c, result)
+// isLink reports whether an element is a hyperlink or has the link role
+func isLink(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
 	}
+	return (n.Data == "a" && dom.HasAttr(n, "href")) || aria.ExplicitRole(n) == "link"
 }
 
 func getNodeHTML(n *html.Node) string {
@@ -398,150 +395,47 @@ func getNodeHTML(n *html.Node) string {
 	return sb.String()
 }
 
-// Helper to extract hex or rgb color from inline style
-func extractCSSColor(style, prop string) string {
-	re := regexp.MustCompile(prop + `:\s*([^;]+);?`)
-	matches := re.FindStringSubmatch(style)
-	if len(matches) > 1 {
-		val := strings.TrimSpace(matches[1])
-		if strings.HasPrefix(val, "#") {
-			return val
-		}
-		if strings.HasPrefix(val, "rgb") {
-			// Convert rgb/rgba to hex (simple version, assumes rgb)
-			val = strings.TrimPrefix(val, "rgb(")
-			val = strings.TrimSuffix(val, ")")
-			parts := strings.Split(val, ",")
-			if len(parts) >= 3 {
-				r, _ := strconv.Atoi(strings.TrimSpace(parts[0]))
-				g, _ := strconv.Atoi(strings.TrimSpace(parts[1]))
-				b, _ := strconv.Atoi(strings.TrimSpace(parts[2]))
-				return fmt.Sprintf("#%02x%02x%02x", r, g, b)
-			}
+// isClickTarget reports whether an element is a pointer target
+func isClickTarget(n *html.Node) bool {
+	switch n.Data {
+	case "a", "button":
+		return true
+	case "input":
+		switch strings.ToLower(dom.Attr(n, "type")) {
+		case "button", "submit", "reset", "image":
+			return true
 		}
 	}
-	return ""
+	return dom.Attr(n, "role") == "button"
 }
 
-// Color contrast check (Level AA)
-func (s *Scanner) checkColorContrast(n *html.Node, result *ScanResult) {
-	if n.Type == html.ElementNode && (n.Data == "p" || n.Data == "span" || n.Data == "div") {
-		var fg, bg string
-		for _, attr := range n.Attr {
-			if attr.Key == "style" {
-				style := attr.Val
-				if strings.Contains(style, "color:") {
-					fg = extractCSSColor(style, "color")
-				}
-				if strings.Contains(style, "background:") {
-					bg = extractCSSColor(style, "background")
-				}
-				if strings.Contains(style, "background-color:") {
-					bg = extractCSSColor(style, "background-color")
-				}
-			}
-		}
-		if fg != "" && bg != "" {
-			// Use simple luminance/contrast calculation
-			contrast := simpleContrastRatio(fg, bg)
-			if contrast > 0 {
-				if contrast < 4.5 {
-					result.Violations = append(result.Violations, AccessibilityCheck{
-						ID:          "color-contrast",
-						Impact:      "serious",
-						Description: "Text has insufficient color contrast",
-						Help:        "Foreground and background colors must have sufficient contrast (AA: 4.5:1)",
-						HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/color-contrast",
-						Nodes:       []string{getNodeHTML(n)},
-					})
-				} else {
-					result.Passes = append(result.Passes, AccessibilityCheck{
-						ID:          "color-contrast",
-						Description: "Text has sufficient color contrast",
-						Nodes:       []string{getNodeHTML(n)},
-					})
-				}
-			}
-		}
-	}
-	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		s.checkColorContrast(c, result)
-	}
-}
-
-// Simple contrast ratio calculation (not WCAG-accurate, but illustrative)
-func simpleContrastRatio(fg, bg string) float64 {
-	parse := func(hex string) (r, g, b float64) {
-		hex = strings.TrimPrefix(hex, "#")
-		if len(hex) == 6 {
-			var ri, gi, bi int
-			fmt.Sscanf(hex, "%02x%02x%02x", &ri, &gi, &bi)
-			return float64(ri), float64(gi), float64(bi)
-		}
-		return 0, 0, 0
-	}
-	fr, fg_, fb := parse(fg)
-	br, bg_, bb := parse(bg)
-	l1 := 0.2126*fr/255 + 0.7152*fg_/255 + 0.0722*fb/255
-	l2 := 0.2126*br/255 + 0.7152*bg_/255 + 0.0722*bb/255
-	if l1 > l2 {
-		return (l1 + 0.05) / (l2 + 0.05)
-	}
-	return (l2 + 0.05) / (l1 + 0.05)
-}
-
-// Target size check (Level AAA)
-func (s *Scanner) checkTargetSize(n *html.Node, result *ScanResult) {
-	if n.Type == html.ElementNode && (n.Data == "button" || n.Data == "a") {
-		var width, height int
-		for _, attr := range n.Attr {
-			if attr.Key == "style" {
-				style := attr.Val
-				if strings.Contains(style, "width:") {
-					w := extractCSSDimension(style, "width")
-					if w > 0 {
-						width = w
-					}
-				}
-				if strings.Contains(style, "height:") {
-					h := extractCSSDimension(style, "height")
-					if h > 0 {
-						height = h
-					}
-				}
-			}
-		}
-		if width > 0 && height > 0 {
+// Target size check (Level AA, 2.5.8 Target Size (Minimum): at least 24x24px),
+// evaluated on the computed width and height
+func checkTargetSize(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if n.Type == html.ElementNode && isClickTarget(n) && !ctx.Styles.Hidden(n) {
+		width, height, widthOK, heightOK := ctx.Styles.Style(n).Size()
+		if widthOK && heightOK {
 			if width < 24 || height < 24 {
 				result.Violations = append(result.Violations, AccessibilityCheck{
 					ID:          "target-size",
 					Impact:      "minor",
 					Description: "Element has insufficient target size",
-					Help:        "Clickable targets should be at least 24x24px (AAA)",
+					Help:        fmt.Sprintf("Clickable targets should be at least 24x24px. Found %.0fx%.0fpx", width, height),
 					HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/target-size",
 					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
 				})
 			} else {
 				result.Passes = append(result.Passes, AccessibilityCheck{
 					ID:          "target-size",
 					Description: "Element has sufficient target size",
 					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
 				})
 			}
 		}
 	}
 	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		s.checkTargetSize(c, result)
-	}
-}
-
-// Helper to extract px dimension from style
-func extractCSSDimension(style, prop string) int {
-	re := regexp.MustCompile(prop + `:\s*([0-9]+)px`)
-	matches := re.FindStringSubmatch(style)
-	if len(matches) > 1 {
-		val, _ := strconv.Atoi(matches[1])
-		return val
+		checkTargetSize(ctx, c, result)
 	}
-	return 0
 }
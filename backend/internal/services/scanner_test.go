@@ -0,0 +1,87 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+const testPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<title>Concurrent scan</title>
+<link rel="stylesheet" href="/style.css">
+</head>
+<body>
+<header><nav><a href="/">Home</a> <a href="/about">Read more</a></nav></header>
+<main>
+<h1>Concurrent scan</h1>
+<h3>Skipped level</h3>
+<p class="faint">Light gray text on a white background is hard to read.</p>
+<img src="logo.png">
+<form>
+<label for="email">Email</label>
+<input id="email" type="email" autocomplete="email" required>
+<input type="text" placeholder="Search">
+<button></button>
+</form>
+<p>The <abbr title="World Wide Web Consortium">W3C</abbr> publishes the WCAG.</p>
+</main>
+</body>
+</html>`
+
+const testStylesheet = `
+body { color: #333; background: #fff; font-size: 16px; }
+.faint { color: #aaa; }
+h1 { font-size: 2em; }
+`
+
+// TestScanURLConcurrent scans the same page from many goroutines on one
+// scanner. Run with -race to catch state shared between scans.
+func TestScanURLConcurrent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprint(w, testPage)
+		case "/style.css":
+			w.Header().Set("Content-Type", "text/css")
+			fmt.Fprint(w, testStylesheet)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	const scans = 50
+	scanner := NewScanner()
+	results := make([]*ScanResult, scans)
+	errs := make([]error, scans)
+
+	var wg sync.WaitGroup
+	for i := 0; i < scans; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = scanner.ScanURL(server.URL + "/")
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("scan %d failed: %v", i, err)
+		}
+	}
+	if len(results[0].Violations) == 0 {
+		t.Fatal("expected violations on the test page")
+	}
+	for i, result := range results[1:] {
+		if !reflect.DeepEqual(result, results[0]) {
+			t.Errorf("scan %d differs from scan 0", i+1)
+		}
+	}
+}
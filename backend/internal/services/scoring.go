@@ -0,0 +1,149 @@
+package services
+
+import (
+	"log"
+	"slices"
+	"strings"
+
+	"tokubetsu/internal/models"
+	"tokubetsu/internal/scoring"
+	"tokubetsu/internal/standards"
+	"tokubetsu/internal/wcag"
+)
+
+// scoringScope collects the checks and criteria of a profile for scoring.
+// Checks of rules that test no criterion of the profile do not count.
+type scoringScope struct {
+	profile  *standards.Profile
+	criteria map[string]bool
+	input    scoring.Input
+}
+
+func newScoringScope(profile *standards.Profile) *scoringScope {
+	scope := &scoringScope{profile: profile, criteria: make(map[string]bool)}
+	for _, requirement := range profile.Requirements {
+		if requirement.Criterion != "" {
+			scope.criteria[requirement.Criterion] = true
+		}
+	}
+	return scope
+}
+
+// addChecks adds count checks of a rule that tests the given criteria. Each
+// check counts towards every level and principle of the criteria in the
+// profile.
+func (s *scoringScope) addChecks(rule string, numbers []string, passed bool, impact string, count int) {
+	var levels, principles []string
+	for _, number := range numbers {
+		if !s.criteria[number] {
+			continue
+		}
+		criterion, _ := wcag.Lookup(number)
+		if !slices.Contains(levels, criterion.Level) {
+			levels = append(levels, criterion.Level)
+		}
+		if !slices.Contains(principles, criterion.Principle) {
+			principles = append(principles, criterion.Principle)
+		}
+	}
+	if len(levels) == 0 {
+		return
+	}
+	for i := 0; i < count; i++ {
+		s.input.Checks = append(s.input.Checks, scoring.Check{
+			Rule:       rule,
+			Passed:     passed,
+			Impact:     impact,
+			Levels:     levels,
+			Principles: principles,
+		})
+	}
+}
+
+// addResults adds the criterion results of the profile
+func (s *scoringScope) addResults(results []models.CriterionResult) {
+	for _, result := range results {
+		requirement, _ := s.profile.Requirement(result.Criterion)
+		s.input.Criteria = append(s.input.Criteria, scoring.Criterion{
+			Status:    result.Status,
+			Manual:    result.Source == models.SourceManual,
+			Level:     result.Level,
+			Principle: requirement.Principle,
+		})
+	}
+}
+
+// Score scores a scan result with a scoring method against the requirements
+// of a profile
+func (s *Scanner) Score(result *ScanResult, method string, profile *standards.Profile) (scoring.Score, error) {
+	scope := newScoringScope(profile)
+	for _, checks := range []struct {
+		passed bool
+		list   []AccessibilityCheck
+	}{{true, result.Passes}, {false, result.Violations}} {
+		for _, check := range checks.list {
+			if meta, ok := s.rules.Lookup(check.ID); ok {
+				scope.addChecks(check.ID, meta.Criteria, checks.passed, check.Impact, 1)
+			}
+		}
+	}
+	scope.addResults(s.rules.evaluateCriteria(result, profile))
+	return scoring.Compute(method, scope.input)
+}
+
+// passCounts counts the passes of a scan by the criteria of their rule,
+// separated by spaces, so reports can be scored again without the scan
+func (s *Scanner) passCounts(result *ScanResult) map[string]int {
+	counts := make(map[string]int)
+	for _, pass := range result.Passes {
+		if meta, ok := s.rules.Lookup(pass.ID); ok {
+			counts[strings.Join(meta.Criteria, " ")]++
+		}
+	}
+	return counts
+}
+
+// scoreReport sets the overall, level and principle scores of a report with
+// its scoring method. Levels and principles without anything to score are
+// left nil rather than shown as a score nothing earned.
+func scoreReport(report *models.ComplianceReport, profile *standards.Profile) {
+	scope := newScoringScope(profile)
+	for _, violation := range report.Violations {
+		criteria := violation.Criteria
+		if len(criteria) == 0 {
+			criteria = []string{violation.Criterion}
+		}
+		scope.addChecks(violation.RuleID, criteria, false, violation.Impact, 1)
+	}
+	// Reports from before passes were counted by all criteria of their rule
+	// have a single criterion per key
+	for numbers, count := range report.PassCounts {
+		scope.addChecks("", strings.Fields(numbers), true, "", count)
+	}
+	scope.addResults(report.Results)
+
+	method := report.ScoringMethod
+	if !scoring.Valid(method) {
+		method = scoring.DefaultMethod
+	}
+	score, err := scoring.Compute(method, scope.input)
+	if err != nil {
+		log.Printf("Failed to score report %s: %v", report.ID, err)
+		return
+	}
+
+	scoreOf := func(scores map[string]float64, key string) *float64 {
+		if s, ok := scores[key]; ok {
+			return &s
+		}
+		return nil
+	}
+	report.OverallScore = score.Overall
+	report.LevelAScore = scoreOf(score.Levels, LevelA)
+	report.LevelAAScore = scoreOf(score.Levels, LevelAA)
+	report.LevelAAAScore = scoreOf(score.Levels, LevelAAA)
+	report.PerceivableScore = scoreOf(score.Principles, PrinciplePerceivable)
+	report.OperableScore = scoreOf(score.Principles, PrincipleOperable)
+	report.UnderstandableScore = scoreOf(score.Principles, PrincipleUnderstandable)
+	report.RobustScore = scoreOf(score.Principles, PrincipleRobust)
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"tokubetsu/internal/models"
+	"tokubetsu/internal/scoring"
+	"tokubetsu/internal/standards"
+)
+
+// scoringFixture is a scan of a page with images, colored text, a link and
+// the document language and title. Level AAA has nothing to score.
+func scoringFixture() *ScanResult {
+	check := func(id, impact string, count int) []AccessibilityCheck {
+		checks := make([]AccessibilityCheck, count)
+		for i := range checks {
+			checks[i] = AccessibilityCheck{ID: id, Impact: impact, Nodes: []string{"<x>"}}
+		}
+		return checks
+	}
+	result := &ScanResult{}
+	for _, passes := range [][]AccessibilityCheck{
+		check("image-alt", "", 3),      // 1.1.1, A, Perceivable
+		check("color-contrast", "", 2), // 1.4.3, AA, Perceivable
+		check("html-has-lang", "", 1),  // 3.1.1, A, Understandable
+		check("document-title", "", 1), // 2.4.2, A, Operable
+	} {
+		result.Passes = append(result.Passes, passes...)
+	}
+	for _, violations := range [][]AccessibilityCheck{
+		check("image-alt", "critical", 1),
+		check("color-contrast", "serious", 2),
+		check("link-name", "serious", 1), // 2.4.4 and 4.1.2, A, Operable
+	} {
+		result.Violations = append(result.Violations, violations...)
+	}
+	return result
+}
+
+// fixtureReport builds the report of a scan like GenerateReport does
+func fixtureReport(scanner *Scanner, result *ScanResult, method string, profile *standards.Profile) *models.ComplianceReport {
+	report := &models.ComplianceReport{
+		Profile:       profile.ID,
+		ScoringMethod: method,
+		PassCounts:    scanner.passCounts(result),
+		Results:       scanner.rules.evaluateCriteria(result, profile),
+	}
+	for _, violation := range result.Violations {
+		meta, _ := scanner.rules.Lookup(violation.ID)
+		report.Violations = append(report.Violations, models.ComplianceViolation{
+			RuleID:    violation.ID,
+			Impact:    violation.Impact,
+			Criterion: meta.Criteria[0],
+			Criteria:  meta.Criteria,
+		})
+	}
+	scoreReport(report, profile)
+	return report
+}
+
+type pinnedScore struct {
+	overall                                       float64
+	levelA, levelAA, levelAAA                     *float64
+	perceivable, operable, understandable, robust *float64
+}
+
+func score(v float64) *float64 { return &v }
+
+func TestScoringMethodsPinned(t *testing.T) {
+	tests := []struct {
+		method string
+		want   pinnedScore
+	}{
+		{scoring.ImpactWeighted, pinnedScore{
+			// critical 10, serious twice 6 + 1.5, serious 6. The link-name
+			// failure counts towards both Operable and Robust.
+			overall: 76.5,
+			levelA:  score(84), levelAA: score(92.5),
+			perceivable: score(82.5), operable: score(94), understandable: score(100), robust: score(94),
+		}},
+		{scoring.PassRatio, pinnedScore{
+			// 7 of 11 checks passed
+			overall: 63.64,
+			levelA:  score(71.43), levelAA: score(50),
+			perceivable: score(62.5), operable: score(50), understandable: score(100), robust: score(0),
+		}},
+		{scoring.Criteria, pinnedScore{
+			// 3.1.1 and 2.4.2 passed automated checks, 1.1.1, 1.4.3, 2.4.4
+			// and 4.1.2 failed
+			overall: 33.33,
+			levelA:  score(40), levelAA: score(0),
+			perceivable: score(0), operable: score(50), understandable: score(100), robust: score(0),
+		}},
+	}
+
+	scanner := NewScanner()
+	profile := standards.Resolve("wcag22-aa")
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			result := scoringFixture()
+
+			scanScore, err := scanner.Score(result, tt.method, profile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			report := fixtureReport(scanner, result, tt.method, profile)
+
+			fromMap := func(scores map[string]float64, key string) *float64 {
+				if s, ok := scores[key]; ok {
+					return &s
+				}
+				return nil
+			}
+			for _, got := range []struct {
+				path string
+				pinnedScore
+			}{
+				{"Scanner.Score", pinnedScore{
+					scanScore.Overall,
+					fromMap(scanScore.Levels, LevelA), fromMap(scanScore.Levels, LevelAA), fromMap(scanScore.Levels, LevelAAA),
+					fromMap(scanScore.Principles, PrinciplePerceivable), fromMap(scanScore.Principles, PrincipleOperable),
+					fromMap(scanScore.Principles, PrincipleUnderstandable), fromMap(scanScore.Principles, PrincipleRobust),
+				}},
+				{"scoreReport", pinnedScore{
+					report.OverallScore,
+					report.LevelAScore, report.LevelAAScore, report.LevelAAAScore,
+					report.PerceivableScore, report.OperableScore, report.UnderstandableScore, report.RobustScore,
+				}},
+			} {
+				comparePinned(t, got.path, got.pinnedScore, tt.want)
+			}
+		})
+	}
+}
+
+func comparePinned(t *testing.T, path string, got, want pinnedScore) {
+	t.Helper()
+	for _, s := range []struct {
+		name      string
+		got, want *float64
+	}{
+		{"overall", &got.overall, &want.overall},
+		{"level A", got.levelA, want.levelA},
+		{"level AA", got.levelAA, want.levelAA},
+		{"level AAA", got.levelAAA, want.levelAAA},
+		{"perceivable", got.perceivable, want.perceivable},
+		{"operable", got.operable, want.operable},
+		{"understandable", got.understandable, want.understandable},
+		{"robust", got.robust, want.robust},
+	} {
+		switch {
+		case s.got == nil && s.want == nil:
+		case s.got == nil || s.want == nil:
+			t.Errorf("%s: %s score = %v, want %v", path, s.name, format(s.got), format(s.want))
+		case math.Abs(*s.got-*s.want) > 0.005:
+			t.Errorf("%s: %s score = %.2f, want %.2f", path, s.name, *s.got, *s.want)
+		}
+	}
+}
+
+func format(s *float64) interface{} {
+	if s == nil {
+		return "nothing to score"
+	}
+	return *s
+}
+
+func TestScoreReportNothingToScore(t *testing.T) {
+	scanner := NewScanner()
+	profile := standards.Resolve("wcag22-aa")
+	for _, method := range scoring.Methods() {
+		report := fixtureReport(scanner, &ScanResult{}, method, profile)
+		if report.OverallScore != 100 {
+			t.Errorf("%s: overall score = %v, want 100", method, report.OverallScore)
+		}
+		for name, s := range map[string]*float64{
+			"level A": report.LevelAScore, "level AA": report.LevelAAScore, "level AAA": report.LevelAAAScore,
+			"perceivable": report.PerceivableScore, "operable": report.OperableScore,
+			"understandable": report.UnderstandableScore, "robust": report.RobustScore,
+		} {
+			if s != nil {
+				t.Errorf("%s: %s score = %v, want nothing to score", method, name, *s)
+			}
+		}
+	}
+}
@@ -0,0 +1,543 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"tokubetsu/internal/aria"
+	"tokubetsu/internal/dom"
+
+	"golang.org/x/net/html"
+)
+
+// Landmark roles. Forms and regions are only landmarks when they are named.
+var landmarkRoles = map[string]bool{
+	"banner":        true,
+	"complementary": true,
+	"contentinfo":   true,
+	"form":          true,
+	"main":          true,
+	"navigation":    true,
+	"region":        true,
+	"search":        true,
+}
+
+// Attributes whose value is a list of ID references
+var idRefAttrs = []string{
+	"aria-activedescendant", "aria-controls", "aria-describedby", "aria-details",
+	"aria-errormessage", "aria-flowto", "aria-labelledby", "aria-owns",
+}
+
+// Elements that render content users perceive even without text
+var contentElements = []string{
+	"audio", "button", "canvas", "embed", "iframe", "img", "input", "object",
+	"select", "svg", "textarea", "video",
+}
+
+// landmarkRole returns the landmark role of an element, or "" when it is
+// not a landmark
+func (ctx *ScanContext) landmarkRole(n *html.Node) string {
+	if n.Type != html.ElementNode {
+		return ""
+	}
+	role := aria.Role(n)
+	if !landmarkRoles[role] {
+		return ""
+	}
+	if (role == "form" || role == "region") && ctx.Name(n) == "" {
+		return ""
+	}
+	return role
+}
+
+// landmarks returns the rendered landmarks of the document in document order
+func (ctx *ScanContext) landmarks() []*html.Node {
+	var found []*html.Node
+	dom.Walk(ctx.Doc, func(n *html.Node) bool {
+		if n.Type != html.ElementNode {
+			return true
+		}
+		if ctx.Hidden(n) {
+			return false
+		}
+		if ctx.landmarkRole(n) != "" {
+			found = append(found, n)
+		}
+		return true
+	})
+	return found
+}
+
+// landmarksWithRole returns the rendered landmarks of one role
+func (ctx *ScanContext) landmarksWithRole(role string) []*html.Node {
+	var found []*html.Node
+	for _, n := range ctx.landmarks() {
+		if ctx.landmarkRole(n) == role {
+			found = append(found, n)
+		}
+	}
+	return found
+}
+
+// enclosingLandmark returns the nearest landmark containing n, if any
+func (ctx *ScanContext) enclosingLandmark(n *html.Node) *html.Node {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if ctx.landmarkRole(p) != "" {
+			return p
+		}
+	}
+	return nil
+}
+
+// headingLevel returns the level of a heading element, or 0 when n is not a
+// heading
+func headingLevel(n *html.Node) int {
+	if n.Type != html.ElementNode {
+		return 0
+	}
+	if role := aria.ExplicitRole(n); role != "" {
+		if role != "heading" {
+			return 0
+		}
+		if level, err := strconv.Atoi(strings.TrimSpace(dom.Attr(n, "aria-level"))); err == nil && level > 0 {
+			return level
+		}
+		if dom.IsElement(n, "h1", "h2", "h3", "h4", "h5", "h6") {
+			return int(n.Data[1] - '0')
+		}
+		return 2
+	}
+	if dom.IsElement(n, "h1", "h2", "h3", "h4", "h5", "h6") {
+		return int(n.Data[1] - '0')
+	}
+	return 0
+}
+
+// checkLandmarkOneMain checks that the page has a main landmark
+func checkLandmarkOneMain(ctx *ScanContext, doc *html.Node, result *ScanResult) {
+	root := dom.Find(doc, "html")
+	if root == nil {
+		return
+	}
+
+	mains := ctx.landmarksWithRole("main")
+	if len(mains) == 0 {
+		result.Violations = append(result.Violations, AccessibilityCheck{
+			ID:          "landmark-one-main",
+			Impact:      "moderate",
+			Description: "Page does not have a main landmark",
+			Help:        "The main content of the page must be contained in a <main> element or role=\"main\"",
+			HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/landmark-one-main",
+			Nodes:       []string{"<html>"},
+			Targets:     ctx.targets(root),
+		})
+		return
+	}
+	result.Passes = append(result.Passes, AccessibilityCheck{
+		ID:          "landmark-one-main",
+		Description: "Page has a main landmark",
+		Nodes:       []string{getNodeHTML(mains[0])},
+		Targets:     ctx.targets(mains[0]),
+	})
+}
+
+// checkNoDuplicateLandmark returns a check allowing at most one landmark of
+// a role on the page
+func checkNoDuplicateLandmark(id, role string) func(ctx *ScanContext, doc *html.Node, result *ScanResult) {
+	return func(ctx *ScanContext, doc *html.Node, result *ScanResult) {
+		found := ctx.landmarksWithRole(role)
+		if len(found) == 1 {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          id,
+				Description: fmt.Sprintf("Page has a single %s landmark", role),
+				Nodes:       []string{getNodeHTML(found[0])},
+				Targets:     ctx.targets(found[0]),
+			})
+			return
+		}
+		// The first landmark is kept, every further one is reported
+		for i := 1; i < len(found); i++ {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          id,
+				Impact:      "moderate",
+				Description: fmt.Sprintf("Page has more than one %s landmark", role),
+				Help:        fmt.Sprintf("A page must not have more than one %s landmark", role),
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/" + id,
+				Nodes:       []string{getNodeHTML(found[i])},
+				Targets:     ctx.targets(found[i]),
+			})
+		}
+	}
+}
+
+// checkTopLevelLandmark returns a check that landmarks of a role are not
+// contained in another landmark
+func checkTopLevelLandmark(id, role string) func(ctx *ScanContext, doc *html.Node, result *ScanResult) {
+	return func(ctx *ScanContext, doc *html.Node, result *ScanResult) {
+		for _, n := range ctx.landmarksWithRole(role) {
+			if parent := ctx.enclosingLandmark(n); parent != nil {
+				result.Violations = append(result.Violations, AccessibilityCheck{
+					ID:          id,
+					Impact:      "moderate",
+					Description: fmt.Sprintf("The %s landmark is contained in a %s landmark", role, ctx.landmarkRole(parent)),
+					Help:        fmt.Sprintf("The %s landmark must be at the top level of the page, not inside another landmark", role),
+					HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/" + id,
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			} else {
+				result.Passes = append(result.Passes, AccessibilityCheck{
+					ID:          id,
+					Description: fmt.Sprintf("The %s landmark is at the top level", role),
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			}
+		}
+	}
+}
+
+// checkLandmarkUnique checks that landmarks sharing a role have different
+// accessible names, so that users can tell them apart. Main, banner and
+// contentinfo landmarks are left to the rules against duplicates.
+func checkLandmarkUnique(ctx *ScanContext, doc *html.Node, result *ScanResult) {
+	type key struct{ role, name string }
+	groups := make(map[key][]*html.Node)
+	var order []key
+	for _, n := range ctx.landmarks() {
+		role := ctx.landmarkRole(n)
+		if role == "main" || role == "banner" || role == "contentinfo" {
+			continue
+		}
+		k := key{role, strings.ToLower(strings.TrimSpace(ctx.Name(n)))}
+		if groups[k] == nil {
+			order = append(order, k)
+		}
+		groups[k] = append(groups[k], n)
+	}
+
+	for _, k := range order {
+		nodes := groups[k]
+		if len(nodes) == 1 {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "landmark-unique",
+				Description: fmt.Sprintf("The %s landmark has a unique name", k.role),
+				Nodes:       []string{getNodeHTML(nodes[0])},
+				Targets:     ctx.targets(nodes[0]),
+			})
+			continue
+		}
+		description := fmt.Sprintf("%d %s landmarks share the name %q", len(nodes), k.role, k.name)
+		if k.name == "" {
+			description = fmt.Sprintf("%d %s landmarks have no name", len(nodes), k.role)
+		}
+		for _, n := range nodes {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "landmark-unique",
+				Impact:      "moderate",
+				Description: description,
+				Help:        fmt.Sprintf("Give each %s landmark a distinct name with aria-label or aria-labelledby", k.role),
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/landmark-unique",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+}
+
+// isSkipLink reports whether an element is a link to a fragment of the page
+func isSkipLink(n *html.Node) bool {
+	return dom.IsElement(n, "a") && strings.HasPrefix(strings.TrimSpace(dom.Attr(n, "href")), "#")
+}
+
+// hasPerceivableContent reports whether an element contains text or
+// elements that render content
+func hasPerceivableContent(n *html.Node) bool {
+	return dom.Text(n) != "" || dom.Find(n, contentElements...) != nil
+}
+
+// checkRegion checks that all content of the page is contained in
+// landmarks. Skip links and dialogs may sit outside landmarks.
+func checkRegion(ctx *ScanContext, doc *html.Node, result *ScanResult) {
+	body := dom.Find(doc, "body")
+	if body == nil {
+		return
+	}
+
+	containsLandmark := func(n *html.Node) bool {
+		found := false
+		dom.Walk(n, func(node *html.Node) bool {
+			if found || (node.Type == html.ElementNode && ctx.Hidden(node)) {
+				return false
+			}
+			found = ctx.landmarkRole(node) != ""
+			return !found
+		})
+		return found
+	}
+	outside := func(n *html.Node) {
+		result.Violations = append(result.Violations, AccessibilityCheck{
+			ID:          "region",
+			Impact:      "moderate",
+			Description: "Content is not contained in a landmark",
+			Help:        "All page content should be contained in landmarks such as <header>, <nav>, <main> and <footer>",
+			HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/region",
+			Nodes:       []string{getNodeHTML(n)},
+			Targets:     ctx.targets(n),
+		})
+	}
+
+	var visit func(n *html.Node)
+	visit = func(n *html.Node) {
+		strayText := false
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.TextNode {
+				strayText = strayText || strings.TrimSpace(c.Data) != ""
+				continue
+			}
+			if c.Type != html.ElementNode || ctx.Hidden(c) || dom.IsElement(c, "script", "style", "template", "noscript") {
+				continue
+			}
+			role := aria.Role(c)
+			switch {
+			case ctx.landmarkRole(c) != "":
+				result.Passes = append(result.Passes, AccessibilityCheck{
+					ID:          "region",
+					Description: fmt.Sprintf("Content is contained in a %s landmark", ctx.landmarkRole(c)),
+					Nodes:       []string{getNodeHTML(c)},
+					Targets:     ctx.targets(c),
+				})
+			case isSkipLink(c), role == "dialog", role == "alertdialog":
+			case containsLandmark(c):
+				visit(c)
+			case hasPerceivableContent(c):
+				outside(c)
+			}
+		}
+		if strayText {
+			outside(n)
+		}
+	}
+	visit(body)
+}
+
+// checkEmptyHeading checks that headings have text
+func checkEmptyHeading(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if n.Type == html.ElementNode && ctx.Hidden(n) {
+		return
+	}
+	if headingLevel(n) > 0 {
+		if ctx.Name(n) == "" {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "empty-heading",
+				Impact:      "minor",
+				Description: "Heading is empty",
+				Help:        "Headings must have text that screen reader users can navigate by",
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/empty-heading",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		} else {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "empty-heading",
+				Description: "Heading has text",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkEmptyHeading(ctx, c, result)
+	}
+}
+
+// checkPageHasHeadingOne checks that the page has a level one heading
+func checkPageHasHeadingOne(ctx *ScanContext, doc *html.Node, result *ScanResult) {
+	root := dom.Find(doc, "html")
+	if root == nil {
+		return
+	}
+
+	var h1 *html.Node
+	dom.Walk(doc, func(n *html.Node) bool {
+		if h1 != nil || (n.Type == html.ElementNode && ctx.Hidden(n)) {
+			return false
+		}
+		if headingLevel(n) == 1 {
+			h1 = n
+			return false
+		}
+		return true
+	})
+
+	if h1 == nil {
+		result.Violations = append(result.Violations, AccessibilityCheck{
+			ID:          "page-has-heading-one",
+			Impact:      "moderate",
+			Description: "Page does not have a level one heading",
+			Help:        "The page should have an <h1> heading that introduces its main content",
+			HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/page-has-heading-one",
+			Nodes:       []string{"<html>"},
+			Targets:     ctx.targets(root),
+		})
+		return
+	}
+	result.Passes = append(result.Passes, AccessibilityCheck{
+		ID:          "page-has-heading-one",
+		Description: "Page has a level one heading",
+		Nodes:       []string{getNodeHTML(h1)},
+		Targets:     ctx.targets(h1),
+	})
+}
+
+// isListElement reports whether an element is a list that keeps its list
+// semantics
+func isListElement(n *html.Node) bool {
+	if role := aria.ExplicitRole(n); role != "" {
+		return role == "list"
+	}
+	return dom.IsElement(n, "ul", "ol", "menu")
+}
+
+// checkList checks that ul and ol elements only contain list items
+func checkList(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if dom.IsElement(n, "ul", "ol") && aria.ExplicitRole(n) == "" && !ctx.Hidden(n) {
+		var invalid []string
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			switch {
+			case c.Type == html.TextNode:
+				if strings.TrimSpace(c.Data) != "" {
+					invalid = append(invalid, "text")
+				}
+			case c.Type != html.ElementNode, dom.IsElement(c, "script", "template"):
+			case dom.IsElement(c, "li"):
+				if role := aria.ExplicitRole(c); role != "" && role != "listitem" {
+					invalid = append(invalid, fmt.Sprintf("<li role=%q>", role))
+				}
+			default:
+				invalid = append(invalid, "<"+c.Data+">")
+			}
+		}
+
+		if len(invalid) > 0 {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "list",
+				Impact:      "serious",
+				Description: "List contains elements that are not list items",
+				Help:        fmt.Sprintf("<%s> elements must only directly contain <li>, <script> or <template> elements. Found: %s", n.Data, strings.Join(invalid, ", ")),
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/list",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		} else {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "list",
+				Description: "List only contains list items",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkList(ctx, c, result)
+	}
+}
+
+// checkListItem checks that li elements are contained in a list
+func checkListItem(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if dom.IsElement(n, "li") && aria.ExplicitRole(n) == "" && !ctx.Hidden(n) {
+		if n.Parent != nil && isListElement(n.Parent) {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "listitem",
+				Description: "List item is contained in a list",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		} else {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "listitem",
+				Impact:      "serious",
+				Description: "List item is not contained in a list",
+				Help:        "<li> elements must be contained in a <ul>, <ol> or <menu> element, or an element with role=\"list\"",
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/listitem",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkListItem(ctx, c, result)
+	}
+}
+
+// documentIDs returns the elements of the document by id, the ids in
+// document order, and the ids that ARIA attributes or labels refer to
+func documentIDs(doc *html.Node) (elements map[string][]*html.Node, order []string, referenced map[string]bool) {
+	elements = make(map[string][]*html.Node)
+	referenced = make(map[string]bool)
+	for _, e := range dom.Elements(doc) {
+		if id := dom.Attr(e, "id"); strings.TrimSpace(id) != "" {
+			if elements[id] == nil {
+				order = append(order, id)
+			}
+			elements[id] = append(elements[id], e)
+		}
+		for _, attr := range idRefAttrs {
+			for _, ref := range strings.Fields(dom.Attr(e, attr)) {
+				referenced[ref] = true
+			}
+		}
+		if dom.IsElement(e, "label") {
+			if ref := dom.Attr(e, "for"); ref != "" {
+				referenced[ref] = true
+			}
+		}
+	}
+	return elements, order, referenced
+}
+
+// checkDuplicateIDs returns a check for id values used more than once. With
+// referenced set it checks ids that ARIA attributes or labels refer to, where
+// a duplicate changes which element is used; otherwise the remaining ids.
+// Other duplicates only fail 4.1.1 Parsing, which WCAG 2.2 dropped, so they
+// are informational in 2.2 profiles.
+func checkDuplicateIDs(id string, referenced bool) func(ctx *ScanContext, doc *html.Node, result *ScanResult) {
+	impact, help := "minor", "id attribute values must be unique"
+	if referenced {
+		impact, help = "critical", "IDs used in ARIA attributes and labels must be unique, or the reference may resolve to the wrong element"
+	}
+
+	return func(ctx *ScanContext, doc *html.Node, result *ScanResult) {
+		elements, order, refs := documentIDs(doc)
+		for _, value := range order {
+			if refs[value] != referenced {
+				continue
+			}
+			nodes := elements[value]
+			if len(nodes) == 1 {
+				result.Passes = append(result.Passes, AccessibilityCheck{
+					ID:          id,
+					Description: fmt.Sprintf("id %q is unique", value),
+					Nodes:       []string{getNodeHTML(nodes[0])},
+					Targets:     ctx.targets(nodes[0]),
+				})
+				continue
+			}
+			for _, n := range nodes[1:] {
+				result.Violations = append(result.Violations, AccessibilityCheck{
+					ID:          id,
+					Impact:      impact,
+					Description: fmt.Sprintf("id %q is used by %d elements", value, len(nodes)),
+					Help:        help,
+					HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/" + id,
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"net/http"
+	"net/url"
+
+	"tokubetsu/internal/css"
+
+	"golang.org/x/net/html"
+)
+
+// Limits applied when loading the stylesheets of a page
+const (
+	maxStylesheets      = 30
+	maxStylesheetSize   = 2 << 20 // 2 MiB
+	maxStylesheetImport = 2       // nesting depth of @import
+)
+
+// loadStylesheets parses the <style> elements of a document and fetches its
+// linked stylesheets, following @import. Stylesheets that cannot be fetched
+// are skipped; relative links cannot be resolved when baseURL is empty.
+func (s *Scanner) loadStylesheets(doc *html.Node, baseURL string) []*css.Stylesheet {
+	base, _ := url.Parse(baseURL)
+	if base != nil && !base.IsAbs() {
+		base = nil
+	}
+	if href := css.BaseURL(doc); href != "" && base != nil {
+		if b, err := base.Parse(href); err == nil {
+			base = b
+		}
+	}
+
+	var sheets []*css.Stylesheet
+	loaded := 0
+	var add func(sheet *css.Stylesheet, sheetURL *url.URL, depth int)
+	add = func(sheet *css.Stylesheet, sheetURL *url.URL, depth int) {
+		// Imported sheets precede the rules of the sheet importing them
+		if sheetURL != nil && depth < maxStylesheetImport {
+			for _, href := range sheet.Imports {
+				if imported, importURL := s.fetchStylesheet(sheetURL, href, &loaded); imported != nil {
+					add(imported, importURL, depth+1)
+				}
+			}
+		}
+		sheets = append(sheets, sheet)
+	}
+
+	for _, source := range css.Sources(doc) {
+		if source.Href == "" {
+			add(css.ParseStylesheet(source.Text), base, 0)
+			continue
+		}
+		if base == nil {
+			continue
+		}
+		if sheet, sheetURL := s.fetchStylesheet(base, source.Href, &loaded); sheet != nil {
+			add(sheet, sheetURL, 0)
+		}
+	}
+	return sheets
+}
+
+// fetchStylesheet fetches and parses a stylesheet referenced relative to base
+func (s *Scanner) fetchStylesheet(base *url.URL, href string, loaded *int) (*css.Stylesheet, *url.URL) {
+	if *loaded >= maxStylesheets {
+		return nil, nil
+	}
+	sheetURL, err := base.Parse(href)
+	if err != nil || (sheetURL.Scheme != "http" && sheetURL.Scheme != "https") {
+		return nil, nil
+	}
+	*loaded++
+
+	page, err := s.fetch(sheetURL.String())
+	if err != nil || page.StatusCode != http.StatusOK || len(page.Body) > maxStylesheetSize {
+		return nil, nil
+	}
+	return css.ParseStylesheet(string(page.Body)), sheetURL
+}
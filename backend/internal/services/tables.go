@@ -0,0 +1,569 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"tokubetsu/internal/aria"
+	"tokubetsu/internal/dom"
+
+	"golang.org/x/net/html"
+)
+
+// Table markup that only makes sense in a data table
+var dataTableElements = []string{"th", "caption", "thead", "tfoot", "colgroup"}
+
+// tableRows returns the rows of a table, excluding rows of nested tables
+func tableRows(table *html.Node) []*html.Node {
+	var rows []*html.Node
+	for c := table.FirstChild; c != nil; c = c.NextSibling {
+		switch {
+		case dom.IsElement(c, "tr"):
+			rows = append(rows, c)
+		case dom.IsElement(c, "thead", "tbody", "tfoot"):
+			for r := c.FirstChild; r != nil; r = r.NextSibling {
+				if dom.IsElement(r, "tr") {
+					rows = append(rows, r)
+				}
+			}
+		}
+	}
+	return rows
+}
+
+// tableCells returns the td and th cells of a table, excluding nested tables
+func tableCells(table *html.Node) []*html.Node {
+	var cells []*html.Node
+	for _, row := range tableRows(table) {
+		for c := row.FirstChild; c != nil; c = c.NextSibling {
+			if dom.IsElement(c, "td", "th") {
+				cells = append(cells, c)
+			}
+		}
+	}
+	return cells
+}
+
+func cellSpan(cell *html.Node, attr string) int {
+	span, err := strconv.Atoi(strings.TrimSpace(dom.Attr(cell, attr)))
+	if err != nil || span < 1 {
+		return 1
+	}
+	// rowspan="0" extends to the end of the section; one row is enough here
+	if span > 1000 {
+		return 1000
+	}
+	return span
+}
+
+// tableGrid lays the cells of a table out on a grid, repeating cells across
+// the slots their colspan and rowspan cover
+func tableGrid(table *html.Node) [][]*html.Node {
+	rows := tableRows(table)
+	grid := make([][]*html.Node, len(rows))
+	for r, row := range rows {
+		col := 0
+		for c := row.FirstChild; c != nil; c = c.NextSibling {
+			if !dom.IsElement(c, "td", "th") {
+				continue
+			}
+			for col < len(grid[r]) && grid[r][col] != nil {
+				col++
+			}
+			colspan, rowspan := cellSpan(c, "colspan"), cellSpan(c, "rowspan")
+			for dr := 0; dr < rowspan && r+dr < len(rows); dr++ {
+				for dc := 0; dc < colspan; dc++ {
+					for len(grid[r+dr]) <= col+dc {
+						grid[r+dr] = append(grid[r+dr], nil)
+					}
+					grid[r+dr][col+dc] = c
+				}
+			}
+			col += colspan
+		}
+	}
+	return grid
+}
+
+// isLayoutTable reports whether a table's role marks it as presentational
+func isLayoutTable(table *html.Node) bool {
+	role := aria.ExplicitRole(table)
+	return role == "presentation" || role == "none"
+}
+
+// hasNestedTable reports whether a table contains another table
+func hasNestedTable(table *html.Node) bool {
+	for _, e := range dom.Elements(table) {
+		if e != table && e.Data == "table" {
+			return true
+		}
+	}
+	return false
+}
+
+// isDataTable reports whether a table presents data rather than layout:
+// it is not presentational and has header cells
+func isDataTable(table *html.Node) bool {
+	if isLayoutTable(table) {
+		return false
+	}
+	for _, cell := range tableCells(table) {
+		if cell.Data == "th" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTableHeadersAttr checks that headers attributes refer to header
+// cells of the same table
+func checkTableHeadersAttr(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if dom.IsElement(n, "table") && !isLayoutTable(n) {
+		cells := tableCells(n)
+		inTable := make(map[string]*html.Node)
+		for _, cell := range cells {
+			if id := dom.Attr(cell, "id"); id != "" {
+				inTable[id] = cell
+			}
+		}
+
+		for _, cell := range cells {
+			headers, ok := dom.LookupAttr(cell, "headers")
+			if !ok {
+				continue
+			}
+
+			var broken []string
+			ids := strings.Fields(headers)
+			for _, id := range ids {
+				if header := inTable[id]; header == nil || header == cell {
+					broken = append(broken, id)
+				}
+			}
+
+			if len(broken) > 0 || len(ids) == 0 {
+				result.Violations = append(result.Violations, AccessibilityCheck{
+					ID:          "td-headers-attr",
+					Impact:      "serious",
+					Description: "Cell headers attribute does not refer to cells in the same table",
+					Help:        fmt.Sprintf("Each ID in a headers attribute must refer to another cell in the same table. Invalid: %q", strings.Join(broken, " ")),
+					HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/td-headers-attr",
+					Nodes:       []string{getNodeHTML(cell)},
+					Targets:     ctx.targets(cell),
+				})
+			} else {
+				result.Passes = append(result.Passes, AccessibilityCheck{
+					ID:          "td-headers-attr",
+					Description: "Cell headers attribute refers to cells in the same table",
+					Nodes:       []string{getNodeHTML(cell)},
+					Targets:     ctx.targets(cell),
+				})
+			}
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkTableHeadersAttr(ctx, c, result)
+	}
+}
+
+// headerDirection reports whether a header cell heads its column, its row,
+// or both, from its scope or its position in the grid
+func headerDirection(grid [][]*html.Node, cell *html.Node, row int) (column, rowHeader bool) {
+	switch strings.ToLower(strings.TrimSpace(dom.Attr(cell, "scope"))) {
+	case "col", "colgroup":
+		return true, false
+	case "row", "rowgroup":
+		return false, true
+	}
+	// A row made up only of header cells is a row of column headers
+	for _, c := range grid[row] {
+		if c != nil && c.Data != "th" {
+			return false, true
+		}
+	}
+	return true, false
+}
+
+// checkTableHeaderData checks that every header cell of a data table heads
+// at least one data cell
+func checkTableHeaderData(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if dom.IsElement(n, "table") && isDataTable(n) {
+		grid := tableGrid(n)
+		seen := make(map[*html.Node]bool)
+		for r, row := range grid {
+			for c, cell := range row {
+				if cell == nil || cell.Data != "th" || seen[cell] {
+					continue
+				}
+				seen[cell] = true
+
+				column, rowHeader := headerDirection(grid, cell, r)
+				var hasData bool
+				if column {
+					for _, other := range grid {
+						if c < len(other) && other[c] != nil && other[c].Data == "td" {
+							hasData = true
+							break
+						}
+					}
+				}
+				if rowHeader {
+					for _, other := range row {
+						if other != nil && other.Data == "td" {
+							hasData = true
+							break
+						}
+					}
+				}
+
+				if !hasData {
+					result.Violations = append(result.Violations, AccessibilityCheck{
+						ID:          "th-has-data-cells",
+						Impact:      "serious",
+						Description: "Table header does not describe any data cells",
+						Help:        "Each th element in a data table must refer to data cells in its row or column",
+						HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/th-has-data-cells",
+						Nodes:       []string{getNodeHTML(cell)},
+						Targets:     ctx.targets(cell),
+					})
+				} else {
+					result.Passes = append(result.Passes, AccessibilityCheck{
+						ID:          "th-has-data-cells",
+						Description: "Table header describes data cells",
+						Nodes:       []string{getNodeHTML(cell)},
+						Targets:     ctx.targets(cell),
+					})
+				}
+			}
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkTableHeaderData(ctx, c, result)
+	}
+}
+
+// checkTableCaption checks that data tables are identified by a caption or
+// another accessible name
+func checkTableCaption(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if dom.IsElement(n, "table") && isDataTable(n) && !ctx.Hidden(n) {
+		if ctx.Name(n) == "" {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "table-caption",
+				Impact:      "moderate",
+				Description: "Data table has no caption",
+				Help:        "Data tables should have a <caption>, aria-label or aria-labelledby that identifies them",
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/table-fake-caption",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		} else {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "table-caption",
+				Description: "Data table has a caption",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkTableCaption(ctx, c, result)
+	}
+}
+
+// checkScopeAttr checks that scope is only used on header cells, with a
+// valid value
+func checkScopeAttr(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if scope, ok := dom.LookupAttr(n, "scope"); ok && n.Type == html.ElementNode {
+		switch {
+		case n.Data != "th":
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "scope-attr-valid",
+				Impact:      "moderate",
+				Description: "scope attribute used on an element that is not a table header",
+				Help:        fmt.Sprintf("The scope attribute may only be used on th elements, found on <%s>", n.Data),
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/scope-attr-valid",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		case !containsRole([]string{"row", "col", "rowgroup", "colgroup"}, strings.ToLower(strings.TrimSpace(scope))):
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "scope-attr-valid",
+				Impact:      "moderate",
+				Description: "scope attribute has an invalid value",
+				Help:        fmt.Sprintf("The scope attribute must be row, col, rowgroup or colgroup, found %q", scope),
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/scope-attr-valid",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		default:
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "scope-attr-valid",
+				Description: "scope attribute is used correctly",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkScopeAttr(ctx, c, result)
+	}
+}
+
+// hasHeaderMarkup reports whether the header cells of a table are set up
+// for data: the table has a caption, header or footer rows, scope or headers
+// attributes, or header cells that share a row or column with data cells
+func hasHeaderMarkup(table *html.Node, grid [][]*html.Node) bool {
+	for _, e := range dom.Elements(table) {
+		if e == table || dom.Closest(e, "table") != table {
+			continue
+		}
+		if dom.IsElement(e, "caption", "thead", "tfoot") || (e.Data == "th" && dom.HasAttr(e, "scope")) || (e.Data == "td" && dom.HasAttr(e, "headers")) {
+			return true
+		}
+	}
+	for _, row := range grid {
+		for c, cell := range row {
+			if cell == nil || cell.Data != "th" {
+				continue
+			}
+			for _, other := range row {
+				if other != nil && other.Data == "td" {
+					return true
+				}
+			}
+			for _, other := range grid {
+				if c < len(other) && other[c] != nil && other[c].Data == "td" {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// looksLikeLayout reports whether a table without a presentational role is
+// structured for layout: it wraps other tables or has a single row or
+// column. A data table with header markup is never layout, however small.
+func looksLikeLayout(table *html.Node) bool {
+	grid := tableGrid(table)
+	if isDataTable(table) && hasHeaderMarkup(table, grid) {
+		return false
+	}
+	if hasNestedTable(table) {
+		return true
+	}
+	if len(grid) <= 1 {
+		return true
+	}
+	for _, row := range grid {
+		if len(row) > 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// checkLayoutTableHeaders checks that tables used for page layout do not
+// use th cells for their visual styling
+func checkLayoutTableHeaders(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if dom.IsElement(n, "table") && !isLayoutTable(n) && looksLikeLayout(n) {
+		var headers []*html.Node
+		for _, cell := range tableCells(n) {
+			if cell.Data == "th" {
+				headers = append(headers, cell)
+			}
+		}
+
+		if len(headers) > 0 {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "layout-table-th",
+				Impact:      "serious",
+				Description: "Layout table uses header cells",
+				Help:        fmt.Sprintf("Tables used for layout must not use th elements. Found %d th element(s); use td with CSS instead", len(headers)),
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/layout-table",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		} else {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "layout-table-th",
+				Description: "Layout table does not use header cells",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkLayoutTableHeaders(ctx, c, result)
+	}
+}
+
+// checkNestedTables checks that tables are not nested inside other tables
+func checkNestedTables(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if dom.IsElement(n, "table") {
+		if outer := dom.Closest(n, "table"); outer != nil {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "table-nested",
+				Impact:      "moderate",
+				Description: "Table is nested inside another table",
+				Help:        "Tables should not be nested; screen readers cannot convey the relationship between nested table cells",
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/layout-table",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		} else if !hasNestedTable(n) {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "table-nested",
+				Description: "Table does not contain nested tables",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkNestedTables(ctx, c, result)
+	}
+}
+
+// checkPresentationTables checks that tables with a presentation or none
+// role do not contain data table markup, which the role would hide
+func checkPresentationTables(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if dom.IsElement(n, "table") && isLayoutTable(n) {
+		var semantics []string
+		seen := make(map[string]bool)
+		add := func(what string) {
+			if !seen[what] {
+				seen[what] = true
+				semantics = append(semantics, what)
+			}
+		}
+		if dom.HasAttr(n, "summary") {
+			add("summary")
+		}
+		for _, e := range dom.Elements(n) {
+			if e != n && dom.Closest(e, "table") != n {
+				continue
+			}
+			if dom.IsElement(e, dataTableElements...) {
+				add("<" + e.Data + ">")
+			}
+			if dom.IsElement(e, "td", "th") {
+				for _, attr := range []string{"headers", "scope", "abbr"} {
+					if dom.HasAttr(e, attr) {
+						add(attr)
+					}
+				}
+			}
+		}
+
+		if len(semantics) > 0 {
+			result.Violations = append(result.Violations, AccessibilityCheck{
+				ID:          "table-presentation-role",
+				Impact:      "serious",
+				Description: "Presentational table contains data table markup",
+				Help:        fmt.Sprintf("Tables with role=%q must not use data table markup: %s", aria.ExplicitRole(n), strings.Join(semantics, ", ")),
+				HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/presentation-role-conflict",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		} else {
+			result.Passes = append(result.Passes, AccessibilityCheck{
+				ID:          "table-presentation-role",
+				Description: "Presentational table has no data table markup",
+				Nodes:       []string{getNodeHTML(n)},
+				Targets:     ctx.targets(n),
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkPresentationTables(ctx, c, result)
+	}
+}
+
+// isComplexTable reports whether a data table has headers that scope alone
+// cannot associate: several rows of column headers, several columns of row
+// headers, or header cells spanning rows or columns
+func isComplexTable(grid [][]*html.Node) bool {
+	headerRows := 0
+	for _, row := range grid {
+		allHeaders := len(row) > 0
+		for _, cell := range row {
+			if cell == nil {
+				continue
+			}
+			if cell.Data == "th" && (cellSpan(cell, "colspan") > 1 || cellSpan(cell, "rowspan") > 1) {
+				return true
+			}
+			if cell.Data != "th" {
+				allHeaders = false
+			}
+		}
+		if allHeaders {
+			headerRows++
+		}
+	}
+	if headerRows > 1 {
+		return true
+	}
+
+	// Count the leading header columns of rows that contain data
+	for _, row := range grid {
+		leading := 0
+		for _, cell := range row {
+			if cell == nil || cell.Data != "th" {
+				break
+			}
+			leading++
+		}
+		if leading > 1 && leading < len(row) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkComplexTables checks that complex data tables associate every data
+// cell with its headers through headers and id attributes
+func checkComplexTables(ctx *ScanContext, n *html.Node, result *ScanResult) {
+	if dom.IsElement(n, "table") && isDataTable(n) {
+		if grid := tableGrid(n); isComplexTable(grid) {
+			var missing []*html.Node
+			for _, cell := range tableCells(n) {
+				if cell.Data == "td" && strings.TrimSpace(dom.Attr(cell, "headers")) == "" && dom.Text(cell) != "" {
+					missing = append(missing, cell)
+				}
+			}
+
+			if len(missing) > 0 {
+				result.Violations = append(result.Violations, AccessibilityCheck{
+					ID:          "table-complex-headers",
+					Impact:      "serious",
+					Description: "Complex table does not associate data cells with headers",
+					Help:        fmt.Sprintf("Tables with multiple levels of headers must use headers and id attributes. %d data cell(s) have no headers attribute", len(missing)),
+					HelpURL:     "https://dequeuniversity.com/rules/axe/4.6/td-has-header",
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			} else {
+				result.Passes = append(result.Passes, AccessibilityCheck{
+					ID:          "table-complex-headers",
+					Description: "Complex table associates data cells with headers",
+					Nodes:       []string{getNodeHTML(n)},
+					Targets:     ctx.targets(n),
+				})
+			}
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		checkComplexTables(ctx, c, result)
+	}
+}
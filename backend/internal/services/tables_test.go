@@ -0,0 +1,36 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLayoutTableHeaders(t *testing.T) {
+	tests := []struct {
+		name   string
+		table  string
+		layout bool
+	}{
+		{"captioned single row", `<table><caption>Opening hours</caption><tr><th>Monday</th><td>9-17</td></tr></table>`, false},
+		{"single row heading data", `<table><tr><th>Total</th><td>42</td></tr></table>`, false},
+		{"single column with scope", `<table><tr><th scope="col">Name</th></tr></table>`, false},
+		{"single row of headers", `<table><tr><th>Home</th><th>About</th><th>Contact</th></tr></table>`, true},
+		{"wrapper of another table", `<table><tr><th>Menu</th><th><table><tr><td>Content</td></tr></table></th></tr></table>`, true},
+	}
+	for _, tt := range tests {
+		page := `<html lang="en"><body>` + tt.table + `</body></html>`
+		result, err := NewScanner().ScanHTML(strings.NewReader(page), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		flagged := false
+		for _, v := range result.Violations {
+			if v.ID == "layout-table-th" {
+				flagged = true
+			}
+		}
+		if flagged != tt.layout {
+			t.Errorf("%s: layout-table-th flagged = %t, want %t", tt.name, flagged, tt.layout)
+		}
+	}
+}
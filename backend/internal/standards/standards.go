@@ -0,0 +1,180 @@
+// Package standards defines the accessibility standards a compliance report
+// can be generated against. Each profile incorporates a set of WCAG success
+// criteria, possibly under its own clause numbers, plus requirements of its own.
+package standards
+
+import (
+	"fmt"
+
+	"tokubetsu/internal/wcag"
+)
+
+// DefaultProfile is used when a project has not chosen a profile
+const DefaultProfile = "wcag22-aa"
+
+// Profile is an accessibility standard reports can be measured against
+type Profile struct {
+	ID           string        `json:"id"`
+	Name         string        `json:"name"`
+	Description  string        `json:"description"`
+	Level        string        `json:"level"`        // Highest WCAG level the profile requires
+	WCAG         string        `json:"wcag_version"` // WCAG version the profile incorporates
+	Requirements []Requirement `json:"requirements"`
+}
+
+// Requirement is a clause of a profile. Requirements that incorporate a WCAG
+// success criterion set Criterion; additions of the standard itself leave it
+// empty and list the rules that test them, if any.
+type Requirement struct {
+	ID        string   `json:"id"` // Clause number in the standard, e.g. "9.1.1.1"
+	Title     string   `json:"title"`
+	Criterion string   `json:"criterion,omitempty"`
+	Level     string   `json:"level,omitempty"` // WCAG level of incorporated criteria
+	Principle string   `json:"principle"`
+	Rules     []string `json:"rules,omitempty"`
+}
+
+// Key is the key results of the requirement are stored under: the WCAG
+// criterion it incorporates, or its own clause number
+func (r Requirement) Key() string {
+	if r.Criterion != "" {
+		return r.Criterion
+	}
+	return r.ID
+}
+
+var (
+	profiles []*Profile
+	index    = make(map[string]*Profile)
+)
+
+func init() {
+	register(&Profile{
+		ID:          "wcag22-aa",
+		Name:        "WCAG 2.2 Level AA",
+		Description: "Web Content Accessibility Guidelines 2.2, Level A and AA success criteria",
+		Level:       wcag.LevelAA,
+		WCAG:        "2.2",
+	}, wcagClause, nil)
+	register(&Profile{
+		ID:          "wcag22-aaa",
+		Name:        "WCAG 2.2 Level AAA",
+		Description: "Web Content Accessibility Guidelines 2.2, all success criteria",
+		Level:       wcag.LevelAAA,
+		WCAG:        "2.2",
+	}, wcagClause, nil)
+	register(&Profile{
+		ID:          "wcag21-aa",
+		Name:        "WCAG 2.1 Level AA",
+		Description: "Web Content Accessibility Guidelines 2.1, Level A and AA success criteria",
+		Level:       wcag.LevelAA,
+		WCAG:        "2.1",
+	}, wcagClause, nil)
+	register(&Profile{
+		ID:   "section508",
+		Name: "Revised Section 508",
+		Description: "Revised Section 508 standards (36 CFR 1194). Web content must conform to " +
+			"WCAG 2.0 Level A and AA (E205.4), plus the requirements for media players of Chapter 5",
+		Level: wcag.LevelAA,
+		WCAG:  "2.0",
+	}, func(c wcag.Criterion) string { return "E205.4 " + c.Number }, []Requirement{
+		{ID: "503.4.1", Title: "Caption Controls", Principle: wcag.PrinciplePerceivable},
+		{ID: "503.4.2", Title: "Audio Description Controls", Principle: wcag.PrinciplePerceivable},
+		{ID: "602.3", Title: "Electronic Support Documentation", Principle: wcag.PrincipleUnderstandable},
+	})
+	register(&Profile{
+		ID:   "en301549",
+		Name: "EN 301 549 V3.2.1",
+		Description: "European accessibility requirements for ICT products and services. Clause 9 incorporates " +
+			"WCAG 2.1 Level A and AA for web pages, clause 7 adds requirements for video with sound",
+		Level: wcag.LevelAA,
+		WCAG:  "2.1",
+	}, func(c wcag.Criterion) string { return "9." + c.Number }, []Requirement{
+		{ID: "7.1.1", Title: "Captioning playback", Principle: wcag.PrinciplePerceivable, Rules: []string{"video-caption"}},
+		{ID: "7.1.2", Title: "Captioning synchronization", Principle: wcag.PrinciplePerceivable},
+		{ID: "7.2.1", Title: "Audio description playback", Principle: wcag.PrinciplePerceivable},
+		{ID: "7.3", Title: "User controls for captions and audio description", Principle: wcag.PrinciplePerceivable},
+		{ID: "12.1.1", Title: "Accessibility and compatibility features", Principle: wcag.PrincipleUnderstandable},
+	})
+	register(&Profile{
+		ID:   "ada-title-ii",
+		Name: "ADA Title II",
+		Description: "Americans with Disabilities Act Title II rule for state and local government web content " +
+			"(28 CFR 35.200), which requires WCAG 2.1 Level A and AA",
+		Level: wcag.LevelAA,
+		WCAG:  "2.1",
+	}, wcagClause, nil)
+}
+
+// register adds a profile with the success criteria of its WCAG version and
+// level, numbered by clause, followed by the additions of the standard
+func register(profile *Profile, clause func(wcag.Criterion) string, additions []Requirement) {
+	if _, exists := index[profile.ID]; exists {
+		panic(fmt.Sprintf("profile %q is already registered", profile.ID))
+	}
+	profile.Requirements = append(incorporate(profile.WCAG, profile.Level, clause), additions...)
+	profiles = append(profiles, profile)
+	index[profile.ID] = profile
+}
+
+func wcagClause(c wcag.Criterion) string { return c.Number }
+
+// incorporate lists the success criteria of a WCAG version up to a level as
+// requirements, numbered by clause. Criteria the version made obsolete are
+// left out.
+func incorporate(version, level string, clause func(wcag.Criterion) string) []Requirement {
+	var requirements []Requirement
+	for _, c := range wcag.All() {
+		if !c.InVersion(version) || !withinLevel(c.Level, level) {
+			continue
+		}
+		requirements = append(requirements, Requirement{
+			ID:        clause(c),
+			Title:     c.Title,
+			Criterion: c.Number,
+			Level:     c.Level,
+			Principle: c.Principle,
+		})
+	}
+	return requirements
+}
+
+func withinLevel(level, target string) bool {
+	switch target {
+	case wcag.LevelA:
+		return level == wcag.LevelA
+	case wcag.LevelAA:
+		return level == wcag.LevelA || level == wcag.LevelAA
+	}
+	return true
+}
+
+// All returns every profile
+func All() []*Profile {
+	return append([]*Profile(nil), profiles...)
+}
+
+// Lookup returns the profile with the given ID
+func Lookup(id string) (*Profile, bool) {
+	profile, ok := index[id]
+	return profile, ok
+}
+
+// Resolve returns the profile with the given ID, or the default profile
+// when id is empty or unknown
+func Resolve(id string) *Profile {
+	if profile, ok := index[id]; ok {
+		return profile
+	}
+	return index[DefaultProfile]
+}
+
+// Requirement returns the requirement stored under key, see Requirement.Key
+func (p *Profile) Requirement(key string) (Requirement, bool) {
+	for _, r := range p.Requirements {
+		if r.Key() == key {
+			return r, true
+		}
+	}
+	return Requirement{}, false
+}
@@ -0,0 +1,28 @@
+package standards
+
+import "testing"
+
+func TestParsingByVersion(t *testing.T) {
+	for id, want := range map[string]string{
+		"wcag22-aa":    "",
+		"wcag22-aaa":   "",
+		"wcag21-aa":    "4.1.1",
+		"ada-title-ii": "4.1.1",
+		"section508":   "E205.4 4.1.1",
+		"en301549":     "9.4.1.1",
+	} {
+		profile, ok := Lookup(id)
+		if !ok {
+			t.Fatalf("profile %q is not registered", id)
+		}
+		requirement, ok := profile.Requirement("4.1.1")
+		switch {
+		case want == "" && ok:
+			t.Errorf("%s incorporates 4.1.1 Parsing, which WCAG 2.2 made obsolete", id)
+		case want != "" && !ok:
+			t.Errorf("%s does not incorporate 4.1.1 Parsing", id)
+		case ok && requirement.ID != want:
+			t.Errorf("%s numbers 4.1.1 as %q, want %q", id, requirement.ID, want)
+		}
+	}
+}
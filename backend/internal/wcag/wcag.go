@@ -0,0 +1,210 @@
+// Package wcag lists the success criteria of WCAG 2.0 to 2.2
+package wcag
+
+import "strings"
+
+// Conformance levels
+const (
+	LevelA   = "A"
+	LevelAA  = "AA"
+	LevelAAA = "AAA"
+)
+
+// Principles
+const (
+	PrinciplePerceivable    = "perceivable"
+	PrincipleOperable       = "operable"
+	PrincipleUnderstandable = "understandable"
+	PrincipleRobust         = "robust"
+)
+
+// Criterion is a WCAG success criterion
+type Criterion struct {
+	Number    string `json:"number"` // e.g. "1.4.3"
+	Title     string `json:"title"`
+	Level     string `json:"level"`
+	Principle string `json:"principle"`
+	Guideline string `json:"guideline"`         // e.g. "1.4"
+	Version   string `json:"version"`           // WCAG version that introduced the criterion
+	Removed   string `json:"removed,omitempty"` // WCAG version that made the criterion obsolete
+	URL       string `json:"url"`               // Understanding document
+}
+
+// InVersion reports whether the criterion is part of a WCAG version
+func (c Criterion) InVersion(version string) bool {
+	return c.Version <= version && (c.Removed == "" || version < c.Removed)
+}
+
+var (
+	criteria []Criterion
+	index    = make(map[string]int)
+)
+
+var table = []struct{ number, title, level string }{
+	{"1.1.1", "Non-text Content", LevelA},
+	{"1.2.1", "Audio-only and Video-only (Prerecorded)", LevelA},
+	{"1.2.2", "Captions (Prerecorded)", LevelA},
+	{"1.2.3", "Audio Description or Media Alternative (Prerecorded)", LevelA},
+	{"1.2.4", "Captions (Live)", LevelAA},
+	{"1.2.5", "Audio Description (Prerecorded)", LevelAA},
+	{"1.2.6", "Sign Language (Prerecorded)", LevelAAA},
+	{"1.2.7", "Extended Audio Description (Prerecorded)", LevelAAA},
+	{"1.2.8", "Media Alternative (Prerecorded)", LevelAAA},
+	{"1.2.9", "Audio-only (Live)", LevelAAA},
+	{"1.3.1", "Info and Relationships", LevelA},
+	{"1.3.2", "Meaningful Sequence", LevelA},
+	{"1.3.3", "Sensory Characteristics", LevelA},
+	{"1.3.4", "Orientation", LevelAA},
+	{"1.3.5", "Identify Input Purpose", LevelAA},
+	{"1.3.6", "Identify Purpose", LevelAAA},
+	{"1.4.1", "Use of Color", LevelA},
+	{"1.4.2", "Audio Control", LevelA},
+	{"1.4.3", "Contrast (Minimum)", LevelAA},
+	{"1.4.4", "Resize Text", LevelAA},
+	{"1.4.5", "Images of Text", LevelAA},
+	{"1.4.6", "Contrast (Enhanced)", LevelAAA},
+	{"1.4.7", "Low or No Background Audio", LevelAAA},
+	{"1.4.8", "Visual Presentation", LevelAAA},
+	{"1.4.9", "Images of Text (No Exception)", LevelAAA},
+	{"1.4.10", "Reflow", LevelAA},
+	{"1.4.11", "Non-text Contrast", LevelAA},
+	{"1.4.12", "Text Spacing", LevelAA},
+	{"1.4.13", "Content on Hover or Focus", LevelAA},
+	{"2.1.1", "Keyboard", LevelA},
+	{"2.1.2", "No Keyboard Trap", LevelA},
+	{"2.1.3", "Keyboard (No Exception)", LevelAAA},
+	{"2.1.4", "Character Key Shortcuts", LevelA},
+	{"2.2.1", "Timing Adjustable", LevelA},
+	{"2.2.2", "Pause, Stop, Hide", LevelA},
+	{"2.2.3", "No Timing", LevelAAA},
+	{"2.2.4", "Interruptions", LevelAAA},
+	{"2.2.5", "Re-authenticating", LevelAAA},
+	{"2.2.6", "Timeouts", LevelAAA},
+	{"2.3.1", "Three Flashes or Below Threshold", LevelA},
+	{"2.3.2", "Three Flashes", LevelAAA},
+	{"2.3.3", "Animation from Interactions", LevelAAA},
+	{"2.4.1", "Bypass Blocks", LevelA},
+	{"2.4.2", "Page Titled", LevelA},
+	{"2.4.3", "Focus Order", LevelA},
+	{"2.4.4", "Link Purpose (In Context)", LevelA},
+	{"2.4.5", "Multiple Ways", LevelAA},
+	{"2.4.6", "Headings and Labels", LevelAA},
+	{"2.4.7", "Focus Visible", LevelAA},
+	{"2.4.8", "Location", LevelAAA},
+	{"2.4.9", "Link Purpose (Link Only)", LevelAAA},
+	{"2.4.10", "Section Headings", LevelAAA},
+	{"2.4.11", "Focus Not Obscured (Minimum)", LevelAA},
+	{"2.4.12", "Focus Not Obscured (Enhanced)", LevelAAA},
+	{"2.4.13", "Focus Appearance", LevelAAA},
+	{"2.5.1", "Pointer Gestures", LevelA},
+	{"2.5.2", "Pointer Cancellation", LevelA},
+	{"2.5.3", "Label in Name", LevelA},
+	{"2.5.4", "Motion Actuation", LevelA},
+	{"2.5.5", "Target Size (Enhanced)", LevelAAA},
+	{"2.5.6", "Concurrent Input Mechanisms", LevelAAA},
+	{"2.5.7", "Dragging Movements", LevelAA},
+	{"2.5.8", "Target Size (Minimum)", LevelAA},
+	{"3.1.1", "Language of Page", LevelA},
+	{"3.1.2", "Language of Parts", LevelAA},
+	{"3.1.3", "Unusual Words", LevelAAA},
+	{"3.1.4", "Abbreviations", LevelAAA},
+	{"3.1.5", "Reading Level", LevelAAA},
+	{"3.1.6", "Pronunciation", LevelAAA},
+	{"3.2.1", "On Focus", LevelA},
+	{"3.2.2", "On Input", LevelA},
+	{"3.2.3", "Consistent Navigation", LevelAA},
+	{"3.2.4", "Consistent Identification", LevelAA},
+	{"3.2.5", "Change on Request", LevelAAA},
+	{"3.2.6", "Consistent Help", LevelA},
+	{"3.3.1", "Error Identification", LevelA},
+	{"3.3.2", "Labels or Instructions", LevelA},
+	{"3.3.3", "Error Suggestion", LevelAA},
+	{"3.3.4", "Error Prevention (Legal, Financial, Data)", LevelAA},
+	{"3.3.5", "Help", LevelAAA},
+	{"3.3.6", "Error Prevention (All)", LevelAAA},
+	{"3.3.7", "Redundant Entry", LevelA},
+	{"3.3.8", "Accessible Authentication (Minimum)", LevelAA},
+	{"3.3.9", "Accessible Authentication (Enhanced)", LevelAAA},
+	{"4.1.1", "Parsing", LevelA},
+	{"4.1.2", "Name, Role, Value", LevelA},
+	{"4.1.3", "Status Messages", LevelAA},
+}
+
+// Criteria added after WCAG 2.0
+var added = map[string]string{
+	"1.3.4": "2.1", "1.3.5": "2.1", "1.3.6": "2.1", "1.4.10": "2.1", "1.4.11": "2.1",
+	"1.4.12": "2.1", "1.4.13": "2.1", "2.1.4": "2.1", "2.2.6": "2.1", "2.3.3": "2.1",
+	"2.5.1": "2.1", "2.5.2": "2.1", "2.5.3": "2.1", "2.5.4": "2.1", "2.5.5": "2.1",
+	"2.5.6": "2.1", "4.1.3": "2.1",
+	"2.4.11": "2.2", "2.4.12": "2.2", "2.4.13": "2.2", "2.5.7": "2.2", "2.5.8": "2.2",
+	"3.2.6": "2.2", "3.3.7": "2.2", "3.3.8": "2.2", "3.3.9": "2.2",
+}
+
+// Criteria made obsolete by a later version. 4.1.1 Parsing is no longer part
+// of WCAG 2.2, but still required by standards that incorporate 2.0 or 2.1.
+var removed = map[string]string{
+	"4.1.1": "2.2",
+}
+
+var principles = map[string]string{
+	"1": PrinciplePerceivable,
+	"2": PrincipleOperable,
+	"3": PrincipleUnderstandable,
+	"4": PrincipleRobust,
+}
+
+func init() {
+	for _, entry := range table {
+		parts := strings.Split(entry.number, ".")
+		version, ok := added[entry.number]
+		if !ok {
+			version = "2.0"
+		}
+		index[entry.number] = len(criteria)
+		criteria = append(criteria, Criterion{
+			Number:    entry.number,
+			Title:     entry.title,
+			Level:     entry.level,
+			Principle: principles[parts[0]],
+			Guideline: parts[0] + "." + parts[1],
+			Version:   version,
+			Removed:   removed[entry.number],
+			URL:       "https://www.w3.org/WAI/WCAG22/Understanding/" + slug(entry.title),
+		})
+	}
+}
+
+// slug turns a criterion title into the name of its Understanding document,
+// e.g. "Contrast (Minimum)" becomes "contrast-minimum"
+func slug(title string) string {
+	title = strings.NewReplacer("(", "", ")", "", ",", "").Replace(strings.ToLower(title))
+	return strings.Join(strings.Fields(title), "-")
+}
+
+// All returns every success criterion in specification order
+func All() []Criterion {
+	return append([]Criterion(nil), criteria...)
+}
+
+// Lookup returns the success criterion with the given number
+func Lookup(number string) (Criterion, bool) {
+	i, ok := index[number]
+	if !ok {
+		return Criterion{}, false
+	}
+	return criteria[i], true
+}
+
+// Less reports whether success criterion a comes before b in specification order.
+// Unknown numbers sort after known ones.
+func Less(a, b string) bool {
+	i, aok := index[a]
+	j, bok := index[b]
+	switch {
+	case aok && bok:
+		return i < j
+	case aok != bok:
+		return aok
+	}
+	return a < b
+}
@@ -43,13 +43,13 @@ interface ComplianceReportData {
   url: string;
   generatedAt: string;
   overallScore: number;
-  levelAScore: number;
-  levelAAScore: number;
-  levelAAAScore: number;
-  perceivableScore: number;
-  operableScore: number;
-  understandableScore: number;
-  robustScore: number;
+  levelAScore: number | null;
+  levelAAScore: number | null;
+  levelAAAScore: number | null;
+  perceivableScore: number | null;
+  operableScore: number | null;
+  understandableScore: number | null;
+  robustScore: number | null;
   violations: ComplianceViolation[];
 }
 
@@ -93,7 +93,8 @@ const getImpactColor = (impact: string) => {
   }
 };
 
-const ScoreIndicator: React.FC<{ score: number; label: string }> = ({ score = 0, label }) => {
+// A null score means there was nothing to score, e.g. Level AAA in an AA report
+const ScoreIndicator: React.FC<{ score: number | null; label: string }> = ({ score = 0, label }) => {
   const theme = useTheme();
   
   const getColor = (score: number) => {
@@ -108,6 +109,19 @@ const ScoreIndicator: React.FC<{ score: number; label: string }> = ({ score = 0,
     return <ErrorIcon color="error" />;
   };
 
+  if (score === null) {
+    return (
+      <Box sx={{ mb: 2 }}>
+        <Box sx={{ display: 'flex', alignItems: 'center', mb: 1 }}>
+          <Typography variant="subtitle1" sx={{ flexGrow: 1 }}>{label}</Typography>
+          <Typography variant="body2" color="text.secondary">
+            Nothing to score
+          </Typography>
+        </Box>
+      </Box>
+    );
+  }
+
   // Ensure score is a number
   const safeScore = typeof score === 'number' ? score : 0;
 
@@ -36,7 +36,8 @@ export const SimulationControls: React.FC<SimulationControlsProps> = ({ onClose
   // Apply color blindness simulation
   useEffect(() => {
     if (colorBlindness) {
-      // Create SVG filters for different types of color blindness
+      // Create SVG filters for different types of color blindness.
+      // The scanner applies the same matrices in backend/internal/cvd.
       const svgFilters = document.createElement('div');
       svgFilters.innerHTML = `
         <svg style="display: none">
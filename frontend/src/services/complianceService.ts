@@ -1,5 +1,14 @@
 import api from './api';
-import { ComplianceReportItem } from '../types/compliance';
+import {
+  AccessibilityConformanceReport,
+  ComplianceReportItem,
+  ManualCheck,
+  ManualCheckChange,
+  ManualEvidence,
+  ManualResult,
+  StandardsProfile,
+  StandardsProfileSummary,
+} from '../types/compliance';
 
 export interface ComplianceRule {
   id: string;
@@ -25,13 +34,13 @@ export interface ComplianceReport {
   url: string;
   generatedAt: string;
   overallScore: number;
-  levelAScore: number;
-  levelAAScore: number;
-  levelAAAScore: number;
-  perceivableScore: number;
-  operableScore: number;
-  understandableScore: number;
-  robustScore: number;
+  levelAScore: number | null;
+  levelAAScore: number | null;
+  levelAAAScore: number | null;
+  perceivableScore: number | null;
+  operableScore: number | null;
+  understandableScore: number | null;
+  robustScore: number | null;
   violations: ComplianceViolation[];
 }
 
@@ -79,10 +88,22 @@ export const complianceService = {
     return response.data;
   },
 
-  async generateComplianceReport(projectId: string): Promise<ComplianceReportItem> {
+  async generateComplianceReport(projectId: string, profile?: string): Promise<ComplianceReportItem> {
     // This likely should have a different response type or handle it appropriately
     // if the backend returns something specific upon generation (e.g., the new report or a job ID)
-    const response = await api.post(`/api/projects/${projectId}/compliance`);
+    const response = await api.post(`/api/projects/${projectId}/compliance`, null, {
+      params: profile ? { profile } : undefined,
+    });
+    return response.data;
+  },
+
+  async getProfiles(): Promise<StandardsProfileSummary[]> {
+    const response = await api.get('/api/profiles');
+    return response.data;
+  },
+
+  async getProfile(profileId: string): Promise<StandardsProfile> {
+    const response = await api.get(`/api/profiles/${profileId}`);
     return response.data;
   },
 
@@ -104,5 +125,58 @@ export const complianceService = {
   async getReport(reportId: string): Promise<ComplianceReport> {
     const response = await api.get(`/api/compliance/${reportId}`);
     return toCamelCaseReport(response.data);
+  },
+
+  async getACR(reportId: string): Promise<AccessibilityConformanceReport> {
+    const response = await api.get(`/api/compliance/${reportId}/acr`, { params: { format: 'json' } });
+    return response.data;
+  },
+
+  // Downloads the ACR as an HTML or Word document
+  async downloadACR(reportId: string, format: 'html' | 'docx'): Promise<Blob> {
+    const response = await api.get(`/api/compliance/${reportId}/acr`, {
+      params: { format },
+      responseType: 'blob',
+    });
+    return response.data;
+  },
+
+  async getChecklist(reportId: string): Promise<{ total: number; audited: number; checklist: ManualCheck[] }> {
+    const response = await api.get(`/api/compliance/${reportId}/checklist`);
+    return response.data;
+  },
+
+  async updateManualCheck(
+    reportId: string,
+    criterion: string,
+    update: { result?: ManualResult; notes?: string }
+  ): Promise<{ check: ManualCheck; conformance_level: string; overall_score: number }> {
+    const response = await api.put(`/api/compliance/${reportId}/checklist/${criterion}`, update);
+    return response.data;
+  },
+
+  async getManualCheckHistory(reportId: string, criterion: string): Promise<ManualCheckChange[]> {
+    const response = await api.get(`/api/compliance/${reportId}/checklist/${criterion}/history`);
+    return response.data;
+  },
+
+  async addEvidence(reportId: string, criterion: string, file: File, description?: string): Promise<ManualEvidence> {
+    const form = new FormData();
+    form.append('file', file);
+    if (description) {
+      form.append('description', description);
+    }
+    const response = await api.post(`/api/compliance/${reportId}/checklist/${criterion}/evidence`, form);
+    return response.data;
+  },
+
+  async deleteEvidence(reportId: string, criterion: string, evidenceId: string): Promise<void> {
+    await api.delete(`/api/compliance/${reportId}/checklist/${criterion}/evidence/${evidenceId}`);
+  },
+
+  // Empty remarks restore the generated text
+  async updateACRRemarks(reportId: string, remarks: Record<string, string>): Promise<AccessibilityConformanceReport> {
+    const response = await api.put(`/api/compliance/${reportId}/acr/remarks`, { remarks });
+    return response.data;
   }
 }; 
\ No newline at end of file
@@ -1,5 +1,5 @@
 import api from './api';
-import { Project, CreateProjectInput, UpdateProjectInput } from '../types/project';
+import { Project, CreateProjectInput, UpdateProjectInput, FetchSettings } from '../types/project';
 
 export const projectService = {
   async getProjects(): Promise<Project[]> {
@@ -26,6 +26,16 @@ export const projectService = {
     await api.delete(`/api/projects/${id}`);
   },
 
+  async getFetchSettings(id: string): Promise<FetchSettings> {
+    const response = await api.get(`/api/projects/${id}/fetch-settings`);
+    return response.data;
+  },
+
+  async updateFetchSettings(id: string, settings: FetchSettings): Promise<FetchSettings> {
+    const response = await api.put(`/api/projects/${id}/fetch-settings`, settings);
+    return response.data;
+  },
+
   async runScan(id: string): Promise<{ score: number }> {
     console.log('=== Project Scan Request Details ===');
     console.log('Project ID:', id);
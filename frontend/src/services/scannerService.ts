@@ -6,6 +6,15 @@ export interface ScanResult {
   violations: Violation[];
   passes: number;
   score: number;
+  readability?: Readability;
+  colorVision?: Record<ColorVisionDeficiency, ColorVisionFinding[]>;
+}
+
+export interface NodeTarget {
+  selector: string;
+  xpath: string;
+  line?: number; // 1-based source line, absent when unknown
+  column?: number; // 1-based source column, absent when unknown
 }
 
 export interface Violation {
@@ -13,10 +22,60 @@ export interface Violation {
   impact: 'minor' | 'moderate' | 'serious' | 'critical';
   description: string;
   nodes: string[];
+  targets: NodeTarget[]; // Locators of nodes, index for index
   help: string;
   helpUrl: string;
 }
 
+export interface ReadabilityMetrics {
+  words: number;
+  sentences: number;
+  syllables: number;
+  longWords: number;
+  wordsPerSentence: number;
+  syllablesPerWord: number;
+  readingEase?: number; // Flesch reading ease or its adaptation to the page language
+  formula?: string; // e.g. 'Flesch', 'Amstad'
+  grade?: number; // Flesch-Kincaid grade, English only
+  lix?: number;
+  level: string; // e.g. 'fairly difficult'
+}
+
+export interface ReadabilitySection {
+  heading?: string;
+  level?: number;
+  target?: NodeTarget;
+  metrics: ReadabilityMetrics;
+}
+
+export interface AmbiguousLink {
+  text: string;
+  reason: 'generic' | 'different-destinations';
+  targets: NodeTarget[];
+}
+
+export interface Readability {
+  lang: string;
+  metrics: ReadabilityMetrics;
+  sections: ReadabilitySection[];
+  abbreviations: string[]; // Used without an expansion
+  ambiguousLinks: AmbiguousLink[];
+}
+
+export type ColorVisionDeficiency = 'protanopia' | 'deuteranopia' | 'tritanopia' | 'achromatopsia';
+
+// A text color pair that meets AA contrast, but not under a color vision deficiency
+export interface ColorVisionFinding {
+  foreground: string;
+  background: string;
+  contrast: number;
+  simulatedForeground: string;
+  simulatedBackground: string;
+  simulatedContrast: number;
+  required: number; // AA minimum for the text size
+  targets: NodeTarget[];
+}
+
 interface RawViolation {
   id?: string;
   impact?: string;
@@ -24,6 +83,7 @@ interface RawViolation {
   help?: string;
   helpUrl?: string;
   nodes?: string[];
+  targets?: NodeTarget[];
 }
 
 export const scannerService = {
@@ -80,7 +140,8 @@ export const scannerService = {
           helpUrl: violation.helpUrl || '#',
           nodes: Array.isArray(violation.nodes) 
             ? violation.nodes
-            : ['Unknown element']
+            : ['Unknown element'],
+          targets: Array.isArray(violation.targets) ? violation.targets : []
         };
       });
 
@@ -89,7 +150,9 @@ export const scannerService = {
         timestamp: new Date().toISOString(),
         violations: validatedViolations,
         passes,
-        score
+        score,
+        readability: response.data.readability ?? undefined,
+        colorVision: response.data.color_vision ?? undefined
       };
     } catch (error: any) {
       console.error('Scan Error Details:', {
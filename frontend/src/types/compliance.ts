@@ -1,31 +1,177 @@
+import { ScoringMethod } from './project';
+
 export interface ComplianceViolationItem {
   ID: string; // UUID from Base
   report_id: string; // UUID
   rule_id: string;
   wcag_level: string;
-  criterion: string;
+  criterion: string; // Primary WCAG 2.2 success criterion, e.g. "1.4.3"
+  criteria: string[]; // Every success criterion the rule tests
   impact: string;
   description: string;
   element: string;
+  selector: string; // Unique CSS selector of the element
+  xpath: string;
+  line: number; // 1-based source line, 0 when unknown
+  column: number; // 1-based source column, 0 when unknown
   suggestion?: string;
   CreatedAt: string; // ISO Date string from Base
   UpdatedAt: string; // ISO Date string from Base
 }
 
+export interface CriterionGroup {
+  requirement: string; // Clause of the report's profile, or the criterion when out of scope
+  in_scope: boolean; // Whether the report's profile includes the requirement
+  criterion: string;
+  title: string;
+  level: string;
+  principle: string;
+  url: string; // WCAG Understanding document
+  rules: string[];
+  count: number;
+}
+
+export type CriterionStatus =
+  | 'passed'
+  | 'failed'
+  | 'not_applicable'
+  | 'needs_manual' // No automated rule can decide the criterion
+  | 'not_tested' // Rules exist but none of them ran
+  | 'needs_review'; // Automated rules passed, but only a reviewer can confirm the criterion
+
+export interface CriterionResult {
+  ID: string; // UUID from Base
+  report_id: string; // UUID
+  criterion: string; // WCAG criterion, or the clause of an addition of the profile
+  requirement: string; // Clause of the report's profile
+  level: string;
+  status: CriterionStatus;
+  source: 'automated' | 'manual';
+  automatable: boolean;
+  rules: string[];
+  passes: number;
+  violations: number;
+}
+
+export type ManualResult = 'pending' | 'passed' | 'failed' | 'not_applicable';
+
+export interface ManualEvidence {
+  ID: string; // UUID from Base
+  check_id: string;
+  file_name: string;
+  content_type: string;
+  size: number;
+  description: string;
+  uploaded_by: string;
+  CreatedAt: string;
+}
+
+export interface ManualCheckChange {
+  ID: string; // UUID from Base
+  check_id: string;
+  user_id: string;
+  field: 'result' | 'notes' | 'evidence';
+  old_value: string;
+  new_value: string;
+  CreatedAt: string;
+}
+
+// A criterion automated testing could not decide, or whose automated pass needs review
+export interface ManualCheck {
+  ID: string; // UUID from Base
+  report_id: string;
+  criterion: string;
+  requirement: string;
+  level: string;
+  automated_status: CriterionStatus;
+  result: ManualResult;
+  notes: string;
+  auditor_id?: string;
+  evidence?: ManualEvidence[];
+  UpdatedAt: string;
+}
+
 export interface ComplianceReportItem {
   ID: string; // UUID from Base
   project_id: string; // UUID
   url: string;
   generated_at: string; // ISO Date string
+  profile: string; // Standards profile ID, e.g. "wcag22-aa"
   overall_score: number;
-  level_a_score: number;
-  level_aa_score: number;
-  level_aaa_score: number;
-  perceivable_score: number;
-  operable_score: number;
-  understandable_score: number;
-  robust_score: number;
+  scoring_method: ScoringMethod;
+  pass_counts: Record<string, number>; // Passed elements by the success criteria of their rule, separated by spaces
+  conformance_level: 'A' | 'AA' | 'AAA' | 'none';
+  conforms: boolean; // Every requirement of the profile passed or does not apply
+  // Level and principle scores are null when there is nothing to score
+  level_a_score: number | null;
+  level_aa_score: number | null;
+  level_aaa_score: number | null;
+  perceivable_score: number | null;
+  operable_score: number | null;
+  understandable_score: number | null;
+  robust_score: number | null;
   violations: ComplianceViolationItem[];
+  criteria: CriterionGroup[]; // Violations grouped by requirement of the profile
+  results: CriterionResult[]; // Every requirement of the profile in its order
+  checklist?: ManualCheck[]; // Manual audit checklist
   CreatedAt: string; // ISO Date string from Base
   UpdatedAt: string; // ISO Date string from Base
-} 
\ No newline at end of file
+} 
+export interface StandardsRequirement {
+  id: string; // Clause number in the standard
+  title: string;
+  criterion?: string; // Incorporated WCAG success criterion
+  level?: string;
+  principle: string;
+  rules?: string[];
+}
+
+export interface StandardsProfileSummary {
+  id: string;
+  name: string;
+  description: string;
+  level: string;
+  wcag_version: string; // WCAG version the profile incorporates
+  requirements: number;
+  default: boolean;
+}
+
+export interface StandardsProfile extends Omit<StandardsProfileSummary, 'requirements' | 'default'> {
+  requirements: StandardsRequirement[];
+}
+
+export type ACRConformance =
+  | 'Supports'
+  | 'Partially Supports'
+  | 'Does Not Support'
+  | 'Not Applicable'
+  | 'Not Evaluated';
+
+export interface ACRRow {
+  id: string; // Key remarks are stored under, e.g. "1.1.1" or "302.1"
+  criterion: string;
+  also_applies_to?: string[];
+  conformance: ACRConformance;
+  remarks: string;
+  edited: boolean; // Remarks were written by a reviewer
+}
+
+export interface ACRTable {
+  standard: string; // wcag, or the profile of the standard's own tables
+  title: string;
+  notes?: string;
+  rows: ACRRow[];
+}
+
+export interface AccessibilityConformanceReport {
+  product: string;
+  description: string;
+  url: string;
+  date: string; // ISO Date string
+  evaluation_methods: string;
+  profile: string; // Standards profile the report was measured against
+  wcag_version: string;
+  standards: string[]; // Applicable standards and guidelines
+  conformance_level: string;
+  tables: ACRTable[];
+}
@@ -1,3 +1,6 @@
+// How scans and reports of a project are scored
+export type ScoringMethod = 'impact_weighted' | 'pass_ratio' | 'criteria';
+
 export interface Project {
   ID: string;
   CreatedAt: string;
@@ -11,6 +14,8 @@ export interface Project {
   last_scan: string;
   score: number;
   status: 'active' | 'archived';
+  default_profile: string; // Standards profile ID
+  scoring_method: ScoringMethod;
 }
 
 export interface CreateProjectInput {
@@ -18,8 +23,31 @@ export interface CreateProjectInput {
   name: string;
   description?: string;
   url?: string;
+  default_profile?: string;
+  scoring_method?: ScoringMethod;
 }
 
 export interface UpdateProjectInput extends CreateProjectInput {
   status?: 'active' | 'archived';
-} 
\ No newline at end of file
+} 
+
+// Stored secrets (header and cookie values, password, token) are returned as
+// this mask. Sending the mask back keeps the stored value.
+export const SECRET_MASK = '********';
+
+// How a project's site is fetched, e.g. to scan a staging site behind a login.
+// Headers, cookies and auth are only sent to the host of the project URL.
+export interface FetchSettings {
+  timeout_seconds?: number; // 0 uses the default of 30, at most 120
+  max_redirects?: number; // 0 uses the default of 10, at most 20
+  max_body_mb?: number; // 0 uses the default of 10, at most 50
+  headers?: Record<string, string>;
+  cookies?: Record<string, string>;
+  auth_type?: 'basic' | 'bearer';
+  username?: string;
+  password?: string;
+  token?: string;
+  // Set when stored secrets could not be decrypted on the server and must be
+  // entered again
+  secrets_unavailable?: boolean;
+}